@@ -0,0 +1,182 @@
+// api/bounces.go
+package api
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"notification-service/bounces"
+)
+
+// maxBounceBody caps inbound DSN messages and webhook batches.
+const maxBounceBody = 10 << 20
+
+// suppressRequest is the body of PUT /v1/email/suppressions/{email}.
+type suppressRequest struct {
+	Reason    string    `json:"reason"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// bounceResponse reports what an inbound bounce report did.
+type bounceResponse struct {
+	Bounces    []bounces.Bounce `json:"bounces"`
+	Suppressed int              `json:"suppressed"`
+}
+
+// HandleBounces adds the bounce/complaint intake routes:
+//
+//	POST   /v1/email/bounces/dsn        raw RFC 3464 DSN or RFC 5965 ARF message
+//	POST   /v1/email/bounces/ses        Amazon SES notification (SNS or raw)
+//	POST   /v1/email/bounces/sendgrid   SendGrid Event Webhook batch
+//
+// Requests must carry webhookToken in the X-Webhook-Token header or the token
+// query parameter; an empty webhookToken rejects every request.
+func (s *Server) HandleBounces(store bounces.Store, policy bounces.Policy, webhookToken string) {
+	intake := func(parse func([]byte) ([]bounces.Bounce, error)) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if !validWebhookToken(r, webhookToken) {
+				writeError(w, http.StatusUnauthorized, "invalid webhook token")
+				return
+			}
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBounceBody))
+			if err != nil {
+				writeError(w, http.StatusRequestEntityTooLarge, "body too large")
+				return
+			}
+
+			reported, err := parse(body)
+			var confirmation *bounces.SubscriptionConfirmation
+			if errors.As(err, &confirmation) {
+				log.Printf("SNS subscription to %s needs confirmation; visit %s", confirmation.TopicARN, confirmation.URL)
+				writeJSON(w, http.StatusAccepted, map[string]string{"status": "subscription confirmation logged"})
+				return
+			}
+			if err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			now := time.Now().UTC()
+			resp := bounceResponse{Bounces: reported}
+			if resp.Bounces == nil {
+				resp.Bounces = []bounces.Bounce{}
+			}
+			for _, b := range reported {
+				entry, ok := policy.Suppression(b, now)
+				if !ok {
+					log.Printf("Recorded %s for %s from %s without suppressing (Status: %s)", b.Kind, b.Email, b.Source, b.Status)
+					continue
+				}
+				if _, err := store.Add(entry); err != nil {
+					log.Printf("Failed to suppress %s: %v", b.Email, err)
+					writeError(w, http.StatusInternalServerError, "failed to update suppression list")
+					return
+				}
+				log.Printf("Suppressed %s after %s from %s (Status: %s)", b.Email, b.Kind, b.Source, b.Status)
+				resp.Suppressed++
+			}
+			writeJSON(w, http.StatusOK, resp)
+		}
+	}
+
+	s.mux.HandleFunc("POST /v1/email/bounces/dsn", intake(func(body []byte) ([]bounces.Bounce, error) {
+		return bounces.ParseReport(bytes.NewReader(body))
+	}))
+	s.mux.HandleFunc("POST /v1/email/bounces/ses", intake(bounces.ParseSES))
+	s.mux.HandleFunc("POST /v1/email/bounces/sendgrid", intake(bounces.ParseSendGrid))
+}
+
+// HandleEmailSuppressions adds the suppression list routes:
+//
+//	GET    /v1/email/suppressions
+//	GET    /v1/email/suppressions/{email}
+//	PUT    /v1/email/suppressions/{email}
+//	DELETE /v1/email/suppressions/{email}
+//
+// Requests must carry one of adminKeys.
+func (s *Server) HandleEmailSuppressions(store bounces.Store, adminKeys []string) {
+	s.mux.HandleFunc("GET /v1/email/suppressions", requireAPIKey(adminKeys, func(w http.ResponseWriter, r *http.Request) {
+		list, err := store.List()
+		if err != nil {
+			log.Printf("Failed to list email suppressions: %v", err)
+			writeError(w, http.StatusInternalServerError, "failed to list suppressions")
+			return
+		}
+		if list == nil {
+			list = []bounces.Suppression{}
+		}
+		writeJSON(w, http.StatusOK, list)
+	}))
+
+	s.mux.HandleFunc("GET /v1/email/suppressions/{email}", requireAPIKey(adminKeys, func(w http.ResponseWriter, r *http.Request) {
+		entry, err := store.Get(r.PathValue("email"))
+		if errors.Is(err, bounces.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "address not suppressed")
+			return
+		}
+		if err != nil {
+			log.Printf("Failed to load email suppression: %v", err)
+			writeError(w, http.StatusInternalServerError, "failed to load suppression")
+			return
+		}
+		writeJSON(w, http.StatusOK, entry)
+	}))
+
+	s.mux.HandleFunc("PUT /v1/email/suppressions/{email}", requireAPIKey(adminKeys, func(w http.ResponseWriter, r *http.Request) {
+		var req suppressRequest
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				writeError(w, http.StatusBadRequest, "invalid JSON body")
+				return
+			}
+		}
+		if req.Reason == "" {
+			req.Reason = bounces.KindManual
+		}
+
+		saved, err := store.Add(bounces.Suppression{
+			Email:     r.PathValue("email"),
+			Reason:    req.Reason,
+			Source:    bounces.SourceAPI,
+			ExpiresAt: req.ExpiresAt,
+		})
+		if err != nil {
+			log.Printf("Failed to save email suppression: %v", err)
+			writeError(w, http.StatusInternalServerError, "failed to save suppression")
+			return
+		}
+		writeJSON(w, http.StatusOK, saved)
+	}))
+
+	s.mux.HandleFunc("DELETE /v1/email/suppressions/{email}", requireAPIKey(adminKeys, func(w http.ResponseWriter, r *http.Request) {
+		err := store.Remove(r.PathValue("email"))
+		if errors.Is(err, bounces.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "address not suppressed")
+			return
+		}
+		if err != nil {
+			log.Printf("Failed to remove email suppression: %v", err)
+			writeError(w, http.StatusInternalServerError, "failed to remove suppression")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+}
+
+// validWebhookToken checks the shared secret configured for bounce webhooks.
+func validWebhookToken(r *http.Request, want string) bool {
+	if want == "" {
+		return false
+	}
+	got := r.Header.Get("X-Webhook-Token")
+	if got == "" {
+		got = r.URL.Query().Get("token")
+	}
+	return subtle.ConstantTimeCompare([]byte(got), []byte(want)) == 1
+}
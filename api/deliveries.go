@@ -0,0 +1,115 @@
+// api/deliveries.go
+package api
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"notification-service/deliverylog"
+)
+
+// HandleDeliveries adds the delivery log query routes:
+//
+//	GET /v1/deliveries/messages?user_id=&application_id=&opportunity_id=&event_id=&since=&until=&limit=
+//	GET /v1/deliveries/attempts?user_id=&application_id=&opportunity_id=&event_id=&since=&until=&limit=
+//	GET /v1/deliveries/engagements?user_id=&application_id=&opportunity_id=&event_id=&since=&until=&limit=
+//
+// since and until are RFC 3339 timestamps. Requests must carry one of adminKeys.
+func (s *Server) HandleDeliveries(store deliverylog.Store, adminKeys []string) {
+	s.mux.HandleFunc("GET /v1/deliveries/messages", requireAPIKey(adminKeys, func(w http.ResponseWriter, r *http.Request) {
+		q, err := parseDeliveryQuery(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		messages, err := store.QueryMessages(r.Context(), q)
+		if err != nil {
+			log.Printf("Failed to query delivery log messages: %v", err)
+			writeError(w, http.StatusInternalServerError, "failed to query delivery log")
+			return
+		}
+		if messages == nil {
+			messages = []deliverylog.Message{}
+		}
+		writeJSON(w, http.StatusOK, messages)
+	}))
+
+	s.mux.HandleFunc("GET /v1/deliveries/attempts", requireAPIKey(adminKeys, func(w http.ResponseWriter, r *http.Request) {
+		q, err := parseDeliveryQuery(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		attempts, err := store.QueryAttempts(r.Context(), q)
+		if err != nil {
+			log.Printf("Failed to query delivery log attempts: %v", err)
+			writeError(w, http.StatusInternalServerError, "failed to query delivery log")
+			return
+		}
+		if attempts == nil {
+			attempts = []deliverylog.Attempt{}
+		}
+		writeJSON(w, http.StatusOK, attempts)
+	}))
+
+	s.mux.HandleFunc("GET /v1/deliveries/engagements", requireAPIKey(adminKeys, func(w http.ResponseWriter, r *http.Request) {
+		q, err := parseDeliveryQuery(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		engagements, err := store.QueryEngagements(r.Context(), q)
+		if err != nil {
+			log.Printf("Failed to query delivery log engagements: %v", err)
+			writeError(w, http.StatusInternalServerError, "failed to query delivery log")
+			return
+		}
+		if engagements == nil {
+			engagements = []deliverylog.Engagement{}
+		}
+		writeJSON(w, http.StatusOK, engagements)
+	}))
+}
+
+// parseDeliveryQuery reads delivery log filters from the query string.
+func parseDeliveryQuery(r *http.Request) (deliverylog.Query, error) {
+	values := r.URL.Query()
+	q := deliverylog.Query{
+		EventID: values.Get("event_id"),
+		UserID:  values.Get("user_id"),
+	}
+
+	ints := map[string]*int{
+		"application_id": &q.ApplicationID,
+		"opportunity_id": &q.OpportunityID,
+		"limit":          &q.Limit,
+	}
+	for name, dest := range ints {
+		if raw := values.Get(name); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil {
+				return q, fmt.Errorf("%s must be an integer", name)
+			}
+			*dest = n
+		}
+	}
+
+	times := map[string]*time.Time{
+		"since": &q.Since,
+		"until": &q.Until,
+	}
+	for name, dest := range times {
+		if raw := values.Get(name); raw != "" {
+			t, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				return q, fmt.Errorf("%s must be an RFC 3339 timestamp", name)
+			}
+			*dest = t
+		}
+	}
+
+	return q, nil
+}
@@ -0,0 +1,157 @@
+// api/dlq.go
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"notification-service/dlq"
+	"notification-service/envelope"
+	"notification-service/handlers"
+)
+
+// HandleDLQ adds the authenticated dead letter queue admin routes:
+//
+//	GET  /v1/admin/dlq?type=&routing_key=&queue=&reason=&id=&limit=100   list messages
+//	POST /v1/admin/dlq/replay        replay messages selected by a dlq.Filter body
+//	POST /v1/admin/dlq/{id}/replay   replay one message, optionally with an edited body of any supported schema version
+//	POST /v1/admin/dlq/purge         delete messages selected by a dlq.Filter body
+//	GET  /v1/admin/dlq/audit?limit=100
+//
+// Requests must carry one of adminKeys as a bearer token or in the X-API-Key header.
+// The X-Actor header names the operator in the audit log, next to the label of the
+// admin key the request was made with.
+func (s *Server) HandleDLQ(manager *dlq.Manager, adminKeys []string) {
+	s.mux.HandleFunc("GET /v1/admin/dlq", requireAPIKey(adminKeys, func(w http.ResponseWriter, r *http.Request) {
+		limit, ok := limitParam(w, r, 100)
+		if !ok {
+			return
+		}
+		query := r.URL.Query()
+		filter := dlq.Filter{
+			IDs:              query["id"],
+			NotificationType: query.Get("type"),
+			RoutingKey:       query.Get("routing_key"),
+			Queue:            query.Get("queue"),
+			Reason:           query.Get("reason"),
+		}
+
+		entries, err := manager.List(filter, limit, actor(r))
+		if err != nil {
+			log.Printf("Failed to list dead-lettered messages: %v", err)
+			writeError(w, http.StatusBadGateway, "failed to read dead letter queue")
+			return
+		}
+		writeJSON(w, http.StatusOK, entries)
+	}))
+
+	s.mux.HandleFunc("POST /v1/admin/dlq/replay", requireAPIKey(adminKeys, func(w http.ResponseWriter, r *http.Request) {
+		var filter dlq.Filter
+		if err := json.NewDecoder(r.Body).Decode(&filter); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid JSON body")
+			return
+		}
+		result, err := manager.Replay(r.Context(), filter, actor(r))
+		writeDLQResult(w, result, err)
+	}))
+
+	s.mux.HandleFunc("POST /v1/admin/dlq/{id}/replay", requireAPIKey(adminKeys, func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxMessageBody))
+		if err != nil {
+			writeError(w, http.StatusRequestEntityTooLarge, "body too large")
+			return
+		}
+
+		if len(strings.TrimSpace(string(body))) == 0 {
+			result, err := manager.Replay(r.Context(), dlq.Filter{IDs: []string{id}}, actor(r))
+			if err == nil && result.Count == 0 {
+				err = dlq.ErrNotFound
+			}
+			writeDLQResult(w, result, err)
+			return
+		}
+
+		if err := handlers.ValidateNotificationBody(body); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+		if _, _, err := envelope.Decode(body); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		err = manager.ReplayEdited(r.Context(), id, body, actor(r))
+		writeDLQResult(w, dlq.Result{Count: 1, IDs: []string{id}}, err)
+	}))
+
+	s.mux.HandleFunc("POST /v1/admin/dlq/purge", requireAPIKey(adminKeys, func(w http.ResponseWriter, r *http.Request) {
+		var filter dlq.Filter
+		if err := json.NewDecoder(r.Body).Decode(&filter); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid JSON body")
+			return
+		}
+		result, err := manager.Purge(filter, actor(r))
+		writeDLQResult(w, result, err)
+	}))
+
+	s.mux.HandleFunc("GET /v1/admin/dlq/audit", requireAPIKey(adminKeys, func(w http.ResponseWriter, r *http.Request) {
+		limit, ok := limitParam(w, r, 100)
+		if !ok {
+			return
+		}
+		entries, err := manager.Audit(limit)
+		if err != nil {
+			log.Printf("Failed to list dead letter queue audit log: %v", err)
+			writeError(w, http.StatusInternalServerError, "failed to list audit log")
+			return
+		}
+		writeJSON(w, http.StatusOK, entries)
+	}))
+}
+
+// writeDLQResult writes the result of a replay or purge, mapping dlq errors to statuses.
+// A partial replay reports the messages that were replayed alongside the error.
+func writeDLQResult(w http.ResponseWriter, result dlq.Result, err error) {
+	switch {
+	case err == nil:
+		writeJSON(w, http.StatusOK, result)
+	case errors.Is(err, dlq.ErrEmptyFilter), errors.Is(err, dlq.ErrInvalidMessage):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, dlq.ErrNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	default:
+		log.Printf("Dead letter queue operation failed: %v", err)
+		writeJSON(w, http.StatusBadGateway, struct {
+			dlq.Result
+			Error string `json:"error"`
+		}{result, err.Error()})
+	}
+}
+
+// actor identifies the operator making an admin request.
+func actor(r *http.Request) dlq.Actor {
+	name := r.Header.Get("X-Actor")
+	if name == "" {
+		name = "unknown"
+	}
+	return dlq.Actor{Name: name, Key: requestKeyLabel(r), RemoteAddr: r.RemoteAddr}
+}
+
+// limitParam parses the limit query parameter, writing a 400 response if it is invalid.
+func limitParam(w http.ResponseWriter, r *http.Request, fallback int) (int, bool) {
+	raw := r.URL.Query().Get("limit")
+	if raw == "" {
+		return fallback, true
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n <= 0 {
+		writeError(w, http.StatusBadRequest, "limit must be a positive integer")
+		return 0, false
+	}
+	return n, true
+}
@@ -0,0 +1,27 @@
+// api/health.go
+package api
+
+import (
+	"net/http"
+
+	"notification-service/health"
+)
+
+// HandleHealth adds the probe routes:
+//
+//	GET /healthz   200 while the process is serving requests
+//	GET /readyz    200 when every dependency is ok or degraded, 503 otherwise; JSON detail per dependency
+func (s *Server) HandleHealth(checker *health.Checker) {
+	s.mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": health.StatusOK})
+	})
+
+	s.mux.HandleFunc("GET /readyz", func(w http.ResponseWriter, r *http.Request) {
+		report := checker.Run(r.Context())
+		status := http.StatusOK
+		if !report.Ready() {
+			status = http.StatusServiceUnavailable
+		}
+		writeJSON(w, status, report)
+	})
+}
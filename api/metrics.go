@@ -0,0 +1,11 @@
+// api/metrics.go
+package api
+
+import "notification-service/metrics"
+
+// HandleMetrics adds the Prometheus scrape endpoint:
+//
+//	GET /metrics
+func (s *Server) HandleMetrics() {
+	s.mux.Handle("GET /metrics", metrics.Handler())
+}
@@ -0,0 +1,171 @@
+// api/notifications.go
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"notification-service/deliverylog"
+	"notification-service/envelope"
+	"notification-service/handlers"
+	"notification-service/models"
+	"notification-service/rabbitmq"
+	"notification-service/schema"
+)
+
+// SourceHTTP is recorded as the queue of notifications processed synchronously over HTTP.
+const SourceHTTP = "http"
+
+// NotificationProcessor processes a notification in-process, as the RabbitMQ consumer would.
+type NotificationProcessor interface {
+	ProcessNotification(ctx context.Context, msg models.NotificationMessage, source string) (string, error)
+}
+
+// NotificationPublisher enqueues a notification onto the exchange with its type's routing key.
+type NotificationPublisher interface {
+	PublishNotification(ctx context.Context, msg models.NotificationMessage) (string, error)
+}
+
+// submitResponse is returned by POST /v1/notifications.
+type submitResponse struct {
+	ID     string `json:"id"`
+	Status string `json:"status"` // "queued", or the delivery log status when processed synchronously
+	Error  string `json:"error,omitempty"`
+}
+
+// maxMessageBody caps a submitted or edited notification message.
+const maxMessageBody = 1 << 20
+
+// HandleNotifications adds the authenticated notification submission routes:
+//
+//	POST /v1/notifications?mode=async|sync   submit a message of any supported envelope schema version
+//	GET  /v1/notifications/{id}              processing status and channel attempts
+//
+// async (the default) enqueues the message onto the exchange; sync processes it through
+// the handler before responding. Requests must carry one of apiKeys as a bearer token
+// or in the X-API-Key header.
+func (s *Server) HandleNotifications(processor NotificationProcessor, publisher NotificationPublisher, store deliverylog.Store, apiKeys []string) {
+	s.mux.HandleFunc("POST /v1/notifications", requireAPIKey(apiKeys, func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxMessageBody))
+		if err != nil {
+			writeError(w, http.StatusRequestEntityTooLarge, "body too large")
+			return
+		}
+		if err := handlers.ValidateNotificationBody(body); err != nil {
+			writeValidationError(w, err)
+			return
+		}
+		msg, _, err := envelope.Decode(body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if msg.EventID == "" {
+			msg.EventID = rabbitmq.NewMessageID()
+		}
+
+		mode := r.URL.Query().Get("mode")
+		switch mode {
+		case "", "async":
+			if publisher == nil {
+				writeError(w, http.StatusServiceUnavailable, "asynchronous submission is not available; use mode=sync")
+				return
+			}
+			ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+			defer cancel()
+			id, err := publisher.PublishNotification(ctx, msg)
+			if err != nil {
+				log.Printf("Failed to enqueue notification %s: %v", msg.EventID, err)
+				writeError(w, http.StatusBadGateway, "failed to enqueue notification")
+				return
+			}
+			writeJSON(w, http.StatusAccepted, submitResponse{ID: id, Status: "queued"})
+
+		case "sync":
+			status, err := processor.ProcessNotification(r.Context(), msg, SourceHTTP)
+			if err != nil {
+				writeJSON(w, http.StatusBadGateway, submitResponse{ID: msg.EventID, Status: status, Error: err.Error()})
+				return
+			}
+			writeJSON(w, http.StatusOK, submitResponse{ID: msg.EventID, Status: status})
+
+		default:
+			writeError(w, http.StatusBadRequest, "mode must be async or sync")
+		}
+	}))
+
+	s.mux.HandleFunc("GET /v1/notifications/{id}", requireAPIKey(apiKeys, func(w http.ResponseWriter, r *http.Request) {
+		summary, err := deliverylog.Summarize(r.Context(), store, r.PathValue("id"))
+		if errors.Is(err, deliverylog.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "notification not found; it may still be queued")
+			return
+		}
+		if err != nil {
+			log.Printf("Failed to look up notification status: %v", err)
+			writeError(w, http.StatusInternalServerError, "failed to look up notification")
+			return
+		}
+		writeJSON(w, http.StatusOK, summary)
+	}))
+}
+
+// validationErrorResponse is returned for a message that does not match its schema.
+type validationErrorResponse struct {
+	Error  string              `json:"error"`
+	Fields []schema.FieldError `json:"fields,omitempty"`
+}
+
+// writeValidationError writes a 400 response, listing the invalid fields of a schema mismatch.
+func writeValidationError(w http.ResponseWriter, err error) {
+	resp := validationErrorResponse{Error: err.Error()}
+	var verr *schema.ValidationError
+	if errors.As(err, &verr) {
+		resp.Fields = verr.Fields
+	}
+	writeJSON(w, http.StatusBadRequest, resp)
+}
+
+// apiKeyContextKey holds the label of the API key a request was authenticated with.
+type apiKeyContextKey struct{}
+
+// keyLabel identifies an API key without revealing it: the first 12 hex digits of
+// its SHA-256, e.g. "sha256:3f9a1c0b7d2e".
+func keyLabel(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return "sha256:" + hex.EncodeToString(sum[:])[:12]
+}
+
+// requestKeyLabel returns the label of the key requireAPIKey accepted for r.
+func requestKeyLabel(r *http.Request) string {
+	label, _ := r.Context().Value(apiKeyContextKey{}).(string)
+	return label
+}
+
+// requireAPIKey rejects requests without one of the keys, sent as
+// "Authorization: Bearer <key>" or "X-API-Key: <key>".
+func requireAPIKey(keys []string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		got := r.Header.Get("X-API-Key")
+		if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+			got = bearer
+		}
+		if got != "" {
+			for _, key := range keys {
+				if subtle.ConstantTimeCompare([]byte(got), []byte(key)) == 1 {
+					next(w, r.WithContext(context.WithValue(r.Context(), apiKeyContextKey{}, keyLabel(key))))
+					return
+				}
+			}
+		}
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeError(w, http.StatusUnauthorized, "missing or invalid API key")
+	}
+}
@@ -0,0 +1,23 @@
+// api/notifications_test.go
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSubmitRejectsOversizedBody(t *testing.T) {
+	s := NewServer("")
+	s.HandleNotifications(nil, nil, nil, []string{"test-key"})
+
+	body := `{"notification_type":"` + strings.Repeat("x", maxMessageBody) + `"}`
+	req := httptest.NewRequest(http.MethodPost, "/v1/notifications", strings.NewReader(body))
+	req.Header.Set("X-API-Key", "test-key")
+	rec := httptest.NewRecorder()
+	s.mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("POST of %d bytes = %d, want %d", len(body), rec.Code, http.StatusRequestEntityTooLarge)
+	}
+}
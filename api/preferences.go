@@ -0,0 +1,174 @@
+// api/preferences.go
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"notification-service/digest"
+	"notification-service/preferences"
+	"notification-service/scheduler"
+)
+
+// setPreferenceRequest is the body of PUT .../preferences/channels/{channel}
+// and PUT .../preferences/types/{notificationType}/{channel}.
+type setPreferenceRequest struct {
+	Enabled *bool `json:"enabled"`
+}
+
+// setDigestRequest is the body of PUT .../preferences/digest/{notificationType}.
+type setDigestRequest struct {
+	Mode string `json:"mode"`
+}
+
+// HandlePreferences adds the recipient preference routes:
+//
+//	GET    /v1/users/{userID}/preferences
+//	PUT    /v1/users/{userID}/preferences
+//	DELETE /v1/users/{userID}/preferences
+//	PUT    /v1/users/{userID}/preferences/channels/{channel}
+//	PUT    /v1/users/{userID}/preferences/types/{notificationType}/{channel}
+//	PUT    /v1/users/{userID}/preferences/digest/{notificationType}
+//	PUT    /v1/users/{userID}/preferences/tracking
+//
+// Requests must carry one of apiKeys, so callers cannot change other users' settings.
+func (s *Server) HandlePreferences(store preferences.Store, apiKeys []string) {
+	s.mux.HandleFunc("GET /v1/users/{userID}/preferences", requireAPIKey(apiKeys, func(w http.ResponseWriter, r *http.Request) {
+		p, err := store.Get(r.PathValue("userID"))
+		if errors.Is(err, preferences.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "preferences not found")
+			return
+		}
+		if err != nil {
+			log.Printf("Failed to load preferences: %v", err)
+			writeError(w, http.StatusInternalServerError, "failed to load preferences")
+			return
+		}
+		writeJSON(w, http.StatusOK, p)
+	}))
+
+	s.mux.HandleFunc("PUT /v1/users/{userID}/preferences", requireAPIKey(apiKeys, func(w http.ResponseWriter, r *http.Request) {
+		var p preferences.Preferences
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid JSON body")
+			return
+		}
+		for channel := range p.Channels {
+			if !preferences.ValidChannel(channel) {
+				writeError(w, http.StatusBadRequest, "unknown channel: "+channel)
+				return
+			}
+		}
+		for _, channels := range p.Types {
+			for channel := range channels {
+				if !preferences.ValidChannel(channel) {
+					writeError(w, http.StatusBadRequest, "unknown channel: "+channel)
+					return
+				}
+			}
+		}
+
+		for _, mode := range p.Digest {
+			if !digest.ValidMode(mode) {
+				writeError(w, http.StatusBadRequest, "unknown digest mode: "+mode)
+				return
+			}
+		}
+		if p.QuietHours != nil {
+			if err := scheduler.ValidateQuietHours(*p.QuietHours, p.Timezone); err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		} else if _, err := time.LoadLocation(p.Timezone); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid timezone: "+p.Timezone)
+			return
+		}
+
+		p.UserID = r.PathValue("userID")
+		saved, err := store.Put(p)
+		if err != nil {
+			log.Printf("Failed to save preferences: %v", err)
+			writeError(w, http.StatusInternalServerError, "failed to save preferences")
+			return
+		}
+		writeJSON(w, http.StatusOK, saved)
+	}))
+
+	s.mux.HandleFunc("DELETE /v1/users/{userID}/preferences", requireAPIKey(apiKeys, func(w http.ResponseWriter, r *http.Request) {
+		err := store.Delete(r.PathValue("userID"))
+		if errors.Is(err, preferences.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "preferences not found")
+			return
+		}
+		if err != nil {
+			log.Printf("Failed to delete preferences: %v", err)
+			writeError(w, http.StatusInternalServerError, "failed to delete preferences")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	setPreference := func(w http.ResponseWriter, r *http.Request, notificationType string) {
+		channel := r.PathValue("channel")
+		if !preferences.ValidChannel(channel) {
+			writeError(w, http.StatusBadRequest, "unknown channel: "+channel)
+			return
+		}
+		var req setPreferenceRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Enabled == nil {
+			writeError(w, http.StatusBadRequest, "body must be {\"enabled\": true|false}")
+			return
+		}
+
+		saved, err := store.Set(r.PathValue("userID"), notificationType, channel, *req.Enabled)
+		if err != nil {
+			log.Printf("Failed to save preference: %v", err)
+			writeError(w, http.StatusInternalServerError, "failed to save preference")
+			return
+		}
+		writeJSON(w, http.StatusOK, saved)
+	}
+
+	s.mux.HandleFunc("PUT /v1/users/{userID}/preferences/channels/{channel}", requireAPIKey(apiKeys, func(w http.ResponseWriter, r *http.Request) {
+		setPreference(w, r, "")
+	}))
+
+	s.mux.HandleFunc("PUT /v1/users/{userID}/preferences/types/{notificationType}/{channel}", requireAPIKey(apiKeys, func(w http.ResponseWriter, r *http.Request) {
+		setPreference(w, r, r.PathValue("notificationType"))
+	}))
+
+	s.mux.HandleFunc("PUT /v1/users/{userID}/preferences/digest/{notificationType}", requireAPIKey(apiKeys, func(w http.ResponseWriter, r *http.Request) {
+		var req setDigestRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || !digest.ValidMode(req.Mode) {
+			writeError(w, http.StatusBadRequest, "body must be {\"mode\": \"immediate\"|\"hourly\"|\"daily\"}")
+			return
+		}
+
+		saved, err := store.SetDigestMode(r.PathValue("userID"), r.PathValue("notificationType"), req.Mode)
+		if err != nil {
+			log.Printf("Failed to save digest mode: %v", err)
+			writeError(w, http.StatusInternalServerError, "failed to save digest mode")
+			return
+		}
+		writeJSON(w, http.StatusOK, saved)
+	}))
+
+	s.mux.HandleFunc("PUT /v1/users/{userID}/preferences/tracking", requireAPIKey(apiKeys, func(w http.ResponseWriter, r *http.Request) {
+		var req setPreferenceRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Enabled == nil {
+			writeError(w, http.StatusBadRequest, "body must be {\"enabled\": true|false}")
+			return
+		}
+
+		saved, err := store.SetTracking(r.PathValue("userID"), *req.Enabled)
+		if err != nil {
+			log.Printf("Failed to save tracking preference: %v", err)
+			writeError(w, http.StatusInternalServerError, "failed to save tracking preference")
+			return
+		}
+		writeJSON(w, http.StatusOK, saved)
+	}))
+}
@@ -0,0 +1,72 @@
+// api/queues.go
+package api
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"notification-service/rabbitmq"
+)
+
+// QueueController pauses and resumes the queues a consumer was started on; *rabbitmq.Consumer implements it.
+type QueueController interface {
+	Pause(queue string) error
+	Resume(queue string) error
+	Status() []rabbitmq.QueueStatus
+}
+
+// HandleQueues adds the authenticated routes controlling consumption per queue:
+//
+//	GET  /v1/admin/queues                  consumer status of every queue
+//	POST /v1/admin/queues/{queue}/pause    stop consuming the queue; its messages stay in the broker
+//	POST /v1/admin/queues/{queue}/resume   consume the queue again
+//
+// A paused queue stays paused across broker reconnections until it is resumed.
+//
+// ngo_email_queue and ngo_push_queue receive the same NGO events and send only email
+// and only push respectively, so pausing one of them, e.g. during an SMTP outage, stops
+// just that channel. Messages on volunteer_push_queue are sent on every channel.
+//
+// Requests must carry one of adminKeys as a bearer token or in the X-API-Key header.
+func (s *Server) HandleQueues(adminKeys []string, consumers ...QueueController) {
+	s.mux.HandleFunc("GET /v1/admin/queues", requireAPIKey(adminKeys, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, queueStatuses(consumers))
+	}))
+
+	control := func(action string, apply func(QueueController, string) error) http.HandlerFunc {
+		return requireAPIKey(adminKeys, func(w http.ResponseWriter, r *http.Request) {
+			queue := r.PathValue("queue")
+			for _, consumer := range consumers {
+				err := apply(consumer, queue)
+				if errors.Is(err, rabbitmq.ErrUnknownQueue) {
+					continue
+				}
+				log.Printf("Queue %s: %s requested by %s (%s)", queue, action, actor(r).Name, r.RemoteAddr)
+				if err != nil {
+					log.Printf("Failed to %s queue %s: %v", action, queue, err)
+					writeError(w, http.StatusBadGateway, err.Error())
+					return
+				}
+				for _, status := range consumer.Status() {
+					if status.Queue == queue {
+						writeJSON(w, http.StatusOK, status)
+					}
+				}
+				return
+			}
+			writeError(w, http.StatusNotFound, "no consumer for queue "+queue)
+		})
+	}
+	s.mux.HandleFunc("POST /v1/admin/queues/{queue}/pause", control("pause", QueueController.Pause))
+	s.mux.HandleFunc("POST /v1/admin/queues/{queue}/resume", control("resume", QueueController.Resume))
+}
+
+// queueStatuses returns the status of every queue the consumers supervise.
+func queueStatuses(consumers []QueueController) []rabbitmq.QueueStatus {
+	statuses := []rabbitmq.QueueStatus{}
+	for _, consumer := range consumers {
+		statuses = append(statuses, consumer.Status()...)
+	}
+	return statuses
+}
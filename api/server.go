@@ -0,0 +1,60 @@
+// api/server.go
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Server is the service's HTTP API. Feature routes are added with the Handle* methods
+// before Start is called.
+type Server struct {
+	mux        *http.ServeMux
+	httpServer *http.Server
+}
+
+// NewServer creates an HTTP API server listening on addr
+func NewServer(addr string) *Server {
+	mux := http.NewServeMux()
+	return &Server{
+		mux: mux,
+		httpServer: &http.Server{
+			Addr:              addr,
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		},
+	}
+}
+
+// Start serves HTTP requests in the background
+func (s *Server) Start() {
+	go func() {
+		log.Printf("HTTP API listening on %s", s.httpServer.Addr)
+		if err := s.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("HTTP API server stopped: %v", err)
+		}
+	}()
+}
+
+// Shutdown stops accepting requests and waits for in-flight ones to finish
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.httpServer.Shutdown(ctx)
+}
+
+// writeJSON writes v as a JSON response with the given status code.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Failed to write JSON response: %v", err)
+	}
+}
+
+// writeError writes a JSON error body: {"error": "..."}.
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
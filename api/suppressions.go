@@ -0,0 +1,40 @@
+// api/suppressions.go
+package api
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"notification-service/ratelimit"
+)
+
+// HandleSuppressions adds the route listing notifications suppressed by frequency capping:
+//
+//	GET /v1/users/{userID}/suppressed-notifications?limit=50
+//
+// Requests must carry one of adminKeys.
+func (s *Server) HandleSuppressions(suppressionLog ratelimit.SuppressionLog, adminKeys []string) {
+	s.mux.HandleFunc("GET /v1/users/{userID}/suppressed-notifications", requireAPIKey(adminKeys, func(w http.ResponseWriter, r *http.Request) {
+		limit := 50
+		if raw := r.URL.Query().Get("limit"); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil || n <= 0 {
+				writeError(w, http.StatusBadRequest, "limit must be a positive integer")
+				return
+			}
+			limit = n
+		}
+
+		list, err := suppressionLog.ListByUser(r.PathValue("userID"), limit)
+		if err != nil {
+			log.Printf("Failed to list suppressed notifications: %v", err)
+			writeError(w, http.StatusInternalServerError, "failed to list suppressed notifications")
+			return
+		}
+		if list == nil {
+			list = []ratelimit.Suppression{}
+		}
+		writeJSON(w, http.StatusOK, list)
+	}))
+}
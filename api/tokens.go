@@ -0,0 +1,83 @@
+// api/tokens.go
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"notification-service/tokens"
+)
+
+// registerTokenRequest is the body of POST /v1/users/{userID}/device-tokens.
+type registerTokenRequest struct {
+	DeviceToken  string `json:"device_token"`
+	Provider     string `json:"provider,omitempty"`
+	PlatformType string `json:"platform_type,omitempty"`
+}
+
+// HandleTokens adds the device token registry routes:
+//
+//	GET    /v1/users/{userID}/device-tokens
+//	POST   /v1/users/{userID}/device-tokens
+//	DELETE /v1/users/{userID}/device-tokens/{token}
+//
+// Requests must carry one of apiKeys, so callers cannot attach devices to other users.
+func (s *Server) HandleTokens(store tokens.Store, apiKeys []string) {
+	s.mux.HandleFunc("GET /v1/users/{userID}/device-tokens", requireAPIKey(apiKeys, func(w http.ResponseWriter, r *http.Request) {
+		list, err := store.ListByUser(r.PathValue("userID"))
+		if err != nil {
+			log.Printf("Failed to list device tokens: %v", err)
+			writeError(w, http.StatusInternalServerError, "failed to list device tokens")
+			return
+		}
+		if list == nil {
+			list = []tokens.Token{}
+		}
+		writeJSON(w, http.StatusOK, list)
+	}))
+
+	s.mux.HandleFunc("POST /v1/users/{userID}/device-tokens", requireAPIKey(apiKeys, func(w http.ResponseWriter, r *http.Request) {
+		var req registerTokenRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid JSON body")
+			return
+		}
+		if req.DeviceToken == "" {
+			writeError(w, http.StatusBadRequest, "device_token is required")
+			return
+		}
+		if !tokens.ValidProvider(req.Provider) {
+			writeError(w, http.StatusBadRequest, "provider must be 'fcm' or 'apns'")
+			return
+		}
+
+		t, err := store.Register(tokens.Token{
+			UserID:       r.PathValue("userID"),
+			Token:        req.DeviceToken,
+			Provider:     req.Provider,
+			PlatformType: req.PlatformType,
+		})
+		if err != nil {
+			log.Printf("Failed to register device token: %v", err)
+			writeError(w, http.StatusInternalServerError, "failed to register device token")
+			return
+		}
+		writeJSON(w, http.StatusCreated, t)
+	}))
+
+	s.mux.HandleFunc("DELETE /v1/users/{userID}/device-tokens/{token}", requireAPIKey(apiKeys, func(w http.ResponseWriter, r *http.Request) {
+		err := store.Unregister(r.PathValue("userID"), r.PathValue("token"))
+		if errors.Is(err, tokens.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "device token not found")
+			return
+		}
+		if err != nil {
+			log.Printf("Failed to unregister device token: %v", err)
+			writeError(w, http.StatusInternalServerError, "failed to unregister device token")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+}
@@ -0,0 +1,65 @@
+// api/tracking.go
+package api
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"notification-service/deliverylog"
+	"notification-service/tracking"
+)
+
+// transparentGIF is a 1x1 transparent GIF served for open tracking.
+var transparentGIF = []byte{
+	0x47, 0x49, 0x46, 0x38, 0x39, 0x61, 0x01, 0x00, 0x01, 0x00, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0xff, 0xff, 0xff, 0x21, 0xf9, 0x04, 0x01, 0x00, 0x00, 0x00, 0x00, 0x2c, 0x00, 0x00, 0x00, 0x00,
+	0x01, 0x00, 0x01, 0x00, 0x00, 0x02, 0x02, 0x44, 0x01, 0x00, 0x3b,
+}
+
+// HandleTracking adds the email open/click tracking routes:
+//
+//	GET /v1/track/open/{token}   1x1 pixel; records an open
+//	GET /v1/track/click/{token}  records a click and redirects to the signed URL
+func (s *Server) HandleTracking(tracker *tracking.Tracker, store deliverylog.Store) {
+	record := func(r *http.Request, claims tracking.Claims, kind string) {
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+		err := store.RecordEngagement(ctx, deliverylog.Engagement{
+			EventID:          claims.EventID,
+			NotificationType: claims.NotificationType,
+			UserID:           claims.UserID,
+			ApplicationID:    claims.ApplicationID,
+			OpportunityID:    claims.OpportunityID,
+			Kind:             kind,
+			URL:              claims.URL,
+			CreatedAt:        time.Now(),
+		})
+		if err != nil {
+			log.Printf("Failed to record %s for event %s: %v", kind, claims.EventID, err)
+		}
+	}
+
+	s.mux.HandleFunc("GET "+tracking.OpenPath+"{token}", func(w http.ResponseWriter, r *http.Request) {
+		// The pixel is served even for bad tokens so mail clients never show a broken image.
+		if claims, err := tracker.Verify(r.PathValue("token")); err == nil {
+			record(r, claims, deliverylog.EngagementOpen)
+		}
+		w.Header().Set("Content-Type", "image/gif")
+		w.Header().Set("Cache-Control", "no-store, max-age=0")
+		w.WriteHeader(http.StatusOK)
+		w.Write(transparentGIF)
+	})
+
+	s.mux.HandleFunc("GET "+tracking.ClickPath+"{token}", func(w http.ResponseWriter, r *http.Request) {
+		claims, err := tracker.Verify(r.PathValue("token"))
+		if err != nil || claims.URL == "" {
+			writeError(w, http.StatusNotFound, "unknown link")
+			return
+		}
+		record(r, claims, deliverylog.EngagementClick)
+		w.Header().Set("Cache-Control", "no-store")
+		http.Redirect(w, r, claims.URL, http.StatusFound)
+	})
+}
@@ -0,0 +1,60 @@
+// api/tracking_test.go
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"notification-service/deliverylog"
+	"notification-service/models"
+	"notification-service/tracking"
+)
+
+func TestClickRedirectOnlyFollowsSignedLinks(t *testing.T) {
+	store, err := deliverylog.Open(deliverylog.DriverSQLite, filepath.Join(t.TempDir(), "log.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	tracker, _ := tracking.NewTracker([]byte("0123456789abcdef"), "")
+	s := NewServer("")
+	s.HandleTracking(tracker, store)
+
+	msg := models.NotificationMessage{NotificationType: "OPPORTUNITY_UPDATED", EventID: "e1", Recipient: models.Recipient{UserID: "u1"}}
+	click := tracker.ClickURL(msg, "https://volhub.example.org/o/7")
+	token := strings.TrimPrefix(click, tracking.ClickPath)
+	encoded, _, _ := strings.Cut(token, ".")
+
+	tests := []struct {
+		name         string
+		path         string
+		wantStatus   int
+		wantLocation string
+	}{
+		{name: "signed link", path: click, wantStatus: http.StatusFound, wantLocation: "https://volhub.example.org/o/7"},
+		{name: "tampered token", path: tracking.ClickPath + encoded + ".AAAA", wantStatus: http.StatusNotFound},
+		{name: "unsigned URL", path: tracking.ClickPath + "https:%2F%2Fevil.example.com", wantStatus: http.StatusNotFound},
+		{name: "pixel token", path: strings.Replace(strings.TrimSuffix(tracker.PixelURL(msg), ".gif"), tracking.OpenPath, tracking.ClickPath, 1), wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			s.mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if rec.Code != tt.wantStatus || rec.Header().Get("Location") != tt.wantLocation {
+				t.Errorf("GET = %d Location %q, want %d %q", rec.Code, rec.Header().Get("Location"), tt.wantStatus, tt.wantLocation)
+			}
+		})
+	}
+
+	clicks, err := store.QueryEngagements(context.Background(), deliverylog.Query{EventID: "e1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(clicks) != 1 || clicks[0].URL != "https://volhub.example.org/o/7" {
+		t.Errorf("recorded %+v, want the one followed click", clicks)
+	}
+}
@@ -0,0 +1,100 @@
+// api/unsubscribe.go
+package api
+
+import (
+	"errors"
+	"html/template"
+	"log"
+	"net/http"
+
+	"notification-service/preferences"
+	"notification-service/unsubscribe"
+)
+
+// unsubscribePage is shown for both the confirmation step and the result.
+var unsubscribePage = template.Must(template.New("unsubscribe").Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: sans-serif; color: #222;">
+  <p>{{.Message}}</p>
+  {{if .Token}}<form method="post">
+    <input type="hidden" name="token" value="{{.Token}}">
+    <button type="submit">Unsubscribe</button>
+  </form>{{end}}
+</body>
+</html>
+`))
+
+type unsubscribeView struct {
+	Message string
+	Token   string // Set on the confirmation step only
+}
+
+// HandleUnsubscribe adds the email unsubscribe routes:
+//
+//	GET  /v1/unsubscribe?token=...  confirmation page (link scanners must not unsubscribe anyone)
+//	POST /v1/unsubscribe?token=...  RFC 8058 one-click unsubscribe, also used by the confirmation form
+func (s *Server) HandleUnsubscribe(signer *unsubscribe.Signer, store preferences.Store) {
+	s.mux.HandleFunc("GET "+unsubscribe.Path, func(w http.ResponseWriter, r *http.Request) {
+		token := r.URL.Query().Get("token")
+		claims, err := signer.Verify(token)
+		if err != nil {
+			writeUnsubscribePage(w, http.StatusBadRequest, unsubscribeView{Message: unsubscribeError(err)})
+			return
+		}
+		writeUnsubscribePage(w, http.StatusOK, unsubscribeView{
+			Message: "Stop receiving " + describeClaims(claims) + "?",
+			Token:   token,
+		})
+	})
+
+	s.mux.HandleFunc("POST "+unsubscribe.Path, func(w http.ResponseWriter, r *http.Request) {
+		// One-click clients post "List-Unsubscribe=One-Click" to the URL from the header,
+		// so the token is in the query; the confirmation form sends it in the body.
+		token := r.URL.Query().Get("token")
+		if token == "" {
+			token = r.PostFormValue("token")
+		}
+		claims, err := signer.Verify(token)
+		if err != nil {
+			writeUnsubscribePage(w, http.StatusBadRequest, unsubscribeView{Message: unsubscribeError(err)})
+			return
+		}
+
+		if _, err := store.Set(claims.UserID, claims.NotificationType, claims.Channel, false); err != nil {
+			log.Printf("Failed to unsubscribe %s: %v", claims.UserID, err)
+			writeUnsubscribePage(w, http.StatusInternalServerError, unsubscribeView{Message: "Something went wrong. Please try again later."})
+			return
+		}
+		log.Printf("Unsubscribed %s from %s (Type: %s)", claims.UserID, claims.Channel, claims.NotificationType)
+		writeUnsubscribePage(w, http.StatusOK, unsubscribeView{
+			Message: "You will no longer receive " + describeClaims(claims) + ".",
+		})
+	})
+}
+
+// describeClaims names what a token unsubscribes from, e.g. "VOLUNTEER_NEW_MATCHING_OPPORTUNITY emails".
+func describeClaims(claims unsubscribe.Claims) string {
+	what := claims.Channel + " notifications"
+	if claims.Channel == preferences.ChannelEmail {
+		what = "emails"
+	}
+	if claims.NotificationType != "" {
+		what = claims.NotificationType + " " + what
+	}
+	return what
+}
+
+func unsubscribeError(err error) string {
+	if errors.Is(err, unsubscribe.ErrExpiredToken) {
+		return "This unsubscribe link has expired. Please use the link in a more recent email or update your notification settings."
+	}
+	return "This unsubscribe link is not valid."
+}
+
+func writeUnsubscribePage(w http.ResponseWriter, status int, view unsubscribeView) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	if err := unsubscribePage.Execute(w, view); err != nil {
+		log.Printf("Failed to write unsubscribe page: %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+// api/unsubscribe_test.go
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"notification-service/preferences"
+	"notification-service/sqlstore"
+	"notification-service/unsubscribe"
+)
+
+func TestUnsubscribeOnlyOnPost(t *testing.T) {
+	db, err := sqlstore.Open(sqlstore.DriverSQLite, filepath.Join(t.TempDir(), "prefs.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	store, err := preferences.NewSQLStore(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, _ := unsubscribe.NewSigner([]byte("0123456789abcdef"), time.Hour)
+	s := NewServer("")
+	s.HandleUnsubscribe(signer, store)
+	token := signer.Sign("u1", "VOLUNTEER_NEW_MATCHING_OPPORTUNITY", preferences.ChannelEmail)
+	target := unsubscribe.Path + "?token=" + url.QueryEscape(token)
+
+	// Link scanners follow GET; it must only show the confirmation form.
+	rec := httptest.NewRecorder()
+	s.mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `<form method="post">`) {
+		t.Fatalf("GET = %d %q, want 200 with the confirmation form", rec.Code, rec.Body.String())
+	}
+	if _, err := store.Get("u1"); !errors.Is(err, preferences.ErrNotFound) {
+		t.Fatalf("preferences after GET: error = %v, want ErrNotFound", err)
+	}
+
+	// RFC 8058 one-click unsubscribe
+	req := httptest.NewRequest(http.MethodPost, target, strings.NewReader("List-Unsubscribe=One-Click"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec = httptest.NewRecorder()
+	s.mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("POST = %d %q, want 200", rec.Code, rec.Body.String())
+	}
+	p, err := store.Get("u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if enabled, ok := p.Lookup("VOLUNTEER_NEW_MATCHING_OPPORTUNITY", preferences.ChannelEmail); !ok || enabled {
+		t.Errorf("email for VOLUNTEER_NEW_MATCHING_OPPORTUNITY after POST = %v (set %v), want disabled", enabled, ok)
+	}
+
+	rec = httptest.NewRecorder()
+	s.mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, unsubscribe.Path+"?token=bogus", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("POST with an invalid token = %d, want 400", rec.Code)
+	}
+}
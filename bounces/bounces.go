@@ -0,0 +1,82 @@
+// bounces/bounces.go
+package bounces
+
+import (
+	"strings"
+	"time"
+)
+
+// Kinds of feedback about an email address.
+const (
+	KindHardBounce = "hard_bounce"
+	KindSoftBounce = "soft_bounce"
+	KindComplaint  = "complaint"
+	KindManual     = "manual"
+)
+
+// Sources a bounce or complaint was reported by.
+const (
+	SourceDSN      = "dsn"
+	SourceARF      = "arf"
+	SourceSES      = "ses"
+	SourceSendGrid = "sendgrid"
+	SourceAPI      = "api"
+)
+
+// Bounce is one bounce or complaint reported for a recipient address.
+type Bounce struct {
+	Email      string `json:"email"`
+	Kind       string `json:"kind"`
+	Source     string `json:"source"`
+	Status     string `json:"status,omitempty"`     // Enhanced status code, e.g. "5.1.1"
+	Diagnostic string `json:"diagnostic,omitempty"` // Remote MTA or provider explanation
+}
+
+// Policy decides how long each kind of bounce keeps an address suppressed.
+type Policy struct {
+	// SoftBounceTTL is how long a soft bounce (mailbox full, greylisting) suppresses
+	// an address. Zero means soft bounces are only logged.
+	SoftBounceTTL time.Duration
+}
+
+// Suppression turns a bounce into a suppression list entry. Hard bounces and
+// complaints never expire; soft bounces expire after SoftBounceTTL. The second
+// result is false when the bounce should not suppress the address.
+func (p Policy) Suppression(b Bounce, now time.Time) (Suppression, bool) {
+	s := Suppression{
+		Email:      NormalizeEmail(b.Email),
+		Reason:     b.Kind,
+		Source:     b.Source,
+		Status:     b.Status,
+		Diagnostic: b.Diagnostic,
+		CreatedAt:  now,
+	}
+	switch b.Kind {
+	case KindHardBounce, KindComplaint:
+		return s, s.Email != ""
+	case KindSoftBounce:
+		if p.SoftBounceTTL <= 0 {
+			return s, false
+		}
+		s.ExpiresAt = now.Add(p.SoftBounceTTL)
+		return s, s.Email != ""
+	}
+	return s, false
+}
+
+// NormalizeEmail returns the canonical form of an address used as the suppression key.
+func NormalizeEmail(address string) string {
+	address = strings.TrimSpace(address)
+	address = strings.TrimPrefix(address, "<")
+	address = strings.TrimSuffix(address, ">")
+	return strings.ToLower(address)
+}
+
+// kindForStatus classifies an RFC 3463 enhanced status code: 5.x.x is permanent,
+// anything else (4.x.x, or no code at all) is treated as transient.
+func kindForStatus(status string) string {
+	if strings.HasPrefix(status, "5") {
+		return KindHardBounce
+	}
+	return KindSoftBounce
+}
@@ -0,0 +1,168 @@
+// bounces/dsn.go
+package bounces
+
+import (
+	"bufio"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/textproto"
+	"strings"
+)
+
+// ErrNotReport is returned for messages that are not multipart/report DSNs or ARF complaints.
+var ErrNotReport = errors.New("message is not a delivery status or feedback report")
+
+// ParseReport parses an inbound multipart/report message: a delivery status
+// notification (RFC 3464) yields a bounce per failed recipient, and an abuse
+// feedback report (RFC 5965, ARF) yields a complaint.
+func ParseReport(r io.Reader) ([]Bounce, error) {
+	msg, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading message: %w", err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/report" || params["boundary"] == "" {
+		return nil, ErrNotReport
+	}
+
+	var bounces []Bounce
+	var feedback textproto.MIMEHeader
+	var originalTo string
+
+	parts := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := parts.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading report part: %w", err)
+		}
+
+		partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		body := partBody(part)
+		switch partType {
+		case "message/delivery-status", "message/global-delivery-status":
+			parsed, err := parseDeliveryStatus(body)
+			if err != nil {
+				return nil, err
+			}
+			bounces = append(bounces, parsed...)
+		case "message/feedback-report":
+			groups, err := readFieldGroups(body)
+			if err != nil {
+				return nil, err
+			}
+			if len(groups) > 0 {
+				feedback = groups[0]
+			}
+		case "message/rfc822", "text/rfc822-headers":
+			if original, err := textproto.NewReader(bufio.NewReader(body)).ReadMIMEHeader(); len(original) > 0 {
+				originalTo = original.Get("To")
+			} else if err != nil && err != io.EOF {
+				return nil, fmt.Errorf("reading original message headers: %w", err)
+			}
+		}
+	}
+
+	if feedback != nil {
+		recipient := fieldValue(feedback.Get("Original-Rcpt-To"))
+		if recipient == "" {
+			if addr, err := mail.ParseAddress(originalTo); err == nil {
+				recipient = addr.Address
+			}
+		}
+		if recipient != "" {
+			bounces = append(bounces, Bounce{
+				Email:      NormalizeEmail(recipient),
+				Kind:       KindComplaint,
+				Source:     SourceARF,
+				Diagnostic: feedback.Get("Feedback-Type"),
+			})
+		}
+	}
+	if bounces == nil && feedback == nil {
+		return nil, ErrNotReport
+	}
+	return bounces, nil
+}
+
+// parseDeliveryStatus reads the per-message fields followed by one field group
+// per recipient, returning a bounce for every recipient whose Action is "failed".
+func parseDeliveryStatus(r io.Reader) ([]Bounce, error) {
+	groups, err := readFieldGroups(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(groups) < 2 {
+		return nil, nil
+	}
+
+	var bounces []Bounce
+	for _, fields := range groups[1:] {
+		if !strings.EqualFold(strings.TrimSpace(fields.Get("Action")), "failed") {
+			// delayed, delivered, relayed and expanded are not bounces.
+			continue
+		}
+		recipient := fieldValue(fields.Get("Final-Recipient"))
+		if recipient == "" {
+			recipient = fieldValue(fields.Get("Original-Recipient"))
+		}
+		if recipient == "" {
+			continue
+		}
+		status := strings.Fields(fields.Get("Status"))
+		b := Bounce{
+			Email:      NormalizeEmail(recipient),
+			Source:     SourceDSN,
+			Diagnostic: fieldValue(fields.Get("Diagnostic-Code")),
+		}
+		if len(status) > 0 {
+			b.Status = status[0]
+		}
+		b.Kind = kindForStatus(b.Status)
+		bounces = append(bounces, b)
+	}
+	return bounces, nil
+}
+
+// readFieldGroups reads blank-line separated groups of header fields.
+func readFieldGroups(r io.Reader) ([]textproto.MIMEHeader, error) {
+	reader := textproto.NewReader(bufio.NewReader(r))
+	var groups []textproto.MIMEHeader
+	for {
+		fields, err := reader.ReadMIMEHeader()
+		if len(fields) > 0 {
+			groups = append(groups, fields)
+		}
+		if err == io.EOF {
+			return groups, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading report fields: %w", err)
+		}
+	}
+}
+
+// fieldValue strips the "type;" prefix from typed fields such as
+// "rfc822; volunteer@example.org" or "smtp; 550 5.1.1 User unknown".
+func fieldValue(value string) string {
+	if i := strings.Index(value, ";"); i >= 0 {
+		value = value[i+1:]
+	}
+	return strings.TrimSpace(value)
+}
+
+// partBody decodes base64 parts; multipart.Reader already decodes quoted-printable.
+func partBody(part *multipart.Part) io.Reader {
+	if strings.EqualFold(part.Header.Get("Content-Transfer-Encoding"), "base64") {
+		return base64.NewDecoder(base64.StdEncoding, part)
+	}
+	return part
+}
@@ -0,0 +1,169 @@
+// bounces/sql.go
+package bounces
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"notification-service/sqlstore"
+)
+
+// Timestamps are Unix milliseconds; an expires_at of 0 marks a permanent suppression.
+var schema = map[string][]string{
+	sqlstore.DriverSQLite: {
+		`CREATE TABLE IF NOT EXISTS email_suppressions (
+			email TEXT PRIMARY KEY,
+			reason TEXT NOT NULL,
+			source TEXT NOT NULL DEFAULT '',
+			status TEXT NOT NULL DEFAULT '',
+			diagnostic TEXT NOT NULL DEFAULT '',
+			created_at INTEGER NOT NULL,
+			expires_at INTEGER NOT NULL DEFAULT 0
+		)`,
+	},
+	sqlstore.DriverPostgres: {
+		`CREATE TABLE IF NOT EXISTS email_suppressions (
+			email TEXT PRIMARY KEY,
+			reason TEXT NOT NULL,
+			source TEXT NOT NULL DEFAULT '',
+			status TEXT NOT NULL DEFAULT '',
+			diagnostic TEXT NOT NULL DEFAULT '',
+			created_at BIGINT NOT NULL,
+			expires_at BIGINT NOT NULL DEFAULT 0
+		)`,
+	},
+}
+
+const suppressionColumns = `email, reason, source, status, diagnostic, created_at, expires_at`
+
+// SQLStore is a Store backed by SQLite or Postgres, so bounced and complaining
+// addresses stay suppressed across restarts.
+type SQLStore struct {
+	db *sqlstore.DB
+}
+
+// NewSQLStore creates the suppression table in db if needed
+func NewSQLStore(db *sqlstore.DB) (*SQLStore, error) {
+	if err := db.Migrate(schema[db.Driver]); err != nil {
+		return nil, fmt.Errorf("creating email suppression schema: %w", err)
+	}
+	return &SQLStore{db: db}, nil
+}
+
+// Add suppresses an address
+func (s *SQLStore) Add(entry Suppression) (Suppression, error) {
+	entry.Email = NormalizeEmail(entry.Email)
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now().UTC()
+	}
+
+	ctx := context.Background()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return Suppression{}, err
+	}
+	defer tx.Rollback()
+
+	lock := ""
+	if s.db.Driver == sqlstore.DriverPostgres {
+		lock = " FOR UPDATE"
+	}
+	existing, err := scanSuppression(tx.QueryRowContext(ctx, s.db.Rebind(
+		`SELECT `+suppressionColumns+` FROM email_suppressions WHERE email = ?`+lock), entry.Email))
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		return Suppression{}, err
+	case existing.Active(entry.CreatedAt) && existing.ExpiresAt.IsZero() && !entry.ExpiresAt.IsZero():
+		return existing, nil
+	}
+
+	_, err = tx.ExecContext(ctx, s.db.Rebind(`INSERT INTO email_suppressions (`+suppressionColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (email) DO UPDATE SET reason = excluded.reason, source = excluded.source, status = excluded.status,
+			diagnostic = excluded.diagnostic, created_at = excluded.created_at, expires_at = excluded.expires_at`),
+		entry.Email, entry.Reason, entry.Source, entry.Status, entry.Diagnostic,
+		entry.CreatedAt.UnixMilli(), unixMilli(entry.ExpiresAt))
+	if err != nil {
+		return Suppression{}, err
+	}
+	return entry, tx.Commit()
+}
+
+// Get returns the active suppression for an address
+func (s *SQLStore) Get(email string) (Suppression, error) {
+	entry, err := scanSuppression(s.db.QueryRowContext(context.Background(), s.db.Rebind(
+		`SELECT `+suppressionColumns+` FROM email_suppressions WHERE email = ? AND (expires_at = 0 OR expires_at > ?)`),
+		NormalizeEmail(email), time.Now().UnixMilli()))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Suppression{}, ErrNotFound
+	}
+	return entry, err
+}
+
+// Remove takes an address off the suppression list
+func (s *SQLStore) Remove(email string) error {
+	result, err := s.db.ExecContext(context.Background(), s.db.Rebind(
+		`DELETE FROM email_suppressions WHERE email = ?`), NormalizeEmail(email))
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// List returns all active suppressions, dropping expired ones
+func (s *SQLStore) List() ([]Suppression, error) {
+	ctx := context.Background()
+	now := time.Now().UnixMilli()
+	if _, err := s.db.ExecContext(ctx, s.db.Rebind(
+		`DELETE FROM email_suppressions WHERE expires_at <> 0 AND expires_at <= ?`), now); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT `+suppressionColumns+` FROM email_suppressions ORDER BY email`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []Suppression
+	for rows.Next() {
+		entry, err := scanSuppression(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, entry)
+	}
+	return list, rows.Err()
+}
+
+// scanSuppression reads a row selected with suppressionColumns.
+func scanSuppression(row interface{ Scan(...any) error }) (Suppression, error) {
+	var entry Suppression
+	var createdAt, expiresAt int64
+	err := row.Scan(&entry.Email, &entry.Reason, &entry.Source, &entry.Status, &entry.Diagnostic, &createdAt, &expiresAt)
+	if err != nil {
+		return Suppression{}, err
+	}
+	entry.CreatedAt = time.UnixMilli(createdAt).UTC()
+	if expiresAt != 0 {
+		entry.ExpiresAt = time.UnixMilli(expiresAt).UTC()
+	}
+	return entry, nil
+}
+
+// unixMilli stores the zero time, a permanent suppression, as 0.
+func unixMilli(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
@@ -0,0 +1,78 @@
+// bounces/sql_test.go
+package bounces
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"notification-service/sqlstore"
+)
+
+func openTestStore(t *testing.T) *SQLStore {
+	t.Helper()
+	db, err := sqlstore.Open(sqlstore.DriverSQLite, filepath.Join(t.TempDir(), "bounces.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	store, err := NewSQLStore(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func TestSQLStoreKeepsPermanentSuppression(t *testing.T) {
+	store := openTestStore(t)
+
+	if _, err := store.Add(Suppression{Email: "Ann@Example.com", Reason: KindHardBounce}); err != nil {
+		t.Fatal(err)
+	}
+	kept, err := store.Add(Suppression{Email: "ann@example.com", Reason: KindSoftBounce, ExpiresAt: time.Now().Add(time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kept.Reason != KindHardBounce || !kept.ExpiresAt.IsZero() {
+		t.Fatalf("Add() = %+v, want the permanent hard bounce kept", kept)
+	}
+
+	got, err := store.Get("ANN@example.com")
+	if err != nil || got.Reason != KindHardBounce {
+		t.Fatalf("Get() = %+v, %v", got, err)
+	}
+
+	if err := store.Remove("ann@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get("ann@example.com"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get() after Remove error = %v, want ErrNotFound", err)
+	}
+	if err := store.Remove("ann@example.com"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("second Remove() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestSQLStoreDropsExpired(t *testing.T) {
+	store := openTestStore(t)
+
+	past := time.Now().Add(-2 * time.Hour)
+	if _, err := store.Add(Suppression{Email: "old@example.com", Reason: KindSoftBounce, CreatedAt: past, ExpiresAt: past.Add(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Add(Suppression{Email: "new@example.com", Reason: KindComplaint}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := store.Get("old@example.com"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get() of expired entry error = %v, want ErrNotFound", err)
+	}
+	list, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].Email != "new@example.com" {
+		t.Fatalf("List() = %+v, want only new@example.com", list)
+	}
+}
@@ -0,0 +1,37 @@
+// bounces/store.go
+package bounces
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrNotFound is returned when an address is not on the suppression list.
+var ErrNotFound = errors.New("address not suppressed")
+
+// Suppression keeps email from being sent to an address.
+type Suppression struct {
+	Email      string    `json:"email"`
+	Reason     string    `json:"reason"` // hard_bounce, soft_bounce, complaint or manual
+	Source     string    `json:"source,omitempty"`
+	Status     string    `json:"status,omitempty"`
+	Diagnostic string    `json:"diagnostic,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	ExpiresAt  time.Time `json:"expires_at,omitempty"` // Zero means permanent
+}
+
+// Active reports whether the suppression still applies at the given time.
+func (s Suppression) Active(now time.Time) bool {
+	return s.ExpiresAt.IsZero() || now.Before(s.ExpiresAt)
+}
+
+// Store is the email suppression list, keyed by normalized address.
+type Store interface {
+	// Add suppresses an address. A permanent entry is never replaced by one that expires.
+	Add(s Suppression) (Suppression, error)
+	// Get returns the active suppression for an address, or ErrNotFound.
+	Get(email string) (Suppression, error)
+	Remove(email string) error
+	// List returns all active suppressions, ordered by address.
+	List() ([]Suppression, error)
+}
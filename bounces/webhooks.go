@@ -0,0 +1,138 @@
+// bounces/webhooks.go
+package bounces
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// SubscriptionConfirmation is returned by ParseSES when SNS asks for the topic
+// subscription to be confirmed; an operator (or the caller) must visit URL.
+type SubscriptionConfirmation struct {
+	TopicARN string
+	URL      string
+}
+
+func (c *SubscriptionConfirmation) Error() string {
+	return fmt.Sprintf("SNS subscription to %s needs confirmation", c.TopicARN)
+}
+
+type snsEnvelope struct {
+	Type         string `json:"Type"`
+	TopicARN     string `json:"TopicArn"`
+	Message      string `json:"Message"`
+	SubscribeURL string `json:"SubscribeURL"`
+}
+
+type sesNotification struct {
+	NotificationType string `json:"notificationType"`
+	EventType        string `json:"eventType"` // Used instead of notificationType by SES event publishing
+	Bounce           struct {
+		BounceType        string `json:"bounceType"` // Permanent, Transient or Undetermined
+		BouncedRecipients []struct {
+			EmailAddress   string `json:"emailAddress"`
+			Status         string `json:"status"`
+			DiagnosticCode string `json:"diagnosticCode"`
+		} `json:"bouncedRecipients"`
+	} `json:"bounce"`
+	Complaint struct {
+		ComplaintFeedbackType string `json:"complaintFeedbackType"`
+		ComplainedRecipients  []struct {
+			EmailAddress string `json:"emailAddress"`
+		} `json:"complainedRecipients"`
+	} `json:"complaint"`
+}
+
+// ParseSES parses an Amazon SES bounce/complaint notification, either wrapped in an
+// SNS envelope or raw. Other notification types (e.g. Delivery) yield no bounces.
+func ParseSES(body []byte) ([]Bounce, error) {
+	var envelope snsEnvelope
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, fmt.Errorf("invalid SES notification: %w", err)
+	}
+	switch envelope.Type {
+	case "SubscriptionConfirmation":
+		return nil, &SubscriptionConfirmation{TopicARN: envelope.TopicARN, URL: envelope.SubscribeURL}
+	case "Notification":
+		body = []byte(envelope.Message)
+	}
+
+	var n sesNotification
+	if err := json.Unmarshal(body, &n); err != nil {
+		return nil, fmt.Errorf("invalid SES notification message: %w", err)
+	}
+	notificationType := n.NotificationType
+	if notificationType == "" {
+		notificationType = n.EventType
+	}
+
+	var bounces []Bounce
+	switch notificationType {
+	case "Bounce":
+		kind := KindSoftBounce
+		if n.Bounce.BounceType == "Permanent" {
+			kind = KindHardBounce
+		}
+		for _, r := range n.Bounce.BouncedRecipients {
+			bounces = append(bounces, Bounce{
+				Email:      NormalizeEmail(r.EmailAddress),
+				Kind:       kind,
+				Source:     SourceSES,
+				Status:     r.Status,
+				Diagnostic: r.DiagnosticCode,
+			})
+		}
+	case "Complaint":
+		for _, r := range n.Complaint.ComplainedRecipients {
+			bounces = append(bounces, Bounce{
+				Email:      NormalizeEmail(r.EmailAddress),
+				Kind:       KindComplaint,
+				Source:     SourceSES,
+				Diagnostic: n.Complaint.ComplaintFeedbackType,
+			})
+		}
+	}
+	return bounces, nil
+}
+
+type sendGridEvent struct {
+	Email  string `json:"email"`
+	Event  string `json:"event"`
+	Type   string `json:"type"` // "bounce" or "blocked" for bounce events
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+}
+
+// ParseSendGrid parses a SendGrid Event Webhook batch. Bounces and spam reports
+// are returned; other events (delivered, open, click, ...) are ignored.
+func ParseSendGrid(body []byte) ([]Bounce, error) {
+	var events []sendGridEvent
+	if err := json.Unmarshal(body, &events); err != nil {
+		return nil, fmt.Errorf("invalid SendGrid events: %w", err)
+	}
+
+	var bounces []Bounce
+	for _, e := range events {
+		b := Bounce{
+			Email:      NormalizeEmail(e.Email),
+			Source:     SourceSendGrid,
+			Status:     e.Status,
+			Diagnostic: e.Reason,
+		}
+		switch e.Event {
+		case "bounce":
+			// "blocked" is SendGrid's transient bounce; "bounce" is permanent.
+			b.Kind = KindHardBounce
+			if strings.EqualFold(e.Type, "blocked") || strings.HasPrefix(e.Status, "4") {
+				b.Kind = KindSoftBounce
+			}
+		case "spamreport":
+			b.Kind = KindComplaint
+		default:
+			continue
+		}
+		bounces = append(bounces, b)
+	}
+	return bounces, nil
+}
@@ -0,0 +1,267 @@
+// cloudevents/cloudevents.go
+package cloudevents
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+	"time"
+
+	"notification-service/envelope"
+	"notification-service/models"
+)
+
+// SpecVersion is the CloudEvents specification version this service reads and writes.
+const SpecVersion = "1.0"
+
+// ContentType marks a message whose body is a whole event (structured mode).
+const ContentType = "application/cloudevents+json"
+
+// Ways events are written, chosen with CLOUDEVENTS_MODE.
+const (
+	ModeBinary     = "binary"     // Attributes in ce- headers, data as the body
+	ModeStructured = "structured" // The whole event as a JSON body
+)
+
+// HeaderPrefix prefixes the attribute headers of a binary-mode event. The AMQP protocol
+// binding's cloudEvents_ and cloudEvents: prefixes are accepted when reading as well.
+const HeaderPrefix = "ce-"
+
+var headerPrefixes = []string{HeaderPrefix, "cloudEvents_", "cloudEvents:"}
+
+// ErrInvalid is returned for a message that claims to be a CloudEvent but is not a valid one.
+var ErrInvalid = errors.New("invalid CloudEvent")
+
+// Event is a CloudEvent whose data is JSON.
+type Event struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Subject         string          `json:"subject,omitempty"`
+	Time            *time.Time      `json:"time,omitempty"`
+	DataContentType string          `json:"datacontenttype,omitempty"`
+	DataSchema      string          `json:"dataschema,omitempty"`
+	Data            json.RawMessage `json:"data,omitempty"`
+	// Mode is the mode the event was read in; it is not an attribute.
+	Mode string `json:"-"`
+}
+
+// New returns an event of the given type from source with data marshalled to JSON.
+func New(id, source, eventType string, data interface{}) (Event, error) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return Event{}, err
+	}
+	now := time.Now().UTC()
+	return Event{
+		SpecVersion:     SpecVersion,
+		ID:              id,
+		Source:          source,
+		Type:            eventType,
+		Time:            &now,
+		DataContentType: "application/json",
+		Data:            body,
+	}, nil
+}
+
+// ValidMode reports whether mode is one of the modes events can be written in.
+func ValidMode(mode string) bool {
+	return mode == ModeBinary || mode == ModeStructured
+}
+
+// Parse returns the CloudEvent carried by a message in binary or structured mode.
+// ok is false if the message is not a CloudEvent, in which case err is nil.
+func Parse(body []byte, contentType string, headers map[string]interface{}) (e Event, ok bool, err error) {
+	if mediaType, _, _ := mime.ParseMediaType(contentType); mediaType == ContentType {
+		e, err = parseStructured(body)
+		return e, true, err
+	}
+	if _, found := attribute(headers, "specversion"); found {
+		e, err = parseBinary(body, contentType, headers)
+		return e, true, err
+	}
+	return Event{}, false, nil
+}
+
+// parseStructured decodes an event written as a whole JSON body.
+func parseStructured(body []byte) (Event, error) {
+	var e struct {
+		Event
+		DataBase64 string `json:"data_base64"`
+	}
+	if err := json.Unmarshal(body, &e); err != nil {
+		return Event{}, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	if e.DataBase64 != "" {
+		data, err := base64.StdEncoding.DecodeString(e.DataBase64)
+		if err != nil {
+			return Event{}, fmt.Errorf("%w: data_base64: %v", ErrInvalid, err)
+		}
+		e.Data = data
+	}
+	e.Mode = ModeStructured
+	return e.Event, e.validate()
+}
+
+// parseBinary reads an event's attributes from headers; the body is its data.
+func parseBinary(body []byte, contentType string, headers map[string]interface{}) (Event, error) {
+	e := Event{
+		Mode:            ModeBinary,
+		DataContentType: contentType,
+		Data:            body,
+	}
+	e.SpecVersion, _ = attribute(headers, "specversion")
+	e.ID, _ = attribute(headers, "id")
+	e.Source, _ = attribute(headers, "source")
+	e.Type, _ = attribute(headers, "type")
+	e.Subject, _ = attribute(headers, "subject")
+	e.DataSchema, _ = attribute(headers, "dataschema")
+	if value, ok := attribute(headers, "time"); ok && value != "" {
+		at, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return Event{}, fmt.Errorf("%w: time: %v", ErrInvalid, err)
+		}
+		e.Time = &at
+	}
+	return e, e.validate()
+}
+
+// validate checks the required attributes and that the data is JSON.
+func (e Event) validate() error {
+	if e.SpecVersion != SpecVersion {
+		return fmt.Errorf("%w: unsupported specversion %q (supported: %s)", ErrInvalid, e.SpecVersion, SpecVersion)
+	}
+	var missing []string
+	for _, attr := range []struct{ name, value string }{{"id", e.ID}, {"source", e.Source}, {"type", e.Type}} {
+		if attr.value == "" {
+			missing = append(missing, attr.name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: missing %s", ErrInvalid, strings.Join(missing, ", "))
+	}
+	if e.DataContentType != "" && !isJSON(e.DataContentType) {
+		return fmt.Errorf("%w: datacontenttype %q is not JSON", ErrInvalid, e.DataContentType)
+	}
+	return nil
+}
+
+// Notification decodes the event's data as a notification message of any supported
+// envelope version. The event's type is the notification type; data that names a
+// different one is malformed. The event's id, time and source fill in the message's
+// event ID, timestamp and sender service when it has none.
+func (e Event) Notification() (models.NotificationMessage, int, error) {
+	if len(e.Data) == 0 {
+		return models.NotificationMessage{}, 0, fmt.Errorf("%w: event %s has no data", ErrInvalid, e.ID)
+	}
+	msg, version, err := envelope.Decode(e.Data)
+	if err != nil {
+		return msg, version, err
+	}
+
+	switch msg.NotificationType {
+	case "":
+		msg.NotificationType = e.Type
+	case e.Type:
+	default:
+		return msg, version, fmt.Errorf("%w: type %q does not match the data's notification_type %q",
+			envelope.ErrMalformed, e.Type, msg.NotificationType)
+	}
+	if msg.EventID == "" {
+		msg.EventID = e.ID
+	}
+	if msg.Timestamp == 0 && e.Time != nil {
+		msg.Timestamp = e.Time.Unix()
+	}
+	if msg.SenderService == "" {
+		msg.SenderService = e.Source
+	}
+	return msg, version, nil
+}
+
+// DecodeNotification decodes a notification message that may be carried by a CloudEvent.
+// It returns the message and the envelope version its data was written in.
+func DecodeNotification(body []byte, contentType string, headers map[string]interface{}) (models.NotificationMessage, int, error) {
+	e, ok, err := Parse(body, contentType, headers)
+	if err != nil {
+		return models.NotificationMessage{}, 0, err
+	}
+	if !ok {
+		return envelope.Decode(body)
+	}
+	return e.Notification()
+}
+
+// Headers returns the event's attributes as binary-mode headers.
+func (e Event) Headers() map[string]interface{} {
+	headers := map[string]interface{}{
+		HeaderPrefix + "specversion": e.SpecVersion,
+		HeaderPrefix + "id":          e.ID,
+		HeaderPrefix + "source":      e.Source,
+		HeaderPrefix + "type":        e.Type,
+	}
+	if e.Subject != "" {
+		headers[HeaderPrefix+"subject"] = e.Subject
+	}
+	if e.Time != nil {
+		headers[HeaderPrefix+"time"] = e.Time.Format(time.RFC3339Nano)
+	}
+	if e.DataSchema != "" {
+		headers[HeaderPrefix+"dataschema"] = e.DataSchema
+	}
+	return headers
+}
+
+// StripHeaders returns a copy of headers without binary-mode attributes.
+func StripHeaders(headers map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(headers))
+	for key, value := range headers {
+		if !isAttributeHeader(key) {
+			result[key] = value
+		}
+	}
+	return result
+}
+
+// attribute returns the binary-mode header for the named attribute.
+func attribute(headers map[string]interface{}, name string) (string, bool) {
+	for _, prefix := range headerPrefixes {
+		value, ok := headers[prefix+name]
+		if !ok {
+			continue
+		}
+		switch v := value.(type) {
+		case string:
+			return v, true
+		case []byte:
+			return string(v), true
+		case time.Time:
+			return v.UTC().Format(time.RFC3339), true
+		default:
+			return fmt.Sprint(v), true
+		}
+	}
+	return "", false
+}
+
+func isAttributeHeader(key string) bool {
+	for _, prefix := range headerPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isJSON reports whether contentType is application/json or a +json type.
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
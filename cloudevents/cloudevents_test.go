@@ -0,0 +1,158 @@
+// cloudevents/cloudevents_test.go
+package cloudevents
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	at := time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)
+	data := `{"recipient":{"user_id":"u1"},"payload":{"title":"t","body":"b"}}`
+
+	tests := []struct {
+		name        string
+		body        string
+		contentType string
+		headers     map[string]interface{}
+		wantOK      bool
+		wantErr     error
+		want        Event
+	}{
+		{
+			name:        "binary mode",
+			body:        data,
+			contentType: "application/json",
+			headers: map[string]interface{}{
+				"ce-specversion": "1.0", "ce-id": "e1", "ce-source": "/volhub", "ce-type": "APPLICATION_ACCEPTED",
+				"ce-subject": "application/12", "ce-time": "2025-01-02T15:04:05Z",
+			},
+			wantOK: true,
+			want: Event{
+				SpecVersion: "1.0", ID: "e1", Source: "/volhub", Type: "APPLICATION_ACCEPTED",
+				Subject: "application/12", Time: &at, DataContentType: "application/json",
+				Data: []byte(data), Mode: ModeBinary,
+			},
+		},
+		{
+			name:        "binary mode with AMQP binding prefixes and byte values",
+			body:        data,
+			contentType: "application/json",
+			headers: map[string]interface{}{
+				"cloudEvents_specversion": "1.0", "cloudEvents_id": []byte("e2"),
+				"cloudEvents:source": "/volhub", "cloudEvents:type": "NGO_NEW_APPLICATION", "cloudEvents:time": at,
+			},
+			wantOK: true,
+			want: Event{
+				SpecVersion: "1.0", ID: "e2", Source: "/volhub", Type: "NGO_NEW_APPLICATION",
+				Time: &at, DataContentType: "application/json", Data: []byte(data), Mode: ModeBinary,
+			},
+		},
+		{
+			name: "structured mode",
+			body: `{"specversion":"1.0","id":"e3","source":"/volhub","type":"OPPORTUNITY_UPDATED",
+				"time":"2025-01-02T15:04:05Z","datacontenttype":"application/json","data":` + data + `}`,
+			contentType: "application/cloudevents+json; charset=utf-8",
+			wantOK:      true,
+			want: Event{
+				SpecVersion: "1.0", ID: "e3", Source: "/volhub", Type: "OPPORTUNITY_UPDATED",
+				Time: &at, DataContentType: "application/json", Data: []byte(data), Mode: ModeStructured,
+			},
+		},
+		{
+			name:        "structured mode with data_base64",
+			body:        `{"specversion":"1.0","id":"e4","source":"/volhub","type":"OPPORTUNITY_DELETED","data_base64":"eyJhIjoxfQ=="}`,
+			contentType: ContentType,
+			wantOK:      true,
+			want: Event{
+				SpecVersion: "1.0", ID: "e4", Source: "/volhub", Type: "OPPORTUNITY_DELETED",
+				Data: []byte(`{"a":1}`), Mode: ModeStructured,
+			},
+		},
+		{
+			name:        "plain message",
+			body:        data,
+			contentType: "application/json",
+			headers:     map[string]interface{}{"x-retry-count": int32(1)},
+		},
+		{
+			name:        "binary mode missing required attributes",
+			body:        data,
+			contentType: "application/json",
+			headers:     map[string]interface{}{"ce-specversion": "1.0", "ce-id": "e5"},
+			wantOK:      true,
+			wantErr:     ErrInvalid,
+		},
+		{
+			name:        "binary mode unsupported specversion",
+			body:        data,
+			contentType: "application/json",
+			headers:     map[string]interface{}{"ce-specversion": "0.3", "ce-id": "e6", "ce-source": "/volhub", "ce-type": "T"},
+			wantOK:      true,
+			wantErr:     ErrInvalid,
+		},
+		{
+			name:        "binary mode bad time",
+			body:        data,
+			contentType: "application/json",
+			headers: map[string]interface{}{
+				"ce-specversion": "1.0", "ce-id": "e7", "ce-source": "/volhub", "ce-type": "T", "ce-time": "yesterday",
+			},
+			wantOK:  true,
+			wantErr: ErrInvalid,
+		},
+		{
+			name:        "binary mode data that is not JSON",
+			body:        "hello",
+			contentType: "text/plain",
+			headers:     map[string]interface{}{"ce-specversion": "1.0", "ce-id": "e8", "ce-source": "/volhub", "ce-type": "T"},
+			wantOK:      true,
+			wantErr:     ErrInvalid,
+		},
+		{
+			name:        "structured mode body that is not JSON",
+			body:        `{"specversion":`,
+			contentType: ContentType,
+			wantOK:      true,
+			wantErr:     ErrInvalid,
+		},
+		{
+			name:        "structured mode bad data_base64",
+			body:        `{"specversion":"1.0","id":"e9","source":"/volhub","type":"T","data_base64":"%%%"}`,
+			contentType: ContentType,
+			wantOK:      true,
+			wantErr:     ErrInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok, err := Parse([]byte(tt.body), tt.contentType, tt.headers)
+			if ok != tt.wantOK {
+				t.Fatalf("Parse() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Parse() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if string(got.Data) != string(tt.want.Data) {
+				t.Errorf("Data = %s, want %s", got.Data, tt.want.Data)
+			}
+			if (got.Time == nil) != (tt.want.Time == nil) || got.Time != nil && !got.Time.Equal(*tt.want.Time) {
+				t.Errorf("Time = %v, want %v", got.Time, tt.want.Time)
+			}
+			got.Data, tt.want.Data = nil, nil
+			got.Time, tt.want.Time = nil, nil
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
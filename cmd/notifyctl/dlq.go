@@ -0,0 +1,162 @@
+// cmd/notifyctl/dlq.go
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"notification-service/dlq"
+)
+
+const dlqUsage = `Usage: notifyctl dlq <command> [flags] [id...]
+
+Commands:
+  list     list dead-lettered messages with their failure reasons
+  show     print dead-lettered messages in full, as JSON
+  replay   publish messages back to notification_exchange with their original routing keys
+  edit     replay one message with a corrected body: notifyctl dlq edit -file msg.json <id>
+  purge    delete messages from the dead letter queue
+  audit    list recent dead letter queue operations
+
+replay and purge take the list filters, or -all to select every message.
+`
+
+func runDLQ(c *client, args []string) error {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, dlqUsage)
+		os.Exit(2)
+	}
+	command, args := args[0], args[1:]
+
+	flags := flag.NewFlagSet("dlq "+command, flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprint(os.Stderr, dlqUsage)
+		fmt.Fprintln(os.Stderr, "\nFlags:")
+		flags.PrintDefaults()
+	}
+	var filter dlq.Filter
+	var ids stringList
+	flags.Var(&ids, "id", "message ID (repeatable; IDs may also be given as arguments)")
+	flags.StringVar(&filter.NotificationType, "type", "", "notification type")
+	flags.StringVar(&filter.RoutingKey, "routing-key", "", "original routing key")
+	flags.StringVar(&filter.Queue, "queue", "", "queue the message failed on")
+	flags.StringVar(&filter.Reason, "reason", "", "substring of the failure reason")
+	limit := flags.Int("limit", 100, "maximum number of messages or audit entries to list")
+	asJSON := flags.Bool("json", false, "print JSON instead of a table")
+	flags.BoolVar(&filter.All, "all", false, "select every message (replay, purge)")
+	file := flags.String("file", "", "edited NotificationMessage JSON, or - for stdin (edit)")
+	flags.Parse(args)
+	filter.IDs = append(ids, flags.Args()...)
+
+	switch command {
+	case "list", "show":
+		var entries []dlq.Entry
+		if err := c.do("GET", "/v1/admin/dlq?"+listQuery(filter, *limit), nil, &entries); err != nil {
+			return err
+		}
+		if *asJSON || command == "show" {
+			return printJSON(entries)
+		}
+		printEntries(entries)
+		return nil
+
+	case "replay", "purge":
+		var result dlq.Result
+		if err := c.do("POST", "/v1/admin/dlq/"+command, filter, &result); err != nil {
+			return err
+		}
+		if *asJSON {
+			return printJSON(result)
+		}
+		verb := map[string]string{"replay": "Replayed", "purge": "Purged"}[command]
+		fmt.Printf("%s %d message(s)\n", verb, result.Count)
+		for _, id := range result.IDs {
+			fmt.Println(" ", id)
+		}
+		return nil
+
+	case "edit":
+		if len(filter.IDs) != 1 || *file == "" {
+			return errors.New("edit takes one message ID and -file")
+		}
+		body, err := readInput(*file)
+		if err != nil {
+			return err
+		}
+		if err := c.do("POST", "/v1/admin/dlq/"+url.PathEscape(filter.IDs[0])+"/replay", body, nil); err != nil {
+			return err
+		}
+		fmt.Println("Replayed edited message", filter.IDs[0])
+		return nil
+
+	case "audit":
+		var entries []dlq.AuditEntry
+		if err := c.do("GET", "/v1/admin/dlq/audit?limit="+strconv.Itoa(*limit), nil, &entries); err != nil {
+			return err
+		}
+		if *asJSON {
+			return printJSON(entries)
+		}
+		printAudit(entries)
+		return nil
+
+	default:
+		flags.Usage()
+		os.Exit(2)
+		return nil
+	}
+}
+
+// listQuery encodes a filter as GET /v1/admin/dlq query parameters.
+func listQuery(filter dlq.Filter, limit int) string {
+	query := url.Values{}
+	query["id"] = filter.IDs
+	for key, value := range map[string]string{
+		"type":        filter.NotificationType,
+		"routing_key": filter.RoutingKey,
+		"queue":       filter.Queue,
+		"reason":      filter.Reason,
+	} {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	query.Set("limit", strconv.Itoa(limit))
+	return query.Encode()
+}
+
+func printEntries(entries []dlq.Entry) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tFAILED AT\tQUEUE\tROUTING KEY\tTYPE\tUSER\tATTEMPTS\tREASON")
+	for _, e := range entries {
+		notificationType, userID := "-", "-"
+		if e.Notification != nil {
+			notificationType, userID = e.Notification.NotificationType, e.Notification.UserID
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%d\t%s\n", e.ID, formatTime(e.FailedAt),
+			e.Queue, e.RoutingKey, notificationType, userID, e.Attempts, e.Reason)
+	}
+	w.Flush()
+}
+
+func printAudit(entries []dlq.AuditEntry) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "AT\tACTION\tACTOR\tKEY\tCOUNT\tERROR")
+	for _, e := range entries {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\n", formatTime(e.At), e.Action, e.Actor.Name, e.Actor.Key, e.Count, e.Error)
+	}
+	w.Flush()
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format(time.DateTime)
+}
@@ -0,0 +1,208 @@
+// cmd/notifyctl/main.go
+//
+// notifyctl is the operator CLI for the notification service.
+//
+//	notifyctl [global flags] publish [-file message.json] [flags]
+//	notifyctl [global flags] tail [queue]
+//	notifyctl [global flags] topology
+//	notifyctl [global flags] status <id>
+//	notifyctl [global flags] dlq list|show|replay|edit|purge|audit ...
+//	notifyctl [global flags] queue list|pause|resume ...
+//
+// publish, tail and topology talk to RabbitMQ; the other commands call the service's
+// HTTP API. Global flags default to the RABBITMQ_URL, NOTIFYCTL_URL, NOTIFYCTL_API_KEY
+// and NOTIFYCTL_ACTOR environment variables.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"notification-service/constants"
+	"notification-service/rabbitmq"
+)
+
+// client calls the notification service's HTTP API and connects to RabbitMQ.
+type client struct {
+	amqpURL string
+	baseURL string
+	apiKey  string
+	actor   string
+	http    *http.Client
+}
+
+func main() {
+	global := flag.NewFlagSet("notifyctl", flag.ExitOnError)
+	amqpURL := global.String("amqp-url", getEnv("RABBITMQ_URL", constants.DefaultRabbitMQURL), "RabbitMQ URL")
+	baseURL := global.String("url", getEnv("NOTIFYCTL_URL", "http://localhost:8080"), "notification service HTTP API base URL")
+	apiKey := global.String("api-key", os.Getenv("NOTIFYCTL_API_KEY"), "API key: an ADMIN_API_KEYS key for dlq and queue, a NOTIFICATIONS_API_KEYS key for status")
+	actor := global.String("actor", getEnv("NOTIFYCTL_ACTOR", os.Getenv("USER")), "operator name recorded in the audit log")
+	verbose := global.Bool("v", false, "log RabbitMQ connection and publishing details")
+	global.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: notifyctl [flags] <command> [args]")
+		fmt.Fprintln(os.Stderr, "\nCommands:")
+		fmt.Fprintln(os.Stderr, "  publish    publish a NotificationMessage with its type's routing key")
+		fmt.Fprintln(os.Stderr, "  tail       print messages for a queue as they are published")
+		fmt.Fprintln(os.Stderr, "  topology   show the exchange, queues, bindings and routing keys")
+		fmt.Fprintln(os.Stderr, "  status     look up the delivery status of a notification by ID")
+		fmt.Fprintln(os.Stderr, "  dlq        inspect, replay and purge dead-lettered messages")
+		fmt.Fprintln(os.Stderr, "  queue      pause and resume consumption per queue")
+		fmt.Fprintln(os.Stderr, "\nFlags:")
+		global.PrintDefaults()
+	}
+	global.Parse(os.Args[1:])
+	if !*verbose {
+		// The rabbitmq package logs every connection and publish.
+		log.SetOutput(io.Discard)
+	}
+
+	c := &client{
+		amqpURL: *amqpURL,
+		baseURL: strings.TrimRight(*baseURL, "/"),
+		apiKey:  *apiKey,
+		actor:   *actor,
+		http:    &http.Client{Timeout: 2 * time.Minute},
+	}
+
+	args := global.Args()
+	if len(args) == 0 {
+		global.Usage()
+		os.Exit(2)
+	}
+	var err error
+	switch args[0] {
+	case "publish":
+		err = runPublish(c, args[1:])
+	case "tail":
+		err = runTail(c, args[1:])
+	case "topology":
+		err = runTopology(c, args[1:])
+	case "status":
+		err = runStatus(c, args[1:])
+	case "dlq":
+		err = runDLQ(c, args[1:])
+	case "queue":
+		err = runQueue(c, args[1:])
+	default:
+		global.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "notifyctl:", err)
+		os.Exit(1)
+	}
+}
+
+// connect opens a RabbitMQ connection.
+func (c *client) connect() (*rabbitmq.Connection, error) {
+	conn, err := rabbitmq.Dial(c.amqpURL)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to RabbitMQ: %w", err)
+	}
+	return conn, nil
+}
+
+// do sends a request with body marshalled as JSON (unless it is nil or already []byte)
+// and decodes a JSON response into out. Non-2xx responses are returned as errors.
+func (c *client) do(method, path string, body, out interface{}) error {
+	var reader io.Reader
+	switch b := body.(type) {
+	case nil:
+	case []byte:
+		reader = bytes.NewReader(b)
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	if reader != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+	if c.actor != "" {
+		req.Header.Set("X-Actor", c.actor)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode/100 != 2 {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Error != "" {
+			return fmt.Errorf("%s %s: %s (%s)", method, path, apiErr.Error, resp.Status)
+		}
+		return fmt.Errorf("%s %s: %s", method, path, resp.Status)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(data, out)
+}
+
+// printJSON writes v to stdout as indented JSON.
+func printJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// readInput reads a file, or stdin when path is "-".
+func readInput(path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(path)
+}
+
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// sortedKeys returns the keys of m in order.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// stringList is a repeatable string flag.
+type stringList []string
+
+func (l *stringList) String() string { return strings.Join(*l, ",") }
+
+func (l *stringList) Set(s string) error {
+	*l = append(*l, s)
+	return nil
+}
@@ -0,0 +1,170 @@
+// cmd/notifyctl/publish.go
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"notification-service/constants"
+	"notification-service/envelope"
+	"notification-service/handlers"
+	"notification-service/models"
+	"notification-service/rabbitmq"
+)
+
+const publishUsage = `Usage: notifyctl publish [-file message.json] [flags]
+
+Publishes a notification to notification_exchange with the routing key NestJS uses for
+its notification type, in the latest envelope schema version unless -legacy is set.
+The message is read from -file (- for stdin; any supported schema version) and/or
+built from flags; flags override fields from the file.
+`
+
+func runPublish(c *client, args []string) error {
+	flags := flag.NewFlagSet("publish", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprint(os.Stderr, publishUsage)
+		fmt.Fprintln(os.Stderr, "\nFlags:")
+		flags.PrintDefaults()
+	}
+	file := flags.String("file", "", "NotificationMessage JSON, or - for stdin")
+	dryRun := flags.Bool("dry-run", false, "print the message and routing key instead of publishing")
+	legacy := flags.Bool("legacy", false, "publish as an unversioned (schema version 1) message")
+
+	var msg models.NotificationMessage
+	flags.StringVar(&msg.NotificationType, "type", "", "notification type, e.g. "+constants.NotificationTypeNgoNewApplication)
+	flags.StringVar(&msg.EventID, "event-id", "", "event ID (generated if empty)")
+	flags.StringVar(&msg.Recipient.UserID, "user", "", "recipient user ID")
+	flags.StringVar(&msg.Recipient.EmailAddress, "email", "", "recipient email address")
+	flags.StringVar(&msg.Recipient.DeviceToken, "device-token", "", "recipient device token")
+	flags.StringVar(&msg.Recipient.PlatformType, "platform", "", "recipient platform type")
+	flags.StringVar(&msg.Recipient.Timezone, "timezone", "", "recipient IANA timezone")
+	flags.BoolVar(&msg.Recipient.Prefs.ReceivePush, "receive-push", true, "recipient accepts push notifications")
+	flags.BoolVar(&msg.Recipient.Prefs.ReceiveEmail, "receive-email", true, "recipient accepts emails")
+	flags.StringVar(&msg.Payload.Title, "title", "", "payload title")
+	flags.StringVar(&msg.Payload.Body, "body", "", "payload body")
+	flags.StringVar(&msg.Payload.Subject, "subject", "", "email subject")
+	flags.StringVar(&msg.Payload.DeepLink, "deep-link", "", "push deep link")
+	flags.StringVar(&msg.Payload.TemplateName, "template", "", "email template name")
+	flags.IntVar(&msg.Payload.ApplicationID, "application-id", 0, "application ID")
+	flags.IntVar(&msg.Payload.OpportunityID, "opportunity-id", 0, "opportunity ID")
+	flags.IntVar(&msg.Payload.NGOID, "ngo-id", 0, "NGO ID")
+	flags.StringVar(&msg.SenderService, "sender", "notifyctl", "sender service")
+	flags.Parse(args)
+
+	if *file != "" {
+		data, err := readInput(*file)
+		if err != nil {
+			return err
+		}
+		fromFile, _, err := envelope.Decode(data)
+		if err != nil {
+			return fmt.Errorf("decoding %s: %w", *file, err)
+		}
+		// Flags that were set override the file.
+		overrides := msg
+		msg = fromFile
+		if msg.SenderService == "" {
+			msg.SenderService = overrides.SenderService
+		}
+		flags.Visit(func(f *flag.Flag) { applyOverride(&msg, overrides, f.Name) })
+	}
+	if msg.Timestamp == 0 {
+		msg.Timestamp = time.Now().Unix()
+	}
+	if err := handlers.ValidateNotification(msg); err != nil {
+		return err
+	}
+	routingKey := constants.NotificationRoutingKeys[msg.NotificationType]
+
+	if msg.EventID == "" {
+		msg.EventID = rabbitmq.NewMessageID()
+	}
+	body, err := envelope.Encode(msg)
+	if *legacy {
+		body, err = json.Marshal(msg)
+	}
+	if err != nil {
+		return err
+	}
+
+	if *dryRun {
+		fmt.Printf("Routing key: %s\n", routingKey)
+		var out bytes.Buffer
+		json.Indent(&out, body, "", "  ")
+		fmt.Println(out.String())
+		return nil
+	}
+
+	conn, err := c.connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	publisher, err := rabbitmq.NewPublisher(conn, constants.ExchangeName)
+	if err != nil {
+		return err
+	}
+	defer publisher.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = publisher.Publish(ctx, rabbitmq.Message{
+		RoutingKey: routingKey,
+		Body:       body,
+		MessageID:  msg.EventID,
+		Mandatory:  true,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Published %s (%s) to %s with routing key %s\n", msg.EventID, msg.NotificationType, constants.ExchangeName, routingKey)
+	return nil
+}
+
+// applyOverride copies the field set by the named flag from overrides to msg.
+func applyOverride(msg *models.NotificationMessage, overrides models.NotificationMessage, name string) {
+	switch name {
+	case "type":
+		msg.NotificationType = overrides.NotificationType
+	case "event-id":
+		msg.EventID = overrides.EventID
+	case "user":
+		msg.Recipient.UserID = overrides.Recipient.UserID
+	case "email":
+		msg.Recipient.EmailAddress = overrides.Recipient.EmailAddress
+	case "device-token":
+		msg.Recipient.DeviceToken = overrides.Recipient.DeviceToken
+	case "platform":
+		msg.Recipient.PlatformType = overrides.Recipient.PlatformType
+	case "timezone":
+		msg.Recipient.Timezone = overrides.Recipient.Timezone
+	case "receive-push":
+		msg.Recipient.Prefs.ReceivePush = overrides.Recipient.Prefs.ReceivePush
+	case "receive-email":
+		msg.Recipient.Prefs.ReceiveEmail = overrides.Recipient.Prefs.ReceiveEmail
+	case "title":
+		msg.Payload.Title = overrides.Payload.Title
+	case "body":
+		msg.Payload.Body = overrides.Payload.Body
+	case "subject":
+		msg.Payload.Subject = overrides.Payload.Subject
+	case "deep-link":
+		msg.Payload.DeepLink = overrides.Payload.DeepLink
+	case "template":
+		msg.Payload.TemplateName = overrides.Payload.TemplateName
+	case "application-id":
+		msg.Payload.ApplicationID = overrides.Payload.ApplicationID
+	case "opportunity-id":
+		msg.Payload.OpportunityID = overrides.Payload.OpportunityID
+	case "ngo-id":
+		msg.Payload.NGOID = overrides.Payload.NGOID
+	case "sender":
+		msg.SenderService = overrides.SenderService
+	}
+}
@@ -0,0 +1,86 @@
+// cmd/notifyctl/queue.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"text/tabwriter"
+
+	"notification-service/rabbitmq"
+)
+
+const queueUsage = `Usage: notifyctl queue <command> [flags] [queue]
+
+Commands:
+  list     show the consumer status of every queue
+  pause    stop consuming a queue; its messages stay in RabbitMQ
+  resume   consume a paused queue again
+
+ngo_email_queue sends NGO notifications by email only and ngo_push_queue by
+push only, so pausing one of them stops just that channel for NGOs.
+volunteer_push_queue sends on every channel.
+`
+
+func runQueue(c *client, args []string) error {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, queueUsage)
+		os.Exit(2)
+	}
+	command, args := args[0], args[1:]
+
+	flags := flag.NewFlagSet("queue "+command, flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprint(os.Stderr, queueUsage)
+		fmt.Fprintln(os.Stderr, "\nFlags:")
+		flags.PrintDefaults()
+	}
+	asJSON := flags.Bool("json", false, "print JSON instead of a table")
+	flags.Parse(args)
+
+	var statuses []rabbitmq.QueueStatus
+	switch command {
+	case "list":
+		if err := c.do("GET", "/v1/admin/queues", nil, &statuses); err != nil {
+			return err
+		}
+
+	case "pause", "resume":
+		if flags.NArg() != 1 {
+			return fmt.Errorf("%s takes one queue name", command)
+		}
+		var status rabbitmq.QueueStatus
+		if err := c.do("POST", "/v1/admin/queues/"+url.PathEscape(flags.Arg(0))+"/"+command, nil, &status); err != nil {
+			return err
+		}
+		statuses = []rabbitmq.QueueStatus{status}
+
+	default:
+		flags.Usage()
+		os.Exit(2)
+		return nil
+	}
+
+	if *asJSON {
+		return printJSON(statuses)
+	}
+	printQueues(statuses)
+	return nil
+}
+
+func printQueues(statuses []rabbitmq.QueueStatus) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "QUEUE\tSTATE\tSINCE\tCONSUMER TAG\tLAST ERROR")
+	for _, s := range statuses {
+		state := "consuming"
+		switch {
+		case s.Paused:
+			state = "paused"
+		case !s.Registered:
+			state = "down"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", s.Queue, state, formatTime(s.Since), s.ConsumerTag, s.LastError)
+	}
+	w.Flush()
+}
@@ -0,0 +1,46 @@
+// cmd/notifyctl/status.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"text/tabwriter"
+
+	"notification-service/deliverylog"
+)
+
+func runStatus(c *client, args []string) error {
+	flags := flag.NewFlagSet("status", flag.ExitOnError)
+	asJSON := flags.Bool("json", false, "print JSON instead of tables")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		return fmt.Errorf("status takes one notification ID")
+	}
+
+	var summary deliverylog.Summary
+	if err := c.do("GET", "/v1/notifications/"+url.PathEscape(flags.Arg(0)), nil, &summary); err != nil {
+		return err
+	}
+	if *asJSON {
+		return printJSON(summary)
+	}
+
+	fmt.Printf("Notification %s: %s\n\n", summary.EventID, summary.Status)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PROCESSED AT\tQUEUE\tTYPE\tUSER\tSTATUS\tERROR")
+	for _, m := range summary.Messages {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", formatTime(m.ProcessedAt), m.Queue, m.NotificationType, m.UserID, m.Status, m.Error)
+	}
+	w.Flush()
+
+	fmt.Println()
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "AT\tCHANNEL\tPROVIDER\tATTEMPT\tSTATUS\tREASON\tERROR")
+	for _, a := range summary.Attempts {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\t%s\n", formatTime(a.CreatedAt), a.Channel, a.Provider, a.AttemptNumber, a.Status, a.Reason, a.Error)
+	}
+	w.Flush()
+	return nil
+}
@@ -0,0 +1,107 @@
+// cmd/notifyctl/tail.go
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"notification-service/cloudevents"
+	"notification-service/constants"
+	"notification-service/rabbitmq"
+)
+
+const tailUsage = `Usage: notifyctl tail [flags] [queue]
+
+Prints messages published to notification_exchange for a queue's routing keys as they
+arrive, until interrupted. Messages are copied to a temporary queue, so the service
+still receives them. Without a queue, every routing key is tailed.
+`
+
+func runTail(c *client, args []string) error {
+	flags := flag.NewFlagSet("tail", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprint(os.Stderr, tailUsage)
+		fmt.Fprintln(os.Stderr, "\nFlags:")
+		flags.PrintDefaults()
+	}
+	var routingKeys stringList
+	flags.Var(&routingKeys, "routing-key", "routing key or pattern to tail instead of the queue's (repeatable)")
+	showBody := flags.Bool("body", false, "print message bodies")
+	asJSON := flags.Bool("json", false, "print one JSON object per message")
+	flags.Parse(args)
+
+	switch {
+	case len(routingKeys) > 0:
+	case flags.NArg() == 0:
+		routingKeys = stringList{"#"}
+	case flags.NArg() == 1:
+		bindings, ok := constants.QueueBindings[flags.Arg(0)]
+		if !ok {
+			return fmt.Errorf("queue %s is not bound to %s; see notifyctl topology", flags.Arg(0), constants.ExchangeName)
+		}
+		routingKeys = bindings
+	default:
+		return fmt.Errorf("tail takes at most one queue")
+	}
+
+	conn, err := c.connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	fmt.Fprintf(os.Stderr, "Tailing %s for %v; press CTRL+C to stop\n", constants.ExchangeName, []string(routingKeys))
+
+	enc := json.NewEncoder(os.Stdout)
+	return conn.Tail(ctx, constants.ExchangeName, routingKeys, func(d rabbitmq.Delivery) {
+		if *asJSON {
+			enc.Encode(tailRecord{
+				MessageID:  d.MessageID,
+				RoutingKey: d.RoutingKey,
+				Timestamp:  d.Timestamp,
+				Headers:    d.Headers,
+				Body:       json.RawMessage(jsonOrString(d.Body)),
+			})
+			return
+		}
+
+		at := d.Timestamp
+		if at.IsZero() {
+			at = time.Now()
+		}
+		summary := "-"
+		if msg, _, err := cloudevents.DecodeNotification(d.Body, d.ContentType, d.Headers); err == nil && msg.NotificationType != "" {
+			summary = fmt.Sprintf("%s user=%s", msg.NotificationType, msg.Recipient.UserID)
+		}
+		fmt.Printf("%s  %-28s  %s  %s\n", at.Local().Format(time.TimeOnly), d.RoutingKey, d.MessageID, summary)
+		if *showBody {
+			fmt.Printf("  %s\n", d.Body)
+		}
+	})
+}
+
+// tailRecord is a tailed message in -json output.
+type tailRecord struct {
+	MessageID  string                 `json:"message_id"`
+	RoutingKey string                 `json:"routing_key"`
+	Timestamp  time.Time              `json:"timestamp"`
+	Headers    map[string]interface{} `json:"headers,omitempty"`
+	Body       json.RawMessage        `json:"body"`
+}
+
+// jsonOrString returns body if it is valid JSON, and otherwise body as a JSON string.
+func jsonOrString(body []byte) []byte {
+	if json.Valid(body) {
+		return body
+	}
+	quoted, _ := json.Marshal(string(body))
+	return quoted
+}
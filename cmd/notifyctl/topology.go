@@ -0,0 +1,65 @@
+// cmd/notifyctl/topology.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"notification-service/constants"
+)
+
+func runTopology(c *client, args []string) error {
+	flags := flag.NewFlagSet("topology", flag.ExitOnError)
+	offline := flags.Bool("offline", false, "do not connect to RabbitMQ for message and consumer counts")
+	flags.Parse(args)
+
+	queues := append(append([]string(nil), constants.QueueOrder...), constants.DeadLetterQueue)
+	counts := make(map[string]string, len(queues))
+	if !*offline {
+		conn, err := c.connect()
+		if err != nil {
+			return fmt.Errorf("%w (use -offline to print the topology without counts)", err)
+		}
+		defer conn.Close()
+		for _, queue := range queues {
+			info, err := conn.InspectQueue(queue)
+			if err != nil {
+				counts[queue] = "not declared\t"
+				continue
+			}
+			counts[queue] = fmt.Sprintf("%d\t%d", info.Messages, info.Consumers)
+		}
+	}
+
+	fmt.Printf("Exchange %s (topic, durable)\n\n", constants.ExchangeName)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	if *offline {
+		fmt.Fprintln(w, "QUEUE\tROUTING KEYS")
+	} else {
+		fmt.Fprintln(w, "QUEUE\tROUTING KEYS\tMESSAGES\tCONSUMERS")
+	}
+	for _, queue := range queues {
+		keys := strings.Join(constants.QueueBindings[queue], ", ")
+		if queue == constants.DeadLetterQueue {
+			keys = "(default exchange; dead-lettered by the service)"
+		}
+		if *offline {
+			fmt.Fprintf(w, "%s\t%s\n", queue, keys)
+		} else {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", queue, keys, counts[queue])
+		}
+	}
+	w.Flush()
+
+	fmt.Println()
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NOTIFICATION TYPE\tROUTING KEY")
+	for _, notificationType := range sortedKeys(constants.NotificationRoutingKeys) {
+		fmt.Fprintf(w, "%s\t%s\n", notificationType, constants.NotificationRoutingKeys[notificationType])
+	}
+	w.Flush()
+	return nil
+}
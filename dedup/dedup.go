@@ -0,0 +1,72 @@
+// dedup/dedup.go
+package dedup
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Scopes an event can be marked done in. Channel scopes let a retry skip the
+// channels that already succeeded.
+const (
+	ScopeMessage = "message"
+	ScopeEmail   = "email"
+)
+
+// PushScope is the scope for a push to a single device token.
+func PushScope(token string) string {
+	return "push:" + token
+}
+
+// Deduplicator tracks which events (and which channels of each event) were already handled.
+type Deduplicator struct {
+	store Store
+	ttl   time.Duration
+}
+
+// NewDeduplicator creates a deduplicator that remembers events for ttl
+func NewDeduplicator(store Store, ttl time.Duration) *Deduplicator {
+	return &Deduplicator{store: store, ttl: ttl}
+}
+
+// Done reports whether the event was already handled in scope.
+// Store errors are logged and treated as "not done": a duplicate beats a lost notification.
+func (d *Deduplicator) Done(eventID, scope string) bool {
+	if eventID == "" {
+		return false
+	}
+	seen, err := d.store.Seen(eventID + "|" + scope)
+	if err != nil {
+		log.Printf("Dedup store lookup failed for event %s (%s): %v", eventID, scope, err)
+		return false
+	}
+	return seen
+}
+
+// MarkDone records that the event was handled in scope.
+func (d *Deduplicator) MarkDone(eventID, scope string) {
+	if eventID == "" {
+		return
+	}
+	if err := d.store.Mark(eventID+"|"+scope, d.ttl); err != nil {
+		log.Printf("Failed to record event %s (%s) as done: %v", eventID, scope, err)
+	}
+}
+
+// Run prunes expired keys from the store every hour until ctx is cancelled
+func (d *Deduplicator) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := d.store.Prune(now); err != nil {
+				log.Printf("Failed to prune expired dedup keys: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,36 @@
+// dedup/dedup_test.go
+package dedup
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDeduplicatorScopesAreIndependent(t *testing.T) {
+	backend := openTestStore(t, filepath.Join(t.TempDir(), "dedup.db"))
+	d := NewDeduplicator(NewLayeredStore(NewMemoryStore(10), backend, time.Hour), time.Hour)
+
+	d.MarkDone("e1", ScopeEmail)
+	tests := []struct {
+		eventID, scope string
+		want           bool
+	}{
+		{"e1", ScopeEmail, true},
+		{"e1", PushScope("token-1"), false},
+		{"e1", ScopeMessage, false},
+		{"e2", ScopeEmail, false},
+		{"", ScopeEmail, false}, // Events without an ID are never deduplicated
+	}
+	for _, tt := range tests {
+		if got := d.Done(tt.eventID, tt.scope); got != tt.want {
+			t.Errorf("Done(%q, %q) = %v, want %v", tt.eventID, tt.scope, got, tt.want)
+		}
+	}
+
+	// A fresh cache still finds the key in the backend, as after a restart.
+	restarted := NewDeduplicator(NewLayeredStore(NewMemoryStore(10), backend, time.Hour), time.Hour)
+	if !restarted.Done("e1", ScopeEmail) {
+		t.Error("Done(e1, email) = false with an empty cache, want true from the backend")
+	}
+}
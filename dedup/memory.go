@@ -0,0 +1,83 @@
+// dedup/memory.go
+package dedup
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+type entry struct {
+	key     string
+	expires time.Time
+}
+
+// MemoryStore is a size-bounded LRU of keys with per-key expiry.
+type MemoryStore struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List // front = most recently used
+	items    map[string]*list.Element
+}
+
+// NewMemoryStore creates an LRU store holding at most capacity keys
+func NewMemoryStore(capacity int) *MemoryStore {
+	return &MemoryStore{
+		capacity: capacity,
+		order:    list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+// Seen reports whether key is present and unexpired
+func (s *MemoryStore) Seen(key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	el, ok := s.items[key]
+	if !ok {
+		return false, nil
+	}
+	if time.Now().After(el.Value.(*entry).expires) {
+		s.order.Remove(el)
+		delete(s.items, key)
+		return false, nil
+	}
+	s.order.MoveToFront(el)
+	return true, nil
+}
+
+// Mark records key until ttl elapses, evicting the least recently used key when full
+func (s *MemoryStore) Mark(key string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expires := time.Now().Add(ttl)
+	if el, ok := s.items[key]; ok {
+		el.Value.(*entry).expires = expires
+		s.order.MoveToFront(el)
+		return nil
+	}
+
+	s.items[key] = s.order.PushFront(&entry{key: key, expires: expires})
+	for s.order.Len() > s.capacity {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.items, oldest.Value.(*entry).key)
+	}
+	return nil
+}
+
+// Prune drops the keys that expired at or before now
+func (s *MemoryStore) Prune(now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, el := range s.items {
+		if !el.Value.(*entry).expires.After(now) {
+			s.order.Remove(el)
+			delete(s.items, key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+// dedup/memory_test.go
+package dedup
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreEvictsLeastRecentlyUsed(t *testing.T) {
+	s := NewMemoryStore(2)
+	s.Mark("a", time.Hour)
+	s.Mark("b", time.Hour)
+	if seen, _ := s.Seen("a"); !seen { // a is now the most recently used
+		t.Fatal("Seen(a) = false, want true")
+	}
+	s.Mark("c", time.Hour)
+
+	for key, want := range map[string]bool{"a": true, "b": false, "c": true} {
+		if seen, _ := s.Seen(key); seen != want {
+			t.Errorf("Seen(%s) = %v, want %v", key, seen, want)
+		}
+	}
+}
+
+func TestMemoryStoreExpiresKeys(t *testing.T) {
+	s := NewMemoryStore(10)
+	s.Mark("short", time.Millisecond)
+	s.Mark("long", time.Hour)
+	time.Sleep(5 * time.Millisecond)
+
+	if seen, _ := s.Seen("short"); seen {
+		t.Error("Seen(short) = true after its ttl, want false")
+	}
+	if seen, _ := s.Seen("long"); !seen {
+		t.Error("Seen(long) = false, want true")
+	}
+
+	s.Mark("pruned", time.Millisecond)
+	if err := s.Prune(time.Now().Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.items) != 1 || s.order.Len() != 1 {
+		t.Errorf("Prune kept %d keys, want only long", len(s.items))
+	}
+}
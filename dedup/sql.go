@@ -0,0 +1,66 @@
+// dedup/sql.go
+package dedup
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"notification-service/sqlstore"
+)
+
+// expires_at is Unix milliseconds so the same queries work on both backends.
+var schema = map[string][]string{
+	sqlstore.DriverSQLite: {
+		`CREATE TABLE IF NOT EXISTS dedup_keys (
+			key TEXT PRIMARY KEY,
+			expires_at INTEGER NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_dedup_keys_expires_at ON dedup_keys (expires_at)`,
+	},
+	sqlstore.DriverPostgres: {
+		`CREATE TABLE IF NOT EXISTS dedup_keys (
+			key TEXT PRIMARY KEY,
+			expires_at BIGINT NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_dedup_keys_expires_at ON dedup_keys (expires_at)`,
+	},
+}
+
+// SQLStore is a Store backed by SQLite or Postgres, so deduplication survives restarts
+// and is shared by every instance using the same database.
+type SQLStore struct {
+	db *sqlstore.DB
+}
+
+// NewSQLStore creates the dedup table in db if needed
+func NewSQLStore(db *sqlstore.DB) (*SQLStore, error) {
+	if err := db.Migrate(schema[db.Driver]); err != nil {
+		return nil, fmt.Errorf("creating dedup schema: %w", err)
+	}
+	return &SQLStore{db: db}, nil
+}
+
+// Seen reports whether key is present and unexpired
+func (s *SQLStore) Seen(key string) (bool, error) {
+	var n int
+	err := s.db.QueryRowContext(context.Background(), s.db.Rebind(
+		`SELECT COUNT(*) FROM dedup_keys WHERE key = ? AND expires_at > ?`),
+		key, time.Now().UnixMilli()).Scan(&n)
+	return n > 0, err
+}
+
+// Mark records key until ttl elapses
+func (s *SQLStore) Mark(key string, ttl time.Duration) error {
+	_, err := s.db.ExecContext(context.Background(), s.db.Rebind(`INSERT INTO dedup_keys (key, expires_at) VALUES (?, ?)
+		ON CONFLICT (key) DO UPDATE SET expires_at = excluded.expires_at`),
+		key, time.Now().Add(ttl).UnixMilli())
+	return err
+}
+
+// Prune deletes the keys that expired at or before now
+func (s *SQLStore) Prune(now time.Time) error {
+	_, err := s.db.ExecContext(context.Background(), s.db.Rebind(
+		`DELETE FROM dedup_keys WHERE expires_at <= ?`), now.UnixMilli())
+	return err
+}
@@ -0,0 +1,66 @@
+// dedup/sql_test.go
+package dedup
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"notification-service/sqlstore"
+)
+
+func openTestStore(t *testing.T, path string) *SQLStore {
+	t.Helper()
+	db, err := sqlstore.Open(sqlstore.DriverSQLite, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	store, err := NewSQLStore(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func TestSQLStoreKeepsKeysAcrossReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dedup.db")
+	store := openTestStore(t, path)
+	if err := store.Mark("e1|message", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Mark("e2|message", -time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened := openTestStore(t, path)
+	for key, want := range map[string]bool{"e1|message": true, "e2|message": false, "e3|message": false} {
+		seen, err := reopened.Seen(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if seen != want {
+			t.Errorf("Seen(%s) = %v after reopening, want %v", key, seen, want)
+		}
+	}
+}
+
+func TestSQLStorePrunesExpiredKeys(t *testing.T) {
+	store := openTestStore(t, filepath.Join(t.TempDir(), "dedup.db"))
+	store.Mark("old", time.Minute)
+	store.Mark("new", time.Hour)
+
+	if err := store.Prune(time.Now().Add(10 * time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	var n int
+	if err := store.db.QueryRow(`SELECT COUNT(*) FROM dedup_keys`).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Errorf("%d keys left after Prune, want 1", n)
+	}
+	if seen, _ := store.Seen("new"); !seen {
+		t.Error("Seen(new) = false after Prune, want true")
+	}
+}
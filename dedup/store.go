@@ -0,0 +1,63 @@
+// dedup/store.go
+package dedup
+
+import "time"
+
+// Store remembers processed keys for a limited time.
+type Store interface {
+	// Seen reports whether key was marked and has not expired.
+	Seen(key string) (bool, error)
+	// Mark records key until ttl elapses.
+	Mark(key string, ttl time.Duration) error
+	// Prune deletes the keys that expired at or before now.
+	Prune(now time.Time) error
+}
+
+// LayeredStore puts an in-memory cache in front of a persistent backend so
+// most lookups never touch the backend.
+type LayeredStore struct {
+	cache   *MemoryStore
+	backend Store
+	ttl     time.Duration
+}
+
+// NewLayeredStore creates a store that checks cache first and falls back to backend.
+// Keys found only in the backend are cached for cacheTTL.
+func NewLayeredStore(cache *MemoryStore, backend Store, cacheTTL time.Duration) *LayeredStore {
+	return &LayeredStore{
+		cache:   cache,
+		backend: backend,
+		ttl:     cacheTTL,
+	}
+}
+
+// Seen checks the cache, then the backend
+func (s *LayeredStore) Seen(key string) (bool, error) {
+	if seen, _ := s.cache.Seen(key); seen {
+		return true, nil
+	}
+	seen, err := s.backend.Seen(key)
+	if err != nil {
+		return false, err
+	}
+	if seen {
+		_ = s.cache.Mark(key, s.ttl)
+	}
+	return seen, nil
+}
+
+// Mark writes to the backend first so a crash never leaves a key only in memory
+func (s *LayeredStore) Mark(key string, ttl time.Duration) error {
+	if err := s.backend.Mark(key, ttl); err != nil {
+		return err
+	}
+	return s.cache.Mark(key, ttl)
+}
+
+// Prune prunes the cache and the backend
+func (s *LayeredStore) Prune(now time.Time) error {
+	if err := s.cache.Prune(now); err != nil {
+		return err
+	}
+	return s.backend.Prune(now)
+}
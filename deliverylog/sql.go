@@ -0,0 +1,319 @@
+// deliverylog/sql.go
+package deliverylog
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"notification-service/sqlstore"
+)
+
+// Supported SQL backends.
+const (
+	DriverSQLite   = sqlstore.DriverSQLite
+	DriverPostgres = sqlstore.DriverPostgres
+)
+
+// maxLimit caps Query.Limit so a single query cannot load the whole log.
+const maxLimit = 1000
+
+// Timestamps are stored as Unix milliseconds so the same queries work on both backends.
+var schema = map[string][]string{
+	DriverSQLite: {
+		`CREATE TABLE IF NOT EXISTS delivery_messages (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			event_id TEXT NOT NULL DEFAULT '',
+			notification_type TEXT NOT NULL,
+			user_id TEXT NOT NULL,
+			application_id INTEGER NOT NULL DEFAULT 0,
+			opportunity_id INTEGER NOT NULL DEFAULT 0,
+			queue TEXT NOT NULL DEFAULT '',
+			routing_key TEXT NOT NULL DEFAULT '',
+			status TEXT NOT NULL,
+			error TEXT NOT NULL DEFAULT '',
+			received_at INTEGER NOT NULL,
+			processed_at INTEGER NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS delivery_attempts (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			event_id TEXT NOT NULL DEFAULT '',
+			notification_type TEXT NOT NULL,
+			user_id TEXT NOT NULL,
+			application_id INTEGER NOT NULL DEFAULT 0,
+			opportunity_id INTEGER NOT NULL DEFAULT 0,
+			channel TEXT NOT NULL,
+			provider TEXT NOT NULL DEFAULT '',
+			status TEXT NOT NULL,
+			reason TEXT NOT NULL DEFAULT '',
+			provider_message_id TEXT NOT NULL DEFAULT '',
+			error TEXT NOT NULL DEFAULT '',
+			latency_ms INTEGER NOT NULL DEFAULT 0,
+			attempt_number INTEGER NOT NULL,
+			created_at INTEGER NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS delivery_engagements (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			event_id TEXT NOT NULL DEFAULT '',
+			notification_type TEXT NOT NULL,
+			user_id TEXT NOT NULL,
+			application_id INTEGER NOT NULL DEFAULT 0,
+			opportunity_id INTEGER NOT NULL DEFAULT 0,
+			kind TEXT NOT NULL,
+			url TEXT NOT NULL DEFAULT '',
+			created_at INTEGER NOT NULL
+		)`,
+	},
+	DriverPostgres: {
+		`CREATE TABLE IF NOT EXISTS delivery_messages (
+			id BIGSERIAL PRIMARY KEY,
+			event_id TEXT NOT NULL DEFAULT '',
+			notification_type TEXT NOT NULL,
+			user_id TEXT NOT NULL,
+			application_id BIGINT NOT NULL DEFAULT 0,
+			opportunity_id BIGINT NOT NULL DEFAULT 0,
+			queue TEXT NOT NULL DEFAULT '',
+			routing_key TEXT NOT NULL DEFAULT '',
+			status TEXT NOT NULL,
+			error TEXT NOT NULL DEFAULT '',
+			received_at BIGINT NOT NULL,
+			processed_at BIGINT NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS delivery_attempts (
+			id BIGSERIAL PRIMARY KEY,
+			event_id TEXT NOT NULL DEFAULT '',
+			notification_type TEXT NOT NULL,
+			user_id TEXT NOT NULL,
+			application_id BIGINT NOT NULL DEFAULT 0,
+			opportunity_id BIGINT NOT NULL DEFAULT 0,
+			channel TEXT NOT NULL,
+			provider TEXT NOT NULL DEFAULT '',
+			status TEXT NOT NULL,
+			reason TEXT NOT NULL DEFAULT '',
+			provider_message_id TEXT NOT NULL DEFAULT '',
+			error TEXT NOT NULL DEFAULT '',
+			latency_ms BIGINT NOT NULL DEFAULT 0,
+			attempt_number INTEGER NOT NULL,
+			created_at BIGINT NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS delivery_engagements (
+			id BIGSERIAL PRIMARY KEY,
+			event_id TEXT NOT NULL DEFAULT '',
+			notification_type TEXT NOT NULL,
+			user_id TEXT NOT NULL,
+			application_id BIGINT NOT NULL DEFAULT 0,
+			opportunity_id BIGINT NOT NULL DEFAULT 0,
+			kind TEXT NOT NULL,
+			url TEXT NOT NULL DEFAULT '',
+			created_at BIGINT NOT NULL
+		)`,
+	},
+}
+
+// Indexes are identical on both backends.
+var indexes = []string{
+	`CREATE INDEX IF NOT EXISTS idx_delivery_messages_user ON delivery_messages (user_id, received_at)`,
+	`CREATE INDEX IF NOT EXISTS idx_delivery_messages_event ON delivery_messages (event_id)`,
+	`CREATE INDEX IF NOT EXISTS idx_delivery_messages_application ON delivery_messages (application_id)`,
+	`CREATE INDEX IF NOT EXISTS idx_delivery_messages_opportunity ON delivery_messages (opportunity_id)`,
+	`CREATE INDEX IF NOT EXISTS idx_delivery_attempts_user ON delivery_attempts (user_id, created_at)`,
+	`CREATE INDEX IF NOT EXISTS idx_delivery_attempts_event ON delivery_attempts (event_id, channel)`,
+	`CREATE INDEX IF NOT EXISTS idx_delivery_attempts_application ON delivery_attempts (application_id)`,
+	`CREATE INDEX IF NOT EXISTS idx_delivery_attempts_opportunity ON delivery_attempts (opportunity_id)`,
+	`CREATE INDEX IF NOT EXISTS idx_delivery_engagements_user ON delivery_engagements (user_id, created_at)`,
+	`CREATE INDEX IF NOT EXISTS idx_delivery_engagements_event ON delivery_engagements (event_id, kind)`,
+}
+
+// SQLStore is a Store backed by SQLite or Postgres.
+type SQLStore struct {
+	db *sqlstore.DB
+}
+
+// Open connects to the delivery log database and creates the schema if needed.
+// driver is "sqlite" (dsn is a file path or file: URI) or "postgres" (dsn is a
+// connection URL).
+func Open(driver, dsn string) (*SQLStore, error) {
+	db, err := sqlstore.Open(driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Migrate(append(schema[driver], indexes...)); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("creating delivery log schema: %w", err)
+	}
+	return &SQLStore{db: db}, nil
+}
+
+// Close closes the database
+func (s *SQLStore) Close() error {
+	return s.db.Close()
+}
+
+// RecordMessage stores the outcome of processing a message
+func (s *SQLStore) RecordMessage(ctx context.Context, m Message) error {
+	_, err := s.db.ExecContext(ctx, s.db.Rebind(`INSERT INTO delivery_messages
+		(event_id, notification_type, user_id, application_id, opportunity_id, queue, routing_key, status, error, received_at, processed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`),
+		m.EventID, m.NotificationType, m.UserID, m.ApplicationID, m.OpportunityID, m.Queue, m.RoutingKey,
+		m.Status, m.Error, m.ReceivedAt.UnixMilli(), m.ProcessedAt.UnixMilli())
+	return err
+}
+
+// RecordAttempt stores a channel attempt
+func (s *SQLStore) RecordAttempt(ctx context.Context, a Attempt) error {
+	if a.AttemptNumber == 0 {
+		a.AttemptNumber = 1
+		if a.EventID != "" {
+			var previous int
+			err := s.db.QueryRowContext(ctx, s.db.Rebind(
+				`SELECT COUNT(*) FROM delivery_attempts WHERE event_id = ? AND channel = ?`),
+				a.EventID, a.Channel).Scan(&previous)
+			if err != nil {
+				return err
+			}
+			a.AttemptNumber = previous + 1
+		}
+	}
+
+	_, err := s.db.ExecContext(ctx, s.db.Rebind(`INSERT INTO delivery_attempts
+		(event_id, notification_type, user_id, application_id, opportunity_id, channel, provider, status, reason,
+		 provider_message_id, error, latency_ms, attempt_number, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`),
+		a.EventID, a.NotificationType, a.UserID, a.ApplicationID, a.OpportunityID, a.Channel, a.Provider, a.Status,
+		a.Reason, a.ProviderMessageID, a.Error, a.Latency.Milliseconds(), a.AttemptNumber, a.CreatedAt.UnixMilli())
+	return err
+}
+
+// QueryMessages returns matching messages, newest first
+func (s *SQLStore) QueryMessages(ctx context.Context, q Query) ([]Message, error) {
+	where, args := s.filters(q, "received_at")
+	rows, err := s.db.QueryContext(ctx, s.db.Rebind(`SELECT id, event_id, notification_type, user_id, application_id,
+		opportunity_id, queue, routing_key, status, error, received_at, processed_at
+		FROM delivery_messages`+where+` ORDER BY received_at DESC, id DESC LIMIT `+strconv.Itoa(limit(q))), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []Message
+	for rows.Next() {
+		var m Message
+		var receivedAt, processedAt int64
+		err := rows.Scan(&m.ID, &m.EventID, &m.NotificationType, &m.UserID, &m.ApplicationID, &m.OpportunityID,
+			&m.Queue, &m.RoutingKey, &m.Status, &m.Error, &receivedAt, &processedAt)
+		if err != nil {
+			return nil, err
+		}
+		m.ReceivedAt = time.UnixMilli(receivedAt).UTC()
+		m.ProcessedAt = time.UnixMilli(processedAt).UTC()
+		result = append(result, m)
+	}
+	return result, rows.Err()
+}
+
+// QueryAttempts returns matching attempts, newest first
+func (s *SQLStore) QueryAttempts(ctx context.Context, q Query) ([]Attempt, error) {
+	where, args := s.filters(q, "created_at")
+	rows, err := s.db.QueryContext(ctx, s.db.Rebind(`SELECT id, event_id, notification_type, user_id, application_id,
+		opportunity_id, channel, provider, status, reason, provider_message_id, error, latency_ms, attempt_number, created_at
+		FROM delivery_attempts`+where+` ORDER BY created_at DESC, id DESC LIMIT `+strconv.Itoa(limit(q))), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []Attempt
+	for rows.Next() {
+		var a Attempt
+		var latencyMs, createdAt int64
+		err := rows.Scan(&a.ID, &a.EventID, &a.NotificationType, &a.UserID, &a.ApplicationID, &a.OpportunityID,
+			&a.Channel, &a.Provider, &a.Status, &a.Reason, &a.ProviderMessageID, &a.Error, &latencyMs,
+			&a.AttemptNumber, &createdAt)
+		if err != nil {
+			return nil, err
+		}
+		a.Latency = time.Duration(latencyMs) * time.Millisecond
+		a.CreatedAt = time.UnixMilli(createdAt).UTC()
+		result = append(result, a)
+	}
+	return result, rows.Err()
+}
+
+// RecordEngagement stores an email open or click
+func (s *SQLStore) RecordEngagement(ctx context.Context, e Engagement) error {
+	_, err := s.db.ExecContext(ctx, s.db.Rebind(`INSERT INTO delivery_engagements
+		(event_id, notification_type, user_id, application_id, opportunity_id, kind, url, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`),
+		e.EventID, e.NotificationType, e.UserID, e.ApplicationID, e.OpportunityID, e.Kind, e.URL, e.CreatedAt.UnixMilli())
+	return err
+}
+
+// QueryEngagements returns matching opens and clicks, newest first
+func (s *SQLStore) QueryEngagements(ctx context.Context, q Query) ([]Engagement, error) {
+	where, args := s.filters(q, "created_at")
+	rows, err := s.db.QueryContext(ctx, s.db.Rebind(`SELECT id, event_id, notification_type, user_id, application_id,
+		opportunity_id, kind, url, created_at
+		FROM delivery_engagements`+where+` ORDER BY created_at DESC, id DESC LIMIT `+strconv.Itoa(limit(q))), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []Engagement
+	for rows.Next() {
+		var e Engagement
+		var createdAt int64
+		err := rows.Scan(&e.ID, &e.EventID, &e.NotificationType, &e.UserID, &e.ApplicationID, &e.OpportunityID,
+			&e.Kind, &e.URL, &createdAt)
+		if err != nil {
+			return nil, err
+		}
+		e.CreatedAt = time.UnixMilli(createdAt).UTC()
+		result = append(result, e)
+	}
+	return result, rows.Err()
+}
+
+// filters builds the WHERE clause for a query; timeColumn is the column Since/Until apply to.
+func (s *SQLStore) filters(q Query, timeColumn string) (string, []interface{}) {
+	var clauses []string
+	var args []interface{}
+	add := func(clause string, arg interface{}) {
+		clauses = append(clauses, clause)
+		args = append(args, arg)
+	}
+
+	if q.EventID != "" {
+		add("event_id = ?", q.EventID)
+	}
+	if q.UserID != "" {
+		add("user_id = ?", q.UserID)
+	}
+	if q.ApplicationID != 0 {
+		add("application_id = ?", q.ApplicationID)
+	}
+	if q.OpportunityID != 0 {
+		add("opportunity_id = ?", q.OpportunityID)
+	}
+	if !q.Since.IsZero() {
+		add(timeColumn+" >= ?", q.Since.UnixMilli())
+	}
+	if !q.Until.IsZero() {
+		add(timeColumn+" < ?", q.Until.UnixMilli())
+	}
+
+	if len(clauses) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(clauses, " AND "), args
+}
+
+// limit returns the number of rows a query returns: 100 by default, at most maxLimit.
+func limit(q Query) int {
+	if q.Limit <= 0 {
+		return 100
+	}
+	return min(q.Limit, maxLimit)
+}
@@ -0,0 +1,247 @@
+// deliverylog/sql_test.go
+package deliverylog
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func openTestStore(t *testing.T) *SQLStore {
+	t.Helper()
+	store, err := Open(DriverSQLite, filepath.Join(t.TempDir(), "log.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestSQLStoreMessageRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+
+	received := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	want := Message{
+		EventID:          "e1",
+		NotificationType: "NGO_NEW_APPLICATION",
+		UserID:           "u1",
+		ApplicationID:    7,
+		OpportunityID:    3,
+		Queue:            "ngo_email_queue",
+		RoutingKey:       "ngo.application.new",
+		Status:           MessageFailed,
+		Error:            "smtp down",
+		ReceivedAt:       received,
+		ProcessedAt:      received.Add(250 * time.Millisecond),
+	}
+	if err := store.RecordMessage(ctx, want); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := store.QueryMessages(ctx, Query{EventID: "e1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("got %d messages, want 1", len(got))
+	}
+	want.ID = got[0].ID
+	if got[0] != want {
+		t.Errorf("got %+v, want %+v", got[0], want)
+	}
+}
+
+func TestSQLStoreQueryFilters(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+
+	base := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	records := []Message{
+		{EventID: "e1", UserID: "u1", ApplicationID: 1, OpportunityID: 10, ReceivedAt: base},
+		{EventID: "e2", UserID: "u1", ApplicationID: 2, OpportunityID: 10, ReceivedAt: base.Add(time.Hour)},
+		{EventID: "e3", UserID: "u2", ApplicationID: 3, OpportunityID: 20, ReceivedAt: base.Add(2 * time.Hour)},
+	}
+	for _, m := range records {
+		m.Status = MessageProcessed
+		m.ProcessedAt = m.ReceivedAt
+		if err := store.RecordMessage(ctx, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		query Query
+		want  []string
+	}{
+		{"all, newest first", Query{}, []string{"e3", "e2", "e1"}},
+		{"event", Query{EventID: "e2"}, []string{"e2"}},
+		{"user", Query{UserID: "u1"}, []string{"e2", "e1"}},
+		{"application", Query{ApplicationID: 3}, []string{"e3"}},
+		{"opportunity", Query{OpportunityID: 10}, []string{"e2", "e1"}},
+		{"since", Query{Since: base.Add(time.Hour)}, []string{"e3", "e2"}},
+		{"until is exclusive", Query{Until: base.Add(time.Hour)}, []string{"e1"}},
+		{"combined", Query{UserID: "u1", Since: base.Add(30 * time.Minute)}, []string{"e2"}},
+		{"limit", Query{Limit: 2}, []string{"e3", "e2"}},
+		{"no match", Query{UserID: "u3"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := store.QueryMessages(ctx, tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var ids []string
+			for _, m := range got {
+				ids = append(ids, m.EventID)
+			}
+			if len(ids) != len(tt.want) {
+				t.Fatalf("got %v, want %v", ids, tt.want)
+			}
+			for i := range ids {
+				if ids[i] != tt.want[i] {
+					t.Fatalf("got %v, want %v", ids, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestLimit(t *testing.T) {
+	tests := []struct {
+		limit int
+		want  int
+	}{
+		{0, 100},
+		{-5, 100},
+		{50, 50},
+		{1000, 1000},
+		{5000, 1000},
+	}
+	for _, tt := range tests {
+		if got := limit(Query{Limit: tt.limit}); got != tt.want {
+			t.Errorf("limit(%d) = %d, want %d", tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestSQLStoreAttemptNumbers(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	attempts := []Attempt{
+		{EventID: "e1", Channel: "email", Status: StatusFailed, Error: "timeout", CreatedAt: now},
+		{EventID: "e1", Channel: "email", Status: StatusSent, Latency: 120 * time.Millisecond, CreatedAt: now.Add(time.Minute)},
+		{EventID: "e1", Channel: "push", Status: StatusSkipped, Reason: ReasonNoDeviceToken, CreatedAt: now.Add(2 * time.Minute)},
+	}
+	for _, a := range attempts {
+		a.NotificationType = "NGO_NEW_APPLICATION"
+		a.UserID = "u1"
+		if err := store.RecordAttempt(ctx, a); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := store.QueryAttempts(ctx, Query{EventID: "e1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("got %d attempts, want 3", len(got))
+	}
+	// Newest first: push #1, email #2, email #1.
+	wants := []struct {
+		channel string
+		number  int
+	}{{"push", 1}, {"email", 2}, {"email", 1}}
+	for i, want := range wants {
+		if got[i].Channel != want.channel || got[i].AttemptNumber != want.number {
+			t.Errorf("attempt %d = %s #%d, want %s #%d", i, got[i].Channel, got[i].AttemptNumber, want.channel, want.number)
+		}
+	}
+	if got[1].Latency != 120*time.Millisecond {
+		t.Errorf("latency = %v, want 120ms", got[1].Latency)
+	}
+	if got[0].Reason != ReasonNoDeviceToken {
+		t.Errorf("reason = %q, want %q", got[0].Reason, ReasonNoDeviceToken)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+
+	if _, err := Summarize(ctx, store, "e1"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Summarize of unknown event: err = %v, want ErrNotFound", err)
+	}
+
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	if err := store.RecordMessage(ctx, Message{EventID: "e1", Status: MessageFailed, ReceivedAt: now, ProcessedAt: now}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.RecordAttempt(ctx, Attempt{EventID: "e1", Channel: "email", Status: StatusFailed, CreatedAt: now}); err != nil {
+		t.Fatal(err)
+	}
+	later := now.Add(time.Minute)
+	if err := store.RecordMessage(ctx, Message{EventID: "e1", Status: MessageProcessed, ReceivedAt: later, ProcessedAt: later}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.RecordAttempt(ctx, Attempt{EventID: "e1", Channel: "email", Status: StatusSent, CreatedAt: later}); err != nil {
+		t.Fatal(err)
+	}
+	// Records of other events are not included.
+	if err := store.RecordMessage(ctx, Message{EventID: "e2", Status: MessageFailed, ReceivedAt: later, ProcessedAt: later}); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := Summarize(ctx, store, "e1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.EventID != "e1" || s.Status != MessageProcessed {
+		t.Errorf("summary = %s/%s, want e1/%s", s.EventID, s.Status, MessageProcessed)
+	}
+	if len(s.Messages) != 2 || len(s.Attempts) != 2 {
+		t.Errorf("got %d messages and %d attempts, want 2 and 2", len(s.Messages), len(s.Attempts))
+	}
+}
+
+func TestSQLStoreEngagementRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t)
+
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	open := Engagement{EventID: "e1", NotificationType: "NGO_NEW_APPLICATION", UserID: "u1",
+		ApplicationID: 7, OpportunityID: 3, Kind: EngagementOpen, CreatedAt: now}
+	click := open
+	click.Kind = EngagementClick
+	click.URL = "https://example.org/applications/7"
+	click.CreatedAt = now.Add(time.Minute)
+	for _, e := range []Engagement{open, click} {
+		if err := store.RecordEngagement(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.RecordEngagement(ctx, Engagement{EventID: "e2", UserID: "u2", Kind: EngagementOpen, CreatedAt: now}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := store.QueryEngagements(ctx, Query{UserID: "u1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d engagements, want 2", len(got))
+	}
+	click.ID, open.ID = got[0].ID, got[1].ID
+	if got[0] != click {
+		t.Errorf("got %+v, want %+v", got[0], click)
+	}
+	if got[1] != open {
+		t.Errorf("got %+v, want %+v", got[1], open)
+	}
+}
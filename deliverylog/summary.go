@@ -0,0 +1,49 @@
+// deliverylog/summary.go
+package deliverylog
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotFound is returned when the log has no record of a notification.
+var ErrNotFound = errors.New("notification not found")
+
+// Summary is everything the log knows about one notification, for status lookups.
+type Summary struct {
+	EventID string `json:"id"`
+	// Status is the outcome of the most recent processing of the message
+	// (processed, failed, deferred, duplicate or invalid).
+	Status   string    `json:"status"`
+	Messages []Message `json:"messages"`
+	Attempts []Attempt `json:"attempts"`
+}
+
+// Summarize returns the message and channel attempt records for an event ID, or
+// ErrNotFound if it has not been processed yet.
+func Summarize(ctx context.Context, store Store, eventID string) (Summary, error) {
+	q := Query{EventID: eventID, Limit: 1000}
+	messages, err := store.QueryMessages(ctx, q)
+	if err != nil {
+		return Summary{}, err
+	}
+	attempts, err := store.QueryAttempts(ctx, q)
+	if err != nil {
+		return Summary{}, err
+	}
+	if len(messages) == 0 && len(attempts) == 0 {
+		return Summary{}, ErrNotFound
+	}
+
+	s := Summary{EventID: eventID, Messages: messages, Attempts: attempts}
+	if s.Messages == nil {
+		s.Messages = []Message{}
+	}
+	if s.Attempts == nil {
+		s.Attempts = []Attempt{}
+	}
+	if len(messages) > 0 {
+		s.Status = messages[0].Status // Newest first
+	}
+	return s, nil
+}
@@ -0,0 +1,116 @@
+// deliverylog/types.go
+package deliverylog
+
+import (
+	"context"
+	"time"
+)
+
+// Message statuses: the outcome of processing one incoming message.
+const (
+	MessageProcessed = "processed"
+	MessageFailed    = "failed"
+	MessageDeferred  = "deferred"  // Held back by quiet hours
+	MessageDuplicate = "duplicate" // Already processed; skipped by deduplication
+	MessageInvalid   = "invalid"   // Could not be decoded
+)
+
+// Attempt statuses: the outcome of one channel delivery attempt.
+const (
+	StatusSent       = "sent"
+	StatusFailed     = "failed"
+	StatusSkipped    = "skipped"    // Preferences, missing address/token or unconfigured provider
+	StatusSuppressed = "suppressed" // Frequency capping
+	StatusDigested   = "digested"   // Buffered into an hourly/daily digest
+)
+
+// Reasons recorded with skipped attempts.
+const (
+	ReasonPrefDisabled   = "pref disabled"
+	ReasonNoEmailAddress = "no email address"
+	ReasonNoDeviceToken  = "no device token"
+	ReasonNoProvider     = "push provider not configured"
+)
+
+// Message is the audit record of one processed notification message.
+type Message struct {
+	ID               int64     `json:"id"`
+	EventID          string    `json:"event_id,omitempty"`
+	NotificationType string    `json:"notification_type"`
+	UserID           string    `json:"user_id"`
+	ApplicationID    int       `json:"application_id,omitempty"`
+	OpportunityID    int       `json:"opportunity_id,omitempty"`
+	Queue            string    `json:"queue,omitempty"`
+	RoutingKey       string    `json:"routing_key,omitempty"`
+	Status           string    `json:"status"`
+	Error            string    `json:"error,omitempty"`
+	ReceivedAt       time.Time `json:"received_at"`
+	ProcessedAt      time.Time `json:"processed_at"`
+}
+
+// Attempt is the audit record of one delivery attempt on one channel.
+type Attempt struct {
+	ID                int64         `json:"id"`
+	EventID           string        `json:"event_id,omitempty"`
+	NotificationType  string        `json:"notification_type"`
+	UserID            string        `json:"user_id"`
+	ApplicationID     int           `json:"application_id,omitempty"`
+	OpportunityID     int           `json:"opportunity_id,omitempty"`
+	Channel           string        `json:"channel"`
+	Provider          string        `json:"provider,omitempty"`
+	Status            string        `json:"status"`
+	Reason            string        `json:"reason,omitempty"` // Why an attempt was skipped or suppressed
+	ProviderMessageID string        `json:"provider_message_id,omitempty"`
+	Error             string        `json:"error,omitempty"`
+	Latency           time.Duration `json:"latency_ns"`
+	AttemptNumber     int           `json:"attempt_number"`
+	CreatedAt         time.Time     `json:"created_at"`
+}
+
+// Engagement kinds recorded by email tracking.
+const (
+	EngagementOpen  = "open"
+	EngagementClick = "click"
+)
+
+// Engagement is an open or click recorded for a sent email.
+type Engagement struct {
+	ID               int64     `json:"id"`
+	EventID          string    `json:"event_id,omitempty"`
+	NotificationType string    `json:"notification_type"`
+	UserID           string    `json:"user_id"`
+	ApplicationID    int       `json:"application_id,omitempty"`
+	OpportunityID    int       `json:"opportunity_id,omitempty"`
+	Kind             string    `json:"kind"`
+	URL              string    `json:"url,omitempty"` // The link clicked
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// Query filters delivery records. Zero-valued fields are ignored.
+type Query struct {
+	EventID       string
+	UserID        string
+	ApplicationID int
+	OpportunityID int
+	Since         time.Time
+	Until         time.Time
+	Limit         int // Defaults to 100; at most 1000
+}
+
+// Store persists the delivery log.
+type Store interface {
+	// RecordMessage stores the outcome of processing a message.
+	RecordMessage(ctx context.Context, m Message) error
+	// RecordAttempt stores a channel attempt. A zero AttemptNumber is filled in
+	// from the number of earlier attempts for the same event and channel.
+	RecordAttempt(ctx context.Context, a Attempt) error
+	// QueryMessages returns matching messages, newest first.
+	QueryMessages(ctx context.Context, q Query) ([]Message, error)
+	// QueryAttempts returns matching attempts, newest first.
+	QueryAttempts(ctx context.Context, q Query) ([]Attempt, error)
+	// RecordEngagement stores an email open or click.
+	RecordEngagement(ctx context.Context, e Engagement) error
+	// QueryEngagements returns matching opens and clicks, newest first.
+	QueryEngagements(ctx context.Context, q Query) ([]Engagement, error)
+	Close() error
+}
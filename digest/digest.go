@@ -0,0 +1,75 @@
+// digest/digest.go
+package digest
+
+import (
+	"time"
+
+	"notification-service/models"
+)
+
+// Delivery modes a recipient can choose per notification type.
+const (
+	ModeImmediate = "immediate"
+	ModeHourly    = "hourly"
+	ModeDaily     = "daily"
+)
+
+// ValidMode reports whether mode is a known delivery mode.
+func ValidMode(mode string) bool {
+	return mode == ModeImmediate || mode == ModeHourly || mode == ModeDaily
+}
+
+// WindowEnd returns when a digest window containing t closes: the top of the next hour
+// for hourly digests, or the next local midnight for daily ones.
+func WindowEnd(mode string, t time.Time, loc *time.Location) time.Time {
+	local := t.In(loc)
+	// Built from the local wall clock: Truncate works in absolute time, which puts hour
+	// boundaries at :30 local in zones with half-hour offsets.
+	if mode == ModeHourly {
+		return time.Date(local.Year(), local.Month(), local.Day(), local.Hour()+1, 0, 0, 0, loc)
+	}
+	return time.Date(local.Year(), local.Month(), local.Day()+1, 0, 0, 0, 0, loc)
+}
+
+// Entry is one buffered event in a digest.
+type Entry struct {
+	EventID          string    `json:"event_id,omitempty"`
+	ApplicationID    int       `json:"application_id,omitempty"`
+	OpportunityID    int       `json:"opportunity_id,omitempty"`
+	OpportunityTitle string    `json:"opportunity_title,omitempty"`
+	VolunteerName    string    `json:"volunteer_name,omitempty"`
+	NgoName          string    `json:"ngo_name,omitempty"`
+	Title            string    `json:"title,omitempty"`
+	Body             string    `json:"body,omitempty"`
+	DeepLink         string    `json:"deep_link,omitempty"`
+	ReceivedAt       time.Time `json:"received_at"`
+}
+
+// NewEntry extracts the digest-relevant fields from a notification message.
+func NewEntry(msg models.NotificationMessage) Entry {
+	return Entry{
+		EventID:          msg.EventID,
+		ApplicationID:    msg.Payload.ApplicationID,
+		OpportunityID:    msg.Payload.OpportunityID,
+		OpportunityTitle: msg.Payload.OpportunityTitle,
+		VolunteerName:    msg.Payload.VolunteerName,
+		NgoName:          msg.Payload.NgoName,
+		Title:            msg.Payload.Title,
+		Body:             msg.Payload.Body,
+		DeepLink:         msg.Payload.DeepLink,
+		ReceivedAt:       time.Now().UTC(),
+	}
+}
+
+// Batch is the set of entries buffered for one recipient and notification type.
+type Batch struct {
+	UserID           string    `json:"user_id"`
+	EmailAddress     string    `json:"email_address"`
+	NotificationType string    `json:"notification_type"`
+	Mode             string    `json:"mode"`
+	FlushAt          time.Time `json:"flush_at"`
+	Attempts         int       `json:"attempts"`
+	Entries          []Entry   `json:"entries"`
+
+	lastEntryID int64 // Newest stored entry in Entries, for SQLStore.Complete
+}
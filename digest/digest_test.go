@@ -0,0 +1,39 @@
+// digest/digest_test.go
+package digest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWindowEnd(t *testing.T) {
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		t.Skip("no tzdata:", err)
+	}
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("no tzdata:", err)
+	}
+
+	tests := []struct {
+		name string
+		mode string
+		t    time.Time
+		loc  *time.Location
+		want time.Time
+	}{
+		{"hourly in a half-hour zone", ModeHourly, time.Date(2025, 6, 1, 10, 45, 0, 0, kolkata), kolkata, time.Date(2025, 6, 1, 11, 0, 0, 0, kolkata)},
+		{"hourly just after the hour", ModeHourly, time.Date(2025, 6, 1, 10, 5, 0, 0, kolkata), kolkata, time.Date(2025, 6, 1, 11, 0, 0, 0, kolkata)},
+		{"hourly before midnight", ModeHourly, time.Date(2025, 6, 1, 23, 10, 0, 0, kolkata), kolkata, time.Date(2025, 6, 2, 0, 0, 0, 0, kolkata)},
+		{"daily", ModeDaily, time.Date(2025, 6, 1, 10, 45, 0, 0, kolkata), kolkata, time.Date(2025, 6, 2, 0, 0, 0, 0, kolkata)},
+		{"daily across spring forward", ModeDaily, time.Date(2025, 3, 8, 12, 0, 0, 0, newYork), newYork, time.Date(2025, 3, 9, 0, 0, 0, 0, newYork)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WindowEnd(tt.mode, tt.t, tt.loc); !got.Equal(tt.want) {
+				t.Errorf("WindowEnd() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
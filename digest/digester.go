@@ -0,0 +1,186 @@
+// digest/digester.go
+package digest
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"notification-service/models"
+	"notification-service/preferences"
+	"notification-service/services/email"
+	"notification-service/unsubscribe"
+)
+
+// Retry bounds for batches that fail to send: a batch waits retryDelay between attempts
+// and is dropped after maxAttempts.
+const (
+	retryDelay  = 5 * time.Minute
+	maxAttempts = 10
+)
+
+// Sender sends a rendered digest email.
+type Sender interface {
+	Send(ctx context.Context, m email.Message) (string, error)
+}
+
+// Result is the outcome of one attempt to send a digest.
+type Result struct {
+	Batch             Batch
+	ProviderMessageID string
+	Latency           time.Duration
+	Err               error
+}
+
+// Digester buffers notifications into per-recipient digests and sends them when
+// their window closes.
+type Digester struct {
+	store    Store
+	sender   Sender
+	renderer *Renderer
+	defaults map[string]string // notification type -> default mode
+	interval time.Duration
+	links    *unsubscribe.Links
+	onResult func(Result)
+}
+
+// NewDigester creates a digester. defaults maps notification types to the mode used
+// when a recipient has not chosen one; unlisted types default to immediate delivery.
+func NewDigester(store Store, sender Sender, defaults map[string]string, interval time.Duration) (*Digester, error) {
+	renderer, err := NewRenderer()
+	if err != nil {
+		return nil, err
+	}
+	return &Digester{
+		store:    store,
+		sender:   sender,
+		renderer: renderer,
+		defaults: defaults,
+		interval: interval,
+	}, nil
+}
+
+// DefaultMode returns the configured mode for a notification type.
+func (d *Digester) DefaultMode(notificationType string) string {
+	if mode, ok := d.defaults[notificationType]; ok {
+		return mode
+	}
+	return ModeImmediate
+}
+
+// Add buffers a message into the recipient's open digest. timezone decides where
+// daily windows end; an empty timezone means UTC.
+func (d *Digester) Add(msg models.NotificationMessage, mode, timezone string) error {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	flushAt := WindowEnd(mode, time.Now(), loc)
+
+	err = d.store.Add(msg.Recipient.UserID, msg.Recipient.EmailAddress, msg.NotificationType, mode, flushAt, NewEntry(msg))
+	if err != nil {
+		return err
+	}
+	log.Printf("Buffered %s for %s into %s digest (Flush at %s)",
+		msg.NotificationType, msg.Recipient.UserID, mode, flushAt.UTC().Format(time.RFC3339))
+	return nil
+}
+
+// Run sends due digests until ctx is cancelled
+func (d *Digester) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			d.flushDue(ctx, now)
+		}
+	}
+}
+
+// flushDue renders and sends every due batch, rescheduling failures until they run
+// out of attempts.
+func (d *Digester) flushDue(ctx context.Context, now time.Time) {
+	batches, err := d.store.Due(now)
+	if err != nil {
+		log.Printf("Failed to load due digests: %v", err)
+		return
+	}
+
+	for _, batch := range batches {
+		err := d.send(ctx, batch)
+		if err == nil {
+			d.complete(batch)
+			continue
+		}
+
+		batch.Attempts++
+		if batch.Attempts >= maxAttempts {
+			log.Printf("Giving up on %s digest to %s after %d attempts: %v",
+				batch.NotificationType, batch.UserID, batch.Attempts, err)
+			d.complete(batch)
+			continue
+		}
+		batch.FlushAt = now.Add(retryDelay)
+		log.Printf("Error sending %s digest to %s (Attempt %d): %v. Retrying at %s.",
+			batch.NotificationType, batch.UserID, batch.Attempts, err, batch.FlushAt.UTC().Format(time.RFC3339))
+		if err := d.store.Reschedule(batch); err != nil {
+			log.Printf("Failed to reschedule digest for %s: %v", batch.UserID, err)
+		}
+	}
+}
+
+func (d *Digester) complete(batch Batch) {
+	if err := d.store.Complete(batch); err != nil {
+		log.Printf("Failed to remove sent digest for %s: %v", batch.UserID, err)
+	}
+}
+
+// SetResultHandler makes the digester call fn after every attempt to send a digest,
+// e.g. to record it in the delivery log
+func (d *Digester) SetResultHandler(fn func(Result)) {
+	d.onResult = fn
+}
+
+// SetUnsubscribeLinks makes digests carry an unsubscribe link and List-Unsubscribe headers
+func (d *Digester) SetUnsubscribeLinks(links *unsubscribe.Links) {
+	d.links = links
+}
+
+func (d *Digester) send(ctx context.Context, batch Batch) error {
+	var unsubscribeURL string
+	var headers map[string]string
+	if d.links != nil {
+		unsubscribeURL = d.links.URL(batch.UserID, batch.NotificationType, preferences.ChannelEmail)
+		headers = unsubscribe.Headers(unsubscribeURL)
+	}
+
+	subject, textBody, htmlBody, err := d.renderer.Render(batch, unsubscribeURL)
+	if err != nil {
+		return err
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	start := time.Now()
+	messageID, err := d.sender.Send(sendCtx, email.Message{
+		To:       batch.EmailAddress,
+		Subject:  subject,
+		TextBody: textBody,
+		HTMLBody: htmlBody,
+		Headers:  headers,
+	})
+	if d.onResult != nil {
+		d.onResult(Result{Batch: batch, ProviderMessageID: messageID, Latency: time.Since(start), Err: err})
+	}
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Sent %s digest of %d %s notifications to %s",
+		batch.Mode, len(batch.Entries), batch.NotificationType, batch.UserID)
+	return nil
+}
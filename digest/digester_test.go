@@ -0,0 +1,131 @@
+// digest/digester_test.go
+package digest
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"notification-service/services/email"
+	"notification-service/sqlstore"
+)
+
+type fakeSender struct {
+	err  error
+	sent []email.Message
+}
+
+func (s *fakeSender) Send(ctx context.Context, m email.Message) (string, error) {
+	if s.err != nil {
+		return "", s.err
+	}
+	s.sent = append(s.sent, m)
+	return "msg-1", nil
+}
+
+func openTestStore(t *testing.T) *SQLStore {
+	t.Helper()
+	db, err := sqlstore.Open(sqlstore.DriverSQLite, filepath.Join(t.TempDir(), "digests.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	store, err := NewSQLStore(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func TestSQLStoreKeepsEntriesAddedWhileSending(t *testing.T) {
+	store := openTestStore(t)
+	now := time.Now()
+	next := now.Add(time.Hour)
+	add := func(eventID string, flushAt time.Time) {
+		t.Helper()
+		err := store.Add("ngo-1", "ngo@example.org", "NGO_NEW_APPLICATION", ModeHourly, flushAt, Entry{EventID: eventID})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	add("e1", now.Add(-time.Minute))
+	add("e2", now.Add(-time.Minute))
+
+	due, err := store.Due(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(due) != 1 || len(due[0].Entries) != 2 {
+		t.Fatalf("Due() = %+v, want one batch of two entries", due)
+	}
+
+	// A failed attempt, then a retry during which e3 arrives for the next window.
+	due[0].Attempts = 2
+	if err := store.Reschedule(due[0]); err != nil {
+		t.Fatal(err)
+	}
+	add("e3", next)
+	if err := store.Complete(due[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	if due, _ := store.Due(now); len(due) != 0 {
+		t.Fatalf("Due() after Complete = %+v, want the remaining entry held until its window closes", due)
+	}
+	due, err = store.Due(next)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(due) != 1 || len(due[0].Entries) != 1 || due[0].Entries[0].EventID != "e3" {
+		t.Fatalf("Due() in the next window = %+v, want only the entry added while sending", due)
+	}
+	if !due[0].FlushAt.Equal(next.Truncate(time.Millisecond)) || due[0].Attempts != 0 {
+		t.Fatalf("remaining batch flushes at %s after %d attempts, want %s and 0",
+			due[0].FlushAt, due[0].Attempts, next.Truncate(time.Millisecond))
+	}
+	if err := store.Complete(due[0]); err != nil {
+		t.Fatal(err)
+	}
+	if due, _ := store.Due(next); len(due) != 0 {
+		t.Fatalf("Due() = %+v, want no batches left", due)
+	}
+}
+
+func TestDigesterReportsResultsAndRetries(t *testing.T) {
+	store := openTestStore(t)
+	sender := &fakeSender{err: errors.New("smtp down")}
+	d, err := NewDigester(store, sender, nil, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var results []Result
+	d.SetResultHandler(func(r Result) { results = append(results, r) })
+
+	now := time.Now()
+	err = store.Add("ngo-1", "ngo@example.org", "NGO_NEW_APPLICATION", ModeDaily, now.Add(-time.Minute), Entry{EventID: "e1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d.flushDue(context.Background(), now)
+	if len(results) != 1 || results[0].Err == nil {
+		t.Fatalf("results = %+v, want one failed attempt", results)
+	}
+	if due, _ := store.Due(now); len(due) != 0 {
+		t.Fatalf("failed batch is due again immediately: %+v", due)
+	}
+
+	sender.err = nil
+	d.flushDue(context.Background(), now.Add(retryDelay))
+	if len(results) != 2 || results[1].Err != nil || results[1].ProviderMessageID != "msg-1" || len(results[1].Batch.Entries) != 1 {
+		t.Fatalf("results = %+v, want a successful retry carrying the entry", results)
+	}
+	if len(sender.sent) != 1 {
+		t.Fatalf("sent %d emails, want 1", len(sender.sent))
+	}
+	if due, _ := store.Due(now.Add(24 * time.Hour)); len(due) != 0 {
+		t.Fatalf("Due() = %+v after sending, want none", due)
+	}
+}
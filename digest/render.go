@@ -0,0 +1,105 @@
+// digest/render.go
+package digest
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	texttemplate "text/template"
+
+	"notification-service/constants"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+// headings describe what a digest contains, per notification type.
+var headings = map[string]string{
+	constants.NotificationTypeNgoNewApplication:       "You received %d new applications.",
+	constants.NotificationTypeApplicationWithdrawn:    "%d applications were withdrawn.",
+	constants.NotificationTypeVolunteerNewOpportunity: "%d new opportunities match your interests.",
+	constants.NotificationTypeOpportunityUpdated:      "%d opportunities you applied to were updated.",
+}
+
+// opportunityGroup is the template view of entries for one opportunity.
+type opportunityGroup struct {
+	Title   string
+	Entries []Entry
+}
+
+// templateData is what the digest templates render.
+type templateData struct {
+	Heading       string
+	Mode          string
+	NgoName       string
+	Opportunities []opportunityGroup
+	// UnsubscribeURL turns this notification type off for email. Empty omits the link.
+	UnsubscribeURL string
+}
+
+// Renderer renders digest emails from the embedded templates.
+type Renderer struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+// NewRenderer parses the embedded digest templates
+func NewRenderer() (*Renderer, error) {
+	text, err := texttemplate.ParseFS(templateFS, "templates/digest.txt.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	html, err := htmltemplate.ParseFS(templateFS, "templates/digest.html.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	return &Renderer{text: text, html: html}, nil
+}
+
+// Render returns the subject, plain text and HTML bodies for a batch.
+func (r *Renderer) Render(batch Batch, unsubscribeURL string) (subject, textBody, htmlBody string, err error) {
+	heading, ok := headings[batch.NotificationType]
+	if !ok {
+		heading = "You have %d new notifications."
+	}
+	heading = fmt.Sprintf(heading, len(batch.Entries))
+
+	data := templateData{
+		Heading:        heading,
+		Mode:           batch.Mode,
+		Opportunities:  groupByOpportunity(batch.Entries),
+		UnsubscribeURL: unsubscribeURL,
+	}
+	if len(batch.Entries) > 0 {
+		data.NgoName = batch.Entries[len(batch.Entries)-1].NgoName
+	}
+
+	var text, html bytes.Buffer
+	if err := r.text.Execute(&text, data); err != nil {
+		return "", "", "", err
+	}
+	if err := r.html.Execute(&html, data); err != nil {
+		return "", "", "", err
+	}
+
+	subject = fmt.Sprintf("Your %s digest: %s", batch.Mode, heading)
+	return subject, text.String(), html.String(), nil
+}
+
+// groupByOpportunity groups entries by opportunity, keeping first-seen order.
+func groupByOpportunity(entries []Entry) []opportunityGroup {
+	var groups []opportunityGroup
+	index := make(map[string]int)
+	for _, e := range entries {
+		key := fmt.Sprintf("%d|%s", e.OpportunityID, e.OpportunityTitle)
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, opportunityGroup{Title: e.OpportunityTitle})
+		}
+		groups[i].Entries = append(groups[i].Entries, e)
+	}
+	return groups
+}
@@ -0,0 +1,201 @@
+// digest/sql.go
+package digest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"notification-service/sqlstore"
+)
+
+// A batch row holds the recipient and window; each buffered entry is its own row so
+// adding one is a single insert, and keeps the window end it was buffered for so a
+// batch that outlives a send can be rescheduled. Timestamps are Unix milliseconds.
+var schema = map[string][]string{
+	sqlstore.DriverSQLite: {
+		`CREATE TABLE IF NOT EXISTS digest_batches (
+			user_id TEXT NOT NULL,
+			notification_type TEXT NOT NULL,
+			email_address TEXT NOT NULL,
+			mode TEXT NOT NULL,
+			flush_at INTEGER NOT NULL,
+			attempts INTEGER NOT NULL DEFAULT 0,
+			PRIMARY KEY (user_id, notification_type)
+		)`,
+		`CREATE TABLE IF NOT EXISTS digest_entries (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id TEXT NOT NULL,
+			notification_type TEXT NOT NULL,
+			flush_at INTEGER NOT NULL,
+			entry TEXT NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_digest_batches_flush_at ON digest_batches (flush_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_digest_entries_batch ON digest_entries (user_id, notification_type, id)`,
+	},
+	sqlstore.DriverPostgres: {
+		`CREATE TABLE IF NOT EXISTS digest_batches (
+			user_id TEXT NOT NULL,
+			notification_type TEXT NOT NULL,
+			email_address TEXT NOT NULL,
+			mode TEXT NOT NULL,
+			flush_at BIGINT NOT NULL,
+			attempts INTEGER NOT NULL DEFAULT 0,
+			PRIMARY KEY (user_id, notification_type)
+		)`,
+		`CREATE TABLE IF NOT EXISTS digest_entries (
+			id BIGSERIAL PRIMARY KEY,
+			user_id TEXT NOT NULL,
+			notification_type TEXT NOT NULL,
+			flush_at BIGINT NOT NULL,
+			entry TEXT NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_digest_batches_flush_at ON digest_batches (flush_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_digest_entries_batch ON digest_entries (user_id, notification_type, id)`,
+	},
+}
+
+// SQLStore is a Store backed by SQLite or Postgres, so buffered entries, whose
+// messages were already acknowledged on the queue, survive restarts.
+type SQLStore struct {
+	db *sqlstore.DB
+}
+
+// NewSQLStore creates the digest tables in db if needed
+func NewSQLStore(db *sqlstore.DB) (*SQLStore, error) {
+	if err := db.Migrate(schema[db.Driver]); err != nil {
+		return nil, fmt.Errorf("creating digest schema: %w", err)
+	}
+	return &SQLStore{db: db}, nil
+}
+
+// Add appends an entry to the open batch
+func (s *SQLStore) Add(userID, emailAddress, notificationType, mode string, flushAt time.Time, entry Entry) error {
+	document, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// The latest message has the freshest address; mode and window are kept from the first.
+	_, err = tx.ExecContext(ctx, s.db.Rebind(`INSERT INTO digest_batches (user_id, notification_type, email_address, mode, flush_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (user_id, notification_type) DO UPDATE SET email_address = excluded.email_address`),
+		userID, notificationType, emailAddress, mode, flushAt.UnixMilli())
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, s.db.Rebind(
+		`INSERT INTO digest_entries (user_id, notification_type, flush_at, entry) VALUES (?, ?, ?, ?)`),
+		userID, notificationType, flushAt.UnixMilli(), string(document))
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Due returns every batch whose window has closed, earliest first
+func (s *SQLStore) Due(now time.Time) ([]Batch, error) {
+	ctx := context.Background()
+	rows, err := s.db.QueryContext(ctx, s.db.Rebind(`SELECT user_id, notification_type, email_address, mode, flush_at, attempts
+		FROM digest_batches WHERE flush_at <= ? ORDER BY flush_at`), now.UnixMilli())
+	if err != nil {
+		return nil, err
+	}
+	var due []Batch
+	for rows.Next() {
+		var b Batch
+		var flushAt int64
+		if err := rows.Scan(&b.UserID, &b.NotificationType, &b.EmailAddress, &b.Mode, &flushAt, &b.Attempts); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		b.FlushAt = time.UnixMilli(flushAt).UTC()
+		due = append(due, b)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range due {
+		if err := s.loadEntries(ctx, &due[i]); err != nil {
+			return nil, err
+		}
+	}
+	return due, nil
+}
+
+// loadEntries reads a batch's entries, oldest first.
+func (s *SQLStore) loadEntries(ctx context.Context, b *Batch) error {
+	rows, err := s.db.QueryContext(ctx, s.db.Rebind(`SELECT id, entry FROM digest_entries
+		WHERE user_id = ? AND notification_type = ? ORDER BY id`), b.UserID, b.NotificationType)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		var document string
+		if err := rows.Scan(&id, &document); err != nil {
+			return err
+		}
+		var entry Entry
+		if err := json.Unmarshal([]byte(document), &entry); err != nil {
+			return fmt.Errorf("decoding digest entry %d: %w", id, err)
+		}
+		b.Entries = append(b.Entries, entry)
+		b.lastEntryID = id
+	}
+	return rows.Err()
+}
+
+// Complete removes a sent batch's entries, and the batch if no entries were added since.
+// Otherwise the batch flushes when the window of its oldest remaining entry closes,
+// with a fresh attempt count.
+func (s *SQLStore) Complete(batch Batch) error {
+	ctx := context.Background()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, s.db.Rebind(`DELETE FROM digest_entries
+		WHERE user_id = ? AND notification_type = ? AND id <= ?`),
+		batch.UserID, batch.NotificationType, batch.lastEntryID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, s.db.Rebind(`DELETE FROM digest_batches
+		WHERE user_id = ? AND notification_type = ? AND NOT EXISTS
+			(SELECT 1 FROM digest_entries WHERE user_id = ? AND notification_type = ?)`),
+		batch.UserID, batch.NotificationType, batch.UserID, batch.NotificationType)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, s.db.Rebind(`UPDATE digest_batches SET attempts = 0, flush_at =
+			(SELECT MIN(flush_at) FROM digest_entries WHERE user_id = ? AND notification_type = ?)
+		WHERE user_id = ? AND notification_type = ?`),
+		batch.UserID, batch.NotificationType, batch.UserID, batch.NotificationType)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Reschedule stores a failed batch's attempt count and retry time
+func (s *SQLStore) Reschedule(batch Batch) error {
+	_, err := s.db.ExecContext(context.Background(), s.db.Rebind(`UPDATE digest_batches SET flush_at = ?, attempts = ?
+		WHERE user_id = ? AND notification_type = ?`),
+		batch.FlushAt.UnixMilli(), batch.Attempts, batch.UserID, batch.NotificationType)
+	return err
+}
@@ -0,0 +1,20 @@
+// digest/store.go
+package digest
+
+import "time"
+
+// Store buffers digest entries until their window closes. A batch stays in the store
+// while it is being sent and its entries are only removed afterwards, so a crash
+// cannot lose them.
+type Store interface {
+	// Add appends an entry to the open batch for the recipient and notification type,
+	// creating the batch with the given mode and flush time if there is none.
+	Add(userID, emailAddress, notificationType, mode string, flushAt time.Time, entry Entry) error
+	// Due returns every batch whose window has closed, without removing it.
+	Due(now time.Time) ([]Batch, error)
+	// Complete removes the entries of a batch that was sent. Entries added since Due
+	// returned it stay buffered until their own window closes.
+	Complete(batch Batch) error
+	// Reschedule stores a failed batch's attempt count and retry time.
+	Reschedule(batch Batch) error
+}
@@ -0,0 +1,38 @@
+// dlq/audit.go
+package dlq
+
+import "time"
+
+// Audited actions.
+const (
+	ActionList       = "list"
+	ActionReplay     = "replay"
+	ActionEditReplay = "edit_replay"
+	ActionPurge      = "purge"
+)
+
+// Actor identifies who performed an action. Name is self-reported by the caller;
+// Key labels the admin API key the request was authenticated with.
+type Actor struct {
+	Name       string `json:"name"`
+	Key        string `json:"key,omitempty"`
+	RemoteAddr string `json:"remote_addr,omitempty"`
+}
+
+// AuditEntry records one operation on the dead letter queue.
+type AuditEntry struct {
+	Action string    `json:"action"`
+	Actor  Actor     `json:"actor"`
+	Filter *Filter   `json:"filter,omitempty"`
+	IDs    []string  `json:"ids,omitempty"` // Messages replayed or purged
+	Count  int       `json:"count"`
+	Error  string    `json:"error,omitempty"`
+	At     time.Time `json:"at"`
+}
+
+// AuditLog records operations on the dead letter queue.
+type AuditLog interface {
+	Record(e AuditEntry) error
+	// List returns the most recent entries, newest first.
+	List(limit int) ([]AuditEntry, error)
+}
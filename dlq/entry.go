@@ -0,0 +1,153 @@
+// dlq/entry.go
+package dlq
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"notification-service/cloudevents"
+	"notification-service/rabbitmq"
+	"notification-service/schema"
+)
+
+// Entry is a dead-lettered message with the failure details the consumer recorded.
+type Entry struct {
+	ID          string                 `json:"id"`
+	Exchange    string                 `json:"exchange"`
+	RoutingKey  string                 `json:"routing_key"` // Original routing key
+	Queue       string                 `json:"queue"`       // Queue the message failed on
+	Reason      string                 `json:"reason"`
+	FailedAt    time.Time              `json:"failed_at"`
+	Attempts    int                    `json:"attempts"`
+	ContentType string                 `json:"content_type,omitempty"`
+	Headers     map[string]interface{} `json:"headers,omitempty"` // Headers other than the dead-letter ones
+	Body        string                 `json:"body"`
+	// ValidationErrors is the schema validation report, for messages dead-lettered in strict mode.
+	ValidationErrors []schema.FieldError `json:"validation_errors,omitempty"`
+	// Notification summarises the body; nil if it is not a NotificationMessage.
+	Notification *Summary `json:"notification,omitempty"`
+}
+
+// Summary is the part of a NotificationMessage operators need to recognise it.
+type Summary struct {
+	SchemaVersion    int    `json:"schema_version"`
+	NotificationType string `json:"notification_type"`
+	EventID          string `json:"event_id,omitempty"`
+	UserID           string `json:"user_id,omitempty"`
+	ApplicationID    int    `json:"application_id,omitempty"`
+	OpportunityID    int    `json:"opportunity_id,omitempty"`
+	Title            string `json:"title,omitempty"`
+}
+
+// deadLetterHeaders are set by the consumer when dead-lettering and removed on replay.
+var deadLetterHeaders = []string{
+	rabbitmq.HeaderOriginalExchange,
+	rabbitmq.HeaderOriginalRoutingKey,
+	rabbitmq.HeaderOriginalQueue,
+	rabbitmq.HeaderFailureReason,
+	rabbitmq.HeaderFailedAt,
+	rabbitmq.HeaderDeliveryAttempts,
+	schema.HeaderSchema,
+	schema.HeaderErrors,
+}
+
+// newEntry decodes a delivery read from the dead letter queue.
+func newEntry(d rabbitmq.Delivery) Entry {
+	e := Entry{
+		ID:          d.MessageID,
+		Exchange:    headerString(d.Headers, rabbitmq.HeaderOriginalExchange),
+		RoutingKey:  headerString(d.Headers, rabbitmq.HeaderOriginalRoutingKey),
+		Queue:       headerString(d.Headers, rabbitmq.HeaderOriginalQueue),
+		Reason:      headerString(d.Headers, rabbitmq.HeaderFailureReason),
+		ContentType: d.ContentType,
+		Headers:     replayHeaders(d.Headers),
+		Body:        string(d.Body),
+	}
+	e.FailedAt, _ = time.Parse(time.RFC3339, headerString(d.Headers, rabbitmq.HeaderFailedAt))
+	e.Attempts, _ = strconv.Atoi(headerString(d.Headers, rabbitmq.HeaderDeliveryAttempts))
+	if report := headerString(d.Headers, schema.HeaderErrors); report != "" {
+		_ = json.Unmarshal([]byte(report), &e.ValidationErrors)
+	}
+
+	if msg, version, err := cloudevents.DecodeNotification(d.Body, d.ContentType, d.Headers); err == nil && msg.NotificationType != "" {
+		e.Notification = &Summary{
+			SchemaVersion:    version,
+			NotificationType: msg.NotificationType,
+			EventID:          msg.EventID,
+			UserID:           msg.Recipient.UserID,
+			ApplicationID:    msg.Payload.ApplicationID,
+			OpportunityID:    msg.Payload.OpportunityID,
+			Title:            msg.Payload.Title,
+		}
+	}
+	return e
+}
+
+// replayHeaders returns a copy of headers without the dead-letter ones.
+func replayHeaders(headers map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(headers))
+	for key, value := range headers {
+		result[key] = value
+	}
+	for _, key := range deadLetterHeaders {
+		delete(result, key)
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+// headerString returns a string header, or "" if it is missing or not a string.
+func headerString(headers map[string]interface{}, key string) string {
+	s, _ := headers[key].(string)
+	return s
+}
+
+// Filter selects dead-lettered messages. Set fields must all match.
+type Filter struct {
+	IDs              []string `json:"ids,omitempty"`
+	NotificationType string   `json:"notification_type,omitempty"`
+	RoutingKey       string   `json:"routing_key,omitempty"`
+	Queue            string   `json:"queue,omitempty"`
+	Reason           string   `json:"reason,omitempty"` // Substring of the failure reason
+	// All must be set for a replay or purge with no other criteria, so an empty
+	// request cannot empty the whole queue by accident.
+	All bool `json:"all,omitempty"`
+}
+
+// Empty reports whether the filter has no criteria, i.e. matches every message.
+func (f Filter) Empty() bool {
+	return len(f.IDs) == 0 && f.NotificationType == "" && f.RoutingKey == "" && f.Queue == "" && f.Reason == ""
+}
+
+// Matches reports whether the entry is selected by the filter.
+func (f Filter) Matches(e Entry) bool {
+	if len(f.IDs) > 0 && !contains(f.IDs, e.ID) {
+		return false
+	}
+	if f.NotificationType != "" && (e.Notification == nil || e.Notification.NotificationType != f.NotificationType) {
+		return false
+	}
+	if f.RoutingKey != "" && e.RoutingKey != f.RoutingKey {
+		return false
+	}
+	if f.Queue != "" && e.Queue != f.Queue {
+		return false
+	}
+	if f.Reason != "" && !strings.Contains(e.Reason, f.Reason) {
+		return false
+	}
+	return true
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
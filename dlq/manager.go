@@ -0,0 +1,208 @@
+// dlq/manager.go
+package dlq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"notification-service/cloudevents"
+	"notification-service/constants"
+	"notification-service/envelope"
+	"notification-service/rabbitmq"
+)
+
+var (
+	// ErrNotFound is returned when no dead-lettered message has the requested ID.
+	ErrNotFound = errors.New("message not found in dead letter queue")
+	// ErrEmptyFilter is returned for a replay or purge of every message without Filter.All.
+	ErrEmptyFilter = errors.New("filter matches every message; set all to confirm")
+	// ErrInvalidMessage is returned when an edited message is not a valid NotificationMessage.
+	ErrInvalidMessage = errors.New("edited message is not a valid notification message")
+)
+
+// Browser reads messages from a queue without consuming them; *rabbitmq.Connection implements it.
+type Browser interface {
+	Browse(queue string, limit int, visit rabbitmq.BrowseFunc) error
+}
+
+// Publisher publishes replayed messages to the notification exchange.
+type Publisher interface {
+	Publish(ctx context.Context, m rabbitmq.Message) error
+}
+
+// Result is the outcome of a replay or purge.
+type Result struct {
+	Count int      `json:"count"`
+	IDs   []string `json:"ids"`
+}
+
+// Manager lists, replays and purges the dead letter queue, recording every operation
+// in the audit log. Operations run one at a time: messages one operation is looking at
+// are invisible to another until it ends.
+type Manager struct {
+	browser   Browser
+	publisher Publisher
+	queue     string
+	audit     AuditLog
+
+	mu sync.Mutex
+}
+
+// NewManager creates a manager for the dead letter queue; publisher must publish to the
+// exchange the messages originally came from.
+func NewManager(browser Browser, publisher Publisher, queue string, audit AuditLog) *Manager {
+	return &Manager{
+		browser:   browser,
+		publisher: publisher,
+		queue:     queue,
+		audit:     audit,
+	}
+}
+
+// List returns up to limit dead-lettered messages matching the filter, oldest first.
+func (m *Manager) List(filter Filter, limit int, actor Actor) ([]Entry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entries := []Entry{}
+	err := m.browser.Browse(m.queue, 0, func(d rabbitmq.Delivery) (bool, bool, error) {
+		if e := newEntry(d); filter.Matches(e) {
+			entries = append(entries, e)
+		}
+		return false, limit > 0 && len(entries) >= limit, nil
+	})
+	m.record(AuditEntry{Action: ActionList, Actor: actor, Filter: &filter, Count: len(entries)}, err)
+	return entries, err
+}
+
+// Replay publishes the matching messages again with their original routing keys and
+// removes them from the dead letter queue. It stops at the first message that cannot
+// be published, leaving it and the rest in the queue.
+func (m *Manager) Replay(ctx context.Context, filter Filter, actor Actor) (Result, error) {
+	if filter.Empty() && !filter.All {
+		return Result{}, ErrEmptyFilter
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result := Result{IDs: []string{}}
+	err := m.browser.Browse(m.queue, 0, func(d rabbitmq.Delivery) (bool, bool, error) {
+		e := newEntry(d)
+		if !filter.Matches(e) {
+			return false, false, nil
+		}
+		if err := m.replay(ctx, d, e.RoutingKey); err != nil {
+			return false, true, fmt.Errorf("replaying %s: %w", e.ID, err)
+		}
+		result.Count++
+		result.IDs = append(result.IDs, e.ID)
+		return true, false, nil
+	})
+	m.record(AuditEntry{Action: ActionReplay, Actor: actor, Filter: &filter, IDs: result.IDs, Count: result.Count}, err)
+	return result, err
+}
+
+// ReplayEdited replaces the body of the dead-lettered message with the given ID and
+// replays it. The body must be a notification message of a supported schema version;
+// the routing key follows its notification type, so a corrected type reaches the right queue.
+// A CloudEvent is replayed as the plain notification message that replaces it.
+func (m *Manager) ReplayEdited(ctx context.Context, id string, body []byte, actor Actor) error {
+	msg, _, err := envelope.Decode(body)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidMessage, err)
+	}
+	routingKey, ok := constants.NotificationRoutingKeys[msg.NotificationType]
+	if !ok {
+		return fmt.Errorf("%w: unknown notification type %q", ErrInvalidMessage, msg.NotificationType)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	found := false
+	err = m.browser.Browse(m.queue, 0, func(d rabbitmq.Delivery) (bool, bool, error) {
+		if d.MessageID != id {
+			return false, false, nil
+		}
+		found = true
+		d.Body = body
+		d.ContentType = "application/json"
+		d.Headers = cloudevents.StripHeaders(d.Headers)
+		if err := m.replay(ctx, d, routingKey); err != nil {
+			return false, true, err
+		}
+		return true, true, nil
+	})
+	if err == nil && !found {
+		err = ErrNotFound
+	}
+	entry := AuditEntry{Action: ActionEditReplay, Actor: actor, IDs: []string{id}}
+	if err == nil {
+		entry.Count = 1
+	}
+	m.record(entry, err)
+	return err
+}
+
+// Purge deletes the matching messages from the dead letter queue.
+func (m *Manager) Purge(filter Filter, actor Actor) (Result, error) {
+	if filter.Empty() && !filter.All {
+		return Result{}, ErrEmptyFilter
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result := Result{IDs: []string{}}
+	err := m.browser.Browse(m.queue, 0, func(d rabbitmq.Delivery) (bool, bool, error) {
+		e := newEntry(d)
+		if !filter.Matches(e) {
+			return false, false, nil
+		}
+		result.Count++
+		result.IDs = append(result.IDs, e.ID)
+		return true, false, nil
+	})
+	m.record(AuditEntry{Action: ActionPurge, Actor: actor, Filter: &filter, IDs: result.IDs, Count: result.Count}, err)
+	return result, err
+}
+
+// Audit returns the most recent operations, newest first.
+func (m *Manager) Audit(limit int) ([]AuditEntry, error) {
+	return m.audit.List(limit)
+}
+
+// replay publishes a dead-lettered delivery with the given routing key, keeping its
+// message ID so deduplication still recognises channels that were already sent.
+func (m *Manager) replay(ctx context.Context, d rabbitmq.Delivery, routingKey string) error {
+	if routingKey == "" {
+		return errors.New("message has no original routing key")
+	}
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	return m.publisher.Publish(ctx, rabbitmq.Message{
+		RoutingKey:  routingKey,
+		Body:        d.Body,
+		ContentType: d.ContentType,
+		MessageID:   d.MessageID,
+		Headers:     replayHeaders(d.Headers),
+		Mandatory:   true,
+	})
+}
+
+// record writes an audit entry and logs it.
+func (m *Manager) record(e AuditEntry, err error) {
+	e.At = time.Now()
+	if err != nil {
+		e.Error = err.Error()
+	}
+	if auditErr := m.audit.Record(e); auditErr != nil {
+		slog.Error("Failed to record dead letter queue audit entry", "action", e.Action, "error", auditErr)
+	}
+	slog.Info("Dead letter queue operation",
+		"action", e.Action, "actor", e.Actor.Name, "remote_addr", e.Actor.RemoteAddr,
+		"count", e.Count, "ids", e.IDs, "error", e.Error)
+}
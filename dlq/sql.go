@@ -0,0 +1,85 @@
+// dlq/sql.go
+package dlq
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"notification-service/sqlstore"
+)
+
+// Audit entries are stored as JSON documents; at is Unix milliseconds.
+var auditSchema = map[string][]string{
+	sqlstore.DriverSQLite: {
+		`CREATE TABLE IF NOT EXISTS dlq_audit (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			action TEXT NOT NULL,
+			at INTEGER NOT NULL,
+			entry TEXT NOT NULL
+		)`,
+	},
+	sqlstore.DriverPostgres: {
+		`CREATE TABLE IF NOT EXISTS dlq_audit (
+			id BIGSERIAL PRIMARY KEY,
+			action TEXT NOT NULL,
+			at BIGINT NOT NULL,
+			entry TEXT NOT NULL
+		)`,
+	},
+}
+
+// SQLAuditLog is an AuditLog backed by SQLite or Postgres, so the record of who
+// replayed or purged dead letters survives restarts.
+type SQLAuditLog struct {
+	db *sqlstore.DB
+}
+
+// NewSQLAuditLog creates the audit table in db if needed
+func NewSQLAuditLog(db *sqlstore.DB) (*SQLAuditLog, error) {
+	if err := db.Migrate(auditSchema[db.Driver]); err != nil {
+		return nil, fmt.Errorf("creating dead letter audit schema: %w", err)
+	}
+	return &SQLAuditLog{db: db}, nil
+}
+
+// Record adds an entry
+func (l *SQLAuditLog) Record(e AuditEntry) error {
+	entry, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = l.db.ExecContext(context.Background(), l.db.Rebind(
+		`INSERT INTO dlq_audit (action, at, entry) VALUES (?, ?, ?)`),
+		e.Action, e.At.UnixMilli(), string(entry))
+	return err
+}
+
+// List returns the most recent entries, newest first
+func (l *SQLAuditLog) List(limit int) ([]AuditEntry, error) {
+	query := `SELECT entry FROM dlq_audit ORDER BY id DESC`
+	var args []any
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit)
+	}
+	rows, err := l.db.QueryContext(context.Background(), l.db.Rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []AuditEntry
+	for rows.Next() {
+		var entry string
+		if err := rows.Scan(&entry); err != nil {
+			return nil, err
+		}
+		var e AuditEntry
+		if err := json.Unmarshal([]byte(entry), &e); err != nil {
+			return nil, fmt.Errorf("decoding dead letter audit entry: %w", err)
+		}
+		result = append(result, e)
+	}
+	return result, rows.Err()
+}
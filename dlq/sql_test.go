@@ -0,0 +1,49 @@
+// dlq/sql_test.go
+package dlq
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"notification-service/sqlstore"
+)
+
+func TestSQLAuditLog(t *testing.T) {
+	db, err := sqlstore.Open(sqlstore.DriverSQLite, filepath.Join(t.TempDir(), "audit.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	log, err := NewSQLAuditLog(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	at := time.Now().UTC().Truncate(time.Millisecond)
+	entries := []AuditEntry{
+		{Action: ActionList, Actor: Actor{Name: "ann", Key: "sha256:aaaaaaaaaaaa"}, At: at},
+		{Action: ActionReplay, Actor: Actor{Name: "bob", Key: "sha256:bbbbbbbbbbbb"}, IDs: []string{"m1"}, Count: 1, At: at.Add(time.Second)},
+		{Action: ActionPurge, Actor: Actor{Name: "ann", Key: "sha256:aaaaaaaaaaaa"}, Filter: &Filter{All: true}, Count: 3, At: at.Add(2 * time.Second)},
+	}
+	for _, e := range entries {
+		if err := log.Record(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := log.List(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Action != ActionPurge || got[1].Action != ActionReplay {
+		t.Fatalf("List(2) = %+v, want purge then replay", got)
+	}
+	if got[1].Actor.Key != "sha256:bbbbbbbbbbbb" || got[1].IDs[0] != "m1" || !got[1].At.Equal(entries[1].At) {
+		t.Errorf("List(2)[1] = %+v, want %+v", got[1], entries[1])
+	}
+
+	if all, err := log.List(0); err != nil || len(all) != 3 {
+		t.Fatalf("List(0) = %d entries, %v; want 3", len(all), err)
+	}
+}
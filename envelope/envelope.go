@@ -0,0 +1,161 @@
+// envelope/envelope.go
+package envelope
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"notification-service/models"
+)
+
+// LatestVersion is the schema version Encode writes.
+const LatestVersion = 2
+
+var (
+	// ErrUnsupportedVersion is returned for a schema_version this service cannot decode.
+	ErrUnsupportedVersion = errors.New("unsupported schema version")
+	// ErrMalformed is returned for a body that is not a valid message of its version.
+	ErrMalformed = errors.New("malformed message")
+)
+
+// Envelope is the version 2 wire format: event metadata around the recipient and payload.
+//
+//	{"schema_version": 2, "event_id": "...", "notification_type": "...",
+//	 "occurred_at": "2025-01-02T15:04:05Z", "sender_service": "...",
+//	 "data": {"recipient": {...}, "payload": {...}}}
+type Envelope struct {
+	SchemaVersion    int        `json:"schema_version"`
+	EventID          string     `json:"event_id,omitempty"`
+	NotificationType string     `json:"notification_type"`
+	OccurredAt       *time.Time `json:"occurred_at,omitempty"`
+	SenderService    string     `json:"sender_service,omitempty"`
+	Data             *Data      `json:"data"`
+}
+
+// Data is the part of an Envelope that depends on the notification type.
+type Data struct {
+	Recipient models.Recipient `json:"recipient"`
+	Payload   models.Payload   `json:"payload"`
+}
+
+// decoders decode each supported version and upgrade the result, one version at a time,
+// to the latest Envelope. Adding a version means adding its decoder, turning the previous
+// latest decoder into an upgrade to the new version, and bumping LatestVersion.
+var decoders = map[int]func(body []byte) (Envelope, error){
+	1: decodeV1,
+	2: decodeV2,
+}
+
+// Decode decodes a message of any supported version. A body without schema_version is
+// version 1, the flat NotificationMessage NestJS published before versioning.
+// It returns the message and the version it was written in.
+func Decode(body []byte) (models.NotificationMessage, int, error) {
+	var probe struct {
+		SchemaVersion *json.RawMessage `json:"schema_version"`
+	}
+	if err := json.Unmarshal(body, &probe); err != nil {
+		return models.NotificationMessage{}, 0, fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+
+	version := 1
+	if probe.SchemaVersion != nil {
+		if err := json.Unmarshal(*probe.SchemaVersion, &version); err != nil {
+			return models.NotificationMessage{}, 0, fmt.Errorf("%w: schema_version must be an integer, got %s", ErrMalformed, *probe.SchemaVersion)
+		}
+	}
+	decode, ok := decoders[version]
+	if !ok {
+		return models.NotificationMessage{}, version, fmt.Errorf("%w %d (supported: %v)", ErrUnsupportedVersion, version, SupportedVersions())
+	}
+
+	env, err := decode(body)
+	if err != nil {
+		return models.NotificationMessage{}, version, fmt.Errorf("%w: version %d: %v", ErrMalformed, version, err)
+	}
+	return env.Message(), version, nil
+}
+
+// Encode writes a message as the latest version.
+func Encode(msg models.NotificationMessage) ([]byte, error) {
+	return json.Marshal(FromMessage(msg))
+}
+
+// SupportedVersions returns the versions Decode accepts, in order.
+func SupportedVersions() []int {
+	versions := make([]int, 0, len(decoders))
+	for version := range decoders {
+		versions = append(versions, version)
+	}
+	sort.Ints(versions)
+	return versions
+}
+
+// FromMessage wraps a message in the latest Envelope.
+func FromMessage(msg models.NotificationMessage) Envelope {
+	env := Envelope{
+		SchemaVersion:    LatestVersion,
+		EventID:          msg.EventID,
+		NotificationType: msg.NotificationType,
+		SenderService:    msg.SenderService,
+		Data:             &Data{Recipient: msg.Recipient, Payload: msg.Payload},
+	}
+	if msg.Timestamp != 0 {
+		at := time.Unix(msg.Timestamp, 0).UTC()
+		env.OccurredAt = &at
+	}
+	return env
+}
+
+// Message converts the envelope to the internal model.
+func (e Envelope) Message() models.NotificationMessage {
+	msg := models.NotificationMessage{
+		NotificationType: e.NotificationType,
+		EventID:          e.EventID,
+		SenderService:    e.SenderService,
+	}
+	if e.Data != nil {
+		msg.Recipient = e.Data.Recipient
+		msg.Payload = e.Data.Payload
+	}
+	if e.OccurredAt != nil {
+		msg.Timestamp = e.OccurredAt.Unix()
+	}
+	return msg
+}
+
+// decodeV1 decodes the legacy flat message and upgrades it to version 2. Unknown
+// fields are ignored, as they always were for unversioned messages.
+func decodeV1(body []byte) (Envelope, error) {
+	var msg models.NotificationMessage
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return Envelope{}, err
+	}
+	return upgradeV1(msg), nil
+}
+
+// upgradeV1 converts a version 1 message to version 2.
+func upgradeV1(msg models.NotificationMessage) Envelope {
+	return FromMessage(msg)
+}
+
+// decodeV2 decodes a version 2 envelope. Producers that version their messages must
+// bump the version when the format changes, so unknown fields are an error.
+func decodeV2(body []byte) (Envelope, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.DisallowUnknownFields()
+	var env Envelope
+	if err := dec.Decode(&env); err != nil {
+		return Envelope{}, err
+	}
+	if env.NotificationType == "" {
+		return Envelope{}, errors.New("notification_type is required")
+	}
+	if env.Data == nil {
+		return Envelope{}, errors.New("data is required")
+	}
+	return env, nil
+}
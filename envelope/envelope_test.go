@@ -0,0 +1,134 @@
+// envelope/envelope_test.go
+package envelope
+
+import (
+	"errors"
+	"testing"
+
+	"notification-service/models"
+)
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		wantVersion int
+		wantErr     error
+		want        models.NotificationMessage
+	}{
+		{
+			name: "v1 without schema_version is upgraded",
+			body: `{"notification_type":"APPLICATION_ACCEPTED","event_id":"e1","timestamp":1735830245,
+				"recipient":{"user_id":"u1","email_address":"u1@example.com"},"payload":{"application_id":12}}`,
+			wantVersion: 1,
+			want: models.NotificationMessage{
+				NotificationType: "APPLICATION_ACCEPTED",
+				EventID:          "e1",
+				Timestamp:        1735830245,
+				Recipient:        models.Recipient{UserID: "u1", EmailAddress: "u1@example.com"},
+				Payload:          models.Payload{ApplicationID: 12},
+			},
+		},
+		{
+			name:        "v1 with explicit schema_version ignores unknown fields",
+			body:        `{"schema_version":1,"notification_type":"NGO_NEW_APPLICATION","legacy_flag":true,"recipient":{"user_id":"u2"}}`,
+			wantVersion: 1,
+			want: models.NotificationMessage{
+				NotificationType: "NGO_NEW_APPLICATION",
+				Recipient:        models.Recipient{UserID: "u2"},
+			},
+		},
+		{
+			name: "v2 envelope",
+			body: `{"schema_version":2,"event_id":"e3","notification_type":"OPPORTUNITY_UPDATED",
+				"occurred_at":"2025-01-02T15:04:05Z","sender_service":"opportunities",
+				"data":{"recipient":{"user_id":"u3"},"payload":{"opportunity_id":7}}}`,
+			wantVersion: 2,
+			want: models.NotificationMessage{
+				NotificationType: "OPPORTUNITY_UPDATED",
+				EventID:          "e3",
+				SenderService:    "opportunities",
+				Timestamp:        1735830245,
+				Recipient:        models.Recipient{UserID: "u3"},
+				Payload:          models.Payload{OpportunityID: 7},
+			},
+		},
+		{
+			name:        "v2 unknown top-level field",
+			body:        `{"schema_version":2,"notification_type":"OPPORTUNITY_UPDATED","priority":"high","data":{"recipient":{"user_id":"u3"}}}`,
+			wantVersion: 2,
+			wantErr:     ErrMalformed,
+		},
+		{
+			name:        "v2 unknown field inside data",
+			body:        `{"schema_version":2,"notification_type":"OPPORTUNITY_UPDATED","data":{"recipient":{"user_id":"u3"},"extra":1}}`,
+			wantVersion: 2,
+			wantErr:     ErrMalformed,
+		},
+		{
+			name:        "v2 without data",
+			body:        `{"schema_version":2,"notification_type":"OPPORTUNITY_UPDATED"}`,
+			wantVersion: 2,
+			wantErr:     ErrMalformed,
+		},
+		{
+			name:        "unsupported future version",
+			body:        `{"schema_version":3,"notification_type":"OPPORTUNITY_UPDATED","data":{}}`,
+			wantVersion: 3,
+			wantErr:     ErrUnsupportedVersion,
+		},
+		{
+			name:        "unsupported zero version",
+			body:        `{"schema_version":0}`,
+			wantVersion: 0,
+			wantErr:     ErrUnsupportedVersion,
+		},
+		{
+			name:    "non-integer schema_version",
+			body:    `{"schema_version":"2"}`,
+			wantErr: ErrMalformed,
+		},
+		{
+			name:    "not JSON",
+			body:    `not json`,
+			wantErr: ErrMalformed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, version, err := Decode([]byte(tt.body))
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("Decode() error = %v, want %v", err, tt.wantErr)
+			}
+			if version != tt.wantVersion {
+				t.Errorf("Decode() version = %d, want %d", version, tt.wantVersion)
+			}
+			if tt.wantErr == nil && msg != tt.want {
+				t.Errorf("Decode() = %+v, want %+v", msg, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	msg := models.NotificationMessage{
+		NotificationType: "APPLICATION_REJECTED",
+		EventID:          "e4",
+		SenderService:    "applications",
+		Timestamp:        1735830245,
+		Recipient:        models.Recipient{UserID: "u4", DeviceToken: "tok"},
+		Payload:          models.Payload{Title: "Update", ApplicationID: 3},
+	}
+	body, err := Encode(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, version, err := Decode(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != LatestVersion || got != msg {
+		t.Errorf("Decode(Encode()) = %+v (version %d), want %+v (version %d)", got, version, msg, LatestVersion)
+	}
+}
@@ -0,0 +1,60 @@
+// grpcapi/auth.go
+package grpcapi
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// healthPrefix covers the grpc.health.v1 methods, which load balancers call unauthenticated.
+const healthPrefix = "/grpc.health.v1.Health/"
+
+// apiKeyAuth checks the API key sent as "authorization: Bearer <key>" or "x-api-key: <key>".
+type apiKeyAuth struct {
+	keys []string
+}
+
+func (a apiKeyAuth) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := a.check(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (a apiKeyAuth) stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := a.check(ss.Context(), info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
+
+func (a apiKeyAuth) check(ctx context.Context, method string) error {
+	if strings.HasPrefix(method, healthPrefix) {
+		return nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	var got string
+	if values := md.Get("x-api-key"); len(values) > 0 {
+		got = values[0]
+	}
+	if values := md.Get("authorization"); len(values) > 0 {
+		if bearer, ok := strings.CutPrefix(values[0], "Bearer "); ok {
+			got = bearer
+		}
+	}
+	if got != "" {
+		for _, key := range a.keys {
+			if subtle.ConstantTimeCompare([]byte(got), []byte(key)) == 1 {
+				return nil
+			}
+		}
+	}
+	return status.Error(codes.Unauthenticated, "missing or invalid API key")
+}
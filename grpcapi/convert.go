@@ -0,0 +1,103 @@
+// grpcapi/convert.go
+package grpcapi
+
+import (
+	"errors"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"notification-service/deliverylog"
+	pb "notification-service/grpcapi/notificationpb"
+	"notification-service/models"
+	"notification-service/schema"
+)
+
+// fromProto converts a gRPC notification into the model the handler processes.
+func fromProto(n *pb.NotificationMessage) models.NotificationMessage {
+	msg := models.NotificationMessage{
+		NotificationType: n.GetNotificationType(),
+		EventID:          n.GetEventId(),
+		SenderService:    n.GetSenderService(),
+		Timestamp:        n.GetTimestamp(),
+	}
+
+	r := n.GetRecipient()
+	msg.Recipient.UserID = r.GetUserId()
+	msg.Recipient.PlatformType = r.GetPlatformType()
+	msg.Recipient.DeviceToken = r.GetDeviceToken()
+	msg.Recipient.EmailAddress = r.GetEmailAddress()
+	msg.Recipient.Timezone = r.GetTimezone()
+	if qh := r.GetQuietHours(); qh != nil {
+		msg.Recipient.QuietHours = &models.QuietHours{Start: qh.GetStart(), End: qh.GetEnd()}
+	}
+	msg.Recipient.Prefs.ReceivePush = r.GetPrefs().GetReceivePush()
+	msg.Recipient.Prefs.ReceiveEmail = r.GetPrefs().GetReceiveEmail()
+
+	p := n.GetPayload()
+	msg.Payload = models.Payload{
+		Title:            p.GetTitle(),
+		Body:             p.GetBody(),
+		Subject:          p.GetSubject(),
+		BodyHTML:         p.GetBodyHtml(),
+		DeepLink:         p.GetDeepLink(),
+		TemplateName:     p.GetTemplateName(),
+		ApplicationID:    int(p.GetApplicationId()),
+		OpportunityID:    int(p.GetOpportunityId()),
+		NGOID:            int(p.GetNgoId()),
+		VolunteerID:      int(p.GetVolunteerId()),
+		OldStatus:        p.GetOldStatus(),
+		NewStatus:        p.GetNewStatus(),
+		OpportunityTitle: p.GetOpportunityTitle(),
+		VolunteerName:    p.GetVolunteerName(),
+		NgoName:          p.GetNgoName(),
+	}
+	return msg
+}
+
+// statusToProto converts a delivery log summary into a NotificationStatus.
+func statusToProto(s deliverylog.Summary) *pb.NotificationStatus {
+	status := &pb.NotificationStatus{
+		Id:     s.EventID,
+		Status: s.Status,
+	}
+	if status.Status == "" {
+		status.Status = statusPending
+	}
+	for _, a := range s.Attempts {
+		status.Attempts = append(status.Attempts, &pb.ChannelAttempt{
+			Channel:           a.Channel,
+			Provider:          a.Provider,
+			Status:            a.Status,
+			Reason:            a.Reason,
+			ProviderMessageId: a.ProviderMessageID,
+			Error:             a.Error,
+			AttemptNumber:     int32(a.AttemptNumber),
+			CreatedAt:         timestamppb.New(a.CreatedAt),
+		})
+	}
+	return status
+}
+
+// invalidArgument converts a validation error to an InvalidArgument status, with a
+// BadRequest field violation per invalid field of a schema mismatch.
+func invalidArgument(err error) error {
+	st := status.New(codes.InvalidArgument, err.Error())
+	var verr *schema.ValidationError
+	if !errors.As(err, &verr) {
+		return st.Err()
+	}
+	details := &errdetails.BadRequest{}
+	for _, f := range verr.Fields {
+		details.FieldViolations = append(details.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       f.Field,
+			Description: f.Message,
+		})
+	}
+	if withDetails, detailErr := st.WithDetails(details); detailErr == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
@@ -0,0 +1,1150 @@
+// proto/notification/v1/notification.proto
+//
+// gRPC interface to the notification service. Messages mirror models.NotificationMessage
+// so gRPC callers and the RabbitMQ consumer share one processing pipeline.
+//
+// Go code in grpcapi/notificationpb is generated with protoc-gen-go and protoc-gen-go-grpc:
+//
+//	protoc --go_out=. --go_opt=module=notification-service \
+//	  --go-grpc_out=. --go-grpc_opt=module=notification-service \
+//	  proto/notification/v1/notification.proto
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: proto/notification/v1/notification.proto
+
+package notificationpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type DeliveryMode int32
+
+const (
+	// Same as DELIVERY_MODE_ASYNC.
+	DeliveryMode_DELIVERY_MODE_UNSPECIFIED DeliveryMode = 0
+	// Enqueue onto the notification exchange with the type's routing key.
+	DeliveryMode_DELIVERY_MODE_ASYNC DeliveryMode = 1
+	// Process before responding.
+	DeliveryMode_DELIVERY_MODE_SYNC DeliveryMode = 2
+)
+
+// Enum value maps for DeliveryMode.
+var (
+	DeliveryMode_name = map[int32]string{
+		0: "DELIVERY_MODE_UNSPECIFIED",
+		1: "DELIVERY_MODE_ASYNC",
+		2: "DELIVERY_MODE_SYNC",
+	}
+	DeliveryMode_value = map[string]int32{
+		"DELIVERY_MODE_UNSPECIFIED": 0,
+		"DELIVERY_MODE_ASYNC":       1,
+		"DELIVERY_MODE_SYNC":        2,
+	}
+)
+
+func (x DeliveryMode) Enum() *DeliveryMode {
+	p := new(DeliveryMode)
+	*p = x
+	return p
+}
+
+func (x DeliveryMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DeliveryMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_notification_v1_notification_proto_enumTypes[0].Descriptor()
+}
+
+func (DeliveryMode) Type() protoreflect.EnumType {
+	return &file_proto_notification_v1_notification_proto_enumTypes[0]
+}
+
+func (x DeliveryMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DeliveryMode.Descriptor instead.
+func (DeliveryMode) EnumDescriptor() ([]byte, []int) {
+	return file_proto_notification_v1_notification_proto_rawDescGZIP(), []int{0}
+}
+
+type Prefs struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReceivePush   bool                   `protobuf:"varint,1,opt,name=receive_push,json=receivePush,proto3" json:"receive_push,omitempty"`
+	ReceiveEmail  bool                   `protobuf:"varint,2,opt,name=receive_email,json=receiveEmail,proto3" json:"receive_email,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Prefs) Reset() {
+	*x = Prefs{}
+	mi := &file_proto_notification_v1_notification_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Prefs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Prefs) ProtoMessage() {}
+
+func (x *Prefs) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notification_v1_notification_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Prefs.ProtoReflect.Descriptor instead.
+func (*Prefs) Descriptor() ([]byte, []int) {
+	return file_proto_notification_v1_notification_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Prefs) GetReceivePush() bool {
+	if x != nil {
+		return x.ReceivePush
+	}
+	return false
+}
+
+func (x *Prefs) GetReceiveEmail() bool {
+	if x != nil {
+		return x.ReceiveEmail
+	}
+	return false
+}
+
+type QuietHours struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Start         string                 `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"` // "HH:MM" in the recipient's timezone
+	End           string                 `protobuf:"bytes,2,opt,name=end,proto3" json:"end,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QuietHours) Reset() {
+	*x = QuietHours{}
+	mi := &file_proto_notification_v1_notification_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuietHours) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuietHours) ProtoMessage() {}
+
+func (x *QuietHours) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notification_v1_notification_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuietHours.ProtoReflect.Descriptor instead.
+func (*QuietHours) Descriptor() ([]byte, []int) {
+	return file_proto_notification_v1_notification_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *QuietHours) GetStart() string {
+	if x != nil {
+		return x.Start
+	}
+	return ""
+}
+
+func (x *QuietHours) GetEnd() string {
+	if x != nil {
+		return x.End
+	}
+	return ""
+}
+
+type Recipient struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	PlatformType  string                 `protobuf:"bytes,2,opt,name=platform_type,json=platformType,proto3" json:"platform_type,omitempty"`
+	DeviceToken   string                 `protobuf:"bytes,3,opt,name=device_token,json=deviceToken,proto3" json:"device_token,omitempty"`
+	EmailAddress  string                 `protobuf:"bytes,4,opt,name=email_address,json=emailAddress,proto3" json:"email_address,omitempty"`
+	Timezone      string                 `protobuf:"bytes,5,opt,name=timezone,proto3" json:"timezone,omitempty"` // IANA name, e.g. "Asia/Kolkata"
+	QuietHours    *QuietHours            `protobuf:"bytes,6,opt,name=quiet_hours,json=quietHours,proto3" json:"quiet_hours,omitempty"`
+	Prefs         *Prefs                 `protobuf:"bytes,7,opt,name=prefs,proto3" json:"prefs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Recipient) Reset() {
+	*x = Recipient{}
+	mi := &file_proto_notification_v1_notification_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Recipient) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Recipient) ProtoMessage() {}
+
+func (x *Recipient) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notification_v1_notification_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Recipient.ProtoReflect.Descriptor instead.
+func (*Recipient) Descriptor() ([]byte, []int) {
+	return file_proto_notification_v1_notification_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Recipient) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Recipient) GetPlatformType() string {
+	if x != nil {
+		return x.PlatformType
+	}
+	return ""
+}
+
+func (x *Recipient) GetDeviceToken() string {
+	if x != nil {
+		return x.DeviceToken
+	}
+	return ""
+}
+
+func (x *Recipient) GetEmailAddress() string {
+	if x != nil {
+		return x.EmailAddress
+	}
+	return ""
+}
+
+func (x *Recipient) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *Recipient) GetQuietHours() *QuietHours {
+	if x != nil {
+		return x.QuietHours
+	}
+	return nil
+}
+
+func (x *Recipient) GetPrefs() *Prefs {
+	if x != nil {
+		return x.Prefs
+	}
+	return nil
+}
+
+type Payload struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Title            string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Body             string                 `protobuf:"bytes,2,opt,name=body,proto3" json:"body,omitempty"`
+	Subject          string                 `protobuf:"bytes,3,opt,name=subject,proto3" json:"subject,omitempty"`
+	BodyHtml         string                 `protobuf:"bytes,4,opt,name=body_html,json=bodyHtml,proto3" json:"body_html,omitempty"`
+	DeepLink         string                 `protobuf:"bytes,5,opt,name=deep_link,json=deepLink,proto3" json:"deep_link,omitempty"`
+	TemplateName     string                 `protobuf:"bytes,6,opt,name=template_name,json=templateName,proto3" json:"template_name,omitempty"`
+	ApplicationId    int64                  `protobuf:"varint,7,opt,name=application_id,json=applicationId,proto3" json:"application_id,omitempty"`
+	OpportunityId    int64                  `protobuf:"varint,8,opt,name=opportunity_id,json=opportunityId,proto3" json:"opportunity_id,omitempty"`
+	NgoId            int64                  `protobuf:"varint,9,opt,name=ngo_id,json=ngoId,proto3" json:"ngo_id,omitempty"`
+	VolunteerId      int64                  `protobuf:"varint,10,opt,name=volunteer_id,json=volunteerId,proto3" json:"volunteer_id,omitempty"`
+	OldStatus        string                 `protobuf:"bytes,11,opt,name=old_status,json=oldStatus,proto3" json:"old_status,omitempty"`
+	NewStatus        string                 `protobuf:"bytes,12,opt,name=new_status,json=newStatus,proto3" json:"new_status,omitempty"`
+	OpportunityTitle string                 `protobuf:"bytes,13,opt,name=opportunity_title,json=opportunityTitle,proto3" json:"opportunity_title,omitempty"`
+	VolunteerName    string                 `protobuf:"bytes,14,opt,name=volunteer_name,json=volunteerName,proto3" json:"volunteer_name,omitempty"`
+	NgoName          string                 `protobuf:"bytes,15,opt,name=ngo_name,json=ngoName,proto3" json:"ngo_name,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Payload) Reset() {
+	*x = Payload{}
+	mi := &file_proto_notification_v1_notification_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Payload) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Payload) ProtoMessage() {}
+
+func (x *Payload) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notification_v1_notification_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Payload.ProtoReflect.Descriptor instead.
+func (*Payload) Descriptor() ([]byte, []int) {
+	return file_proto_notification_v1_notification_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Payload) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Payload) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *Payload) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *Payload) GetBodyHtml() string {
+	if x != nil {
+		return x.BodyHtml
+	}
+	return ""
+}
+
+func (x *Payload) GetDeepLink() string {
+	if x != nil {
+		return x.DeepLink
+	}
+	return ""
+}
+
+func (x *Payload) GetTemplateName() string {
+	if x != nil {
+		return x.TemplateName
+	}
+	return ""
+}
+
+func (x *Payload) GetApplicationId() int64 {
+	if x != nil {
+		return x.ApplicationId
+	}
+	return 0
+}
+
+func (x *Payload) GetOpportunityId() int64 {
+	if x != nil {
+		return x.OpportunityId
+	}
+	return 0
+}
+
+func (x *Payload) GetNgoId() int64 {
+	if x != nil {
+		return x.NgoId
+	}
+	return 0
+}
+
+func (x *Payload) GetVolunteerId() int64 {
+	if x != nil {
+		return x.VolunteerId
+	}
+	return 0
+}
+
+func (x *Payload) GetOldStatus() string {
+	if x != nil {
+		return x.OldStatus
+	}
+	return ""
+}
+
+func (x *Payload) GetNewStatus() string {
+	if x != nil {
+		return x.NewStatus
+	}
+	return ""
+}
+
+func (x *Payload) GetOpportunityTitle() string {
+	if x != nil {
+		return x.OpportunityTitle
+	}
+	return ""
+}
+
+func (x *Payload) GetVolunteerName() string {
+	if x != nil {
+		return x.VolunteerName
+	}
+	return ""
+}
+
+func (x *Payload) GetNgoName() string {
+	if x != nil {
+		return x.NgoName
+	}
+	return ""
+}
+
+type NotificationMessage struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	NotificationType string                 `protobuf:"bytes,1,opt,name=notification_type,json=notificationType,proto3" json:"notification_type,omitempty"` // e.g. "APPLICATION_ACCEPTED"
+	EventId          string                 `protobuf:"bytes,2,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`                            // Assigned by the service when empty
+	Recipient        *Recipient             `protobuf:"bytes,3,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	Payload          *Payload               `protobuf:"bytes,4,opt,name=payload,proto3" json:"payload,omitempty"`
+	SenderService    string                 `protobuf:"bytes,5,opt,name=sender_service,json=senderService,proto3" json:"sender_service,omitempty"`
+	Timestamp        int64                  `protobuf:"varint,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // Unix seconds
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *NotificationMessage) Reset() {
+	*x = NotificationMessage{}
+	mi := &file_proto_notification_v1_notification_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NotificationMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NotificationMessage) ProtoMessage() {}
+
+func (x *NotificationMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notification_v1_notification_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NotificationMessage.ProtoReflect.Descriptor instead.
+func (*NotificationMessage) Descriptor() ([]byte, []int) {
+	return file_proto_notification_v1_notification_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *NotificationMessage) GetNotificationType() string {
+	if x != nil {
+		return x.NotificationType
+	}
+	return ""
+}
+
+func (x *NotificationMessage) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *NotificationMessage) GetRecipient() *Recipient {
+	if x != nil {
+		return x.Recipient
+	}
+	return nil
+}
+
+func (x *NotificationMessage) GetPayload() *Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *NotificationMessage) GetSenderService() string {
+	if x != nil {
+		return x.SenderService
+	}
+	return ""
+}
+
+func (x *NotificationMessage) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+type SendRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Notification  *NotificationMessage   `protobuf:"bytes,1,opt,name=notification,proto3" json:"notification,omitempty"`
+	Mode          DeliveryMode           `protobuf:"varint,2,opt,name=mode,proto3,enum=notification.v1.DeliveryMode" json:"mode,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendRequest) Reset() {
+	*x = SendRequest{}
+	mi := &file_proto_notification_v1_notification_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendRequest) ProtoMessage() {}
+
+func (x *SendRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notification_v1_notification_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendRequest.ProtoReflect.Descriptor instead.
+func (*SendRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notification_v1_notification_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SendRequest) GetNotification() *NotificationMessage {
+	if x != nil {
+		return x.Notification
+	}
+	return nil
+}
+
+func (x *SendRequest) GetMode() DeliveryMode {
+	if x != nil {
+		return x.Mode
+	}
+	return DeliveryMode_DELIVERY_MODE_UNSPECIFIED
+}
+
+type SendResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"` // "queued", or the processing status in sync mode
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`   // Set when processing failed (sync) or a batch entry could not be submitted
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendResponse) Reset() {
+	*x = SendResponse{}
+	mi := &file_proto_notification_v1_notification_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendResponse) ProtoMessage() {}
+
+func (x *SendResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notification_v1_notification_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendResponse.ProtoReflect.Descriptor instead.
+func (*SendResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notification_v1_notification_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SendResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SendResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *SendResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type SendBatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Notifications []*NotificationMessage `protobuf:"bytes,1,rep,name=notifications,proto3" json:"notifications,omitempty"`
+	Mode          DeliveryMode           `protobuf:"varint,2,opt,name=mode,proto3,enum=notification.v1.DeliveryMode" json:"mode,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendBatchRequest) Reset() {
+	*x = SendBatchRequest{}
+	mi := &file_proto_notification_v1_notification_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendBatchRequest) ProtoMessage() {}
+
+func (x *SendBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notification_v1_notification_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendBatchRequest.ProtoReflect.Descriptor instead.
+func (*SendBatchRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notification_v1_notification_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SendBatchRequest) GetNotifications() []*NotificationMessage {
+	if x != nil {
+		return x.Notifications
+	}
+	return nil
+}
+
+func (x *SendBatchRequest) GetMode() DeliveryMode {
+	if x != nil {
+		return x.Mode
+	}
+	return DeliveryMode_DELIVERY_MODE_UNSPECIFIED
+}
+
+type SendBatchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*SendResponse        `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"` // In request order
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendBatchResponse) Reset() {
+	*x = SendBatchResponse{}
+	mi := &file_proto_notification_v1_notification_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendBatchResponse) ProtoMessage() {}
+
+func (x *SendBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notification_v1_notification_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendBatchResponse.ProtoReflect.Descriptor instead.
+func (*SendBatchResponse) Descriptor() ([]byte, []int) {
+	return file_proto_notification_v1_notification_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SendBatchResponse) GetResults() []*SendResponse {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type GetStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatusRequest) Reset() {
+	*x = GetStatusRequest{}
+	mi := &file_proto_notification_v1_notification_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatusRequest) ProtoMessage() {}
+
+func (x *GetStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notification_v1_notification_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetStatusRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notification_v1_notification_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetStatusRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type StreamStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamStatusRequest) Reset() {
+	*x = StreamStatusRequest{}
+	mi := &file_proto_notification_v1_notification_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamStatusRequest) ProtoMessage() {}
+
+func (x *StreamStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notification_v1_notification_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamStatusRequest.ProtoReflect.Descriptor instead.
+func (*StreamStatusRequest) Descriptor() ([]byte, []int) {
+	return file_proto_notification_v1_notification_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *StreamStatusRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ChannelAttempt struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Channel           string                 `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"` // "email" or "push"
+	Provider          string                 `protobuf:"bytes,2,opt,name=provider,proto3" json:"provider,omitempty"`
+	Status            string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"` // sent, failed, skipped, suppressed or digested
+	Reason            string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	ProviderMessageId string                 `protobuf:"bytes,5,opt,name=provider_message_id,json=providerMessageId,proto3" json:"provider_message_id,omitempty"`
+	Error             string                 `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	AttemptNumber     int32                  `protobuf:"varint,7,opt,name=attempt_number,json=attemptNumber,proto3" json:"attempt_number,omitempty"`
+	CreatedAt         *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ChannelAttempt) Reset() {
+	*x = ChannelAttempt{}
+	mi := &file_proto_notification_v1_notification_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChannelAttempt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChannelAttempt) ProtoMessage() {}
+
+func (x *ChannelAttempt) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notification_v1_notification_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChannelAttempt.ProtoReflect.Descriptor instead.
+func (*ChannelAttempt) Descriptor() ([]byte, []int) {
+	return file_proto_notification_v1_notification_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ChannelAttempt) GetChannel() string {
+	if x != nil {
+		return x.Channel
+	}
+	return ""
+}
+
+func (x *ChannelAttempt) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *ChannelAttempt) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ChannelAttempt) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ChannelAttempt) GetProviderMessageId() string {
+	if x != nil {
+		return x.ProviderMessageId
+	}
+	return ""
+}
+
+func (x *ChannelAttempt) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *ChannelAttempt) GetAttemptNumber() int32 {
+	if x != nil {
+		return x.AttemptNumber
+	}
+	return 0
+}
+
+func (x *ChannelAttempt) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type NotificationStatus struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// processed, failed, deferred, duplicate or invalid; "pending" until first processed.
+	Status        string            `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Attempts      []*ChannelAttempt `protobuf:"bytes,3,rep,name=attempts,proto3" json:"attempts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NotificationStatus) Reset() {
+	*x = NotificationStatus{}
+	mi := &file_proto_notification_v1_notification_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NotificationStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NotificationStatus) ProtoMessage() {}
+
+func (x *NotificationStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_notification_v1_notification_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NotificationStatus.ProtoReflect.Descriptor instead.
+func (*NotificationStatus) Descriptor() ([]byte, []int) {
+	return file_proto_notification_v1_notification_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *NotificationStatus) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *NotificationStatus) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *NotificationStatus) GetAttempts() []*ChannelAttempt {
+	if x != nil {
+		return x.Attempts
+	}
+	return nil
+}
+
+var File_proto_notification_v1_notification_proto protoreflect.FileDescriptor
+
+const file_proto_notification_v1_notification_proto_rawDesc = "" +
+	"\n" +
+	"(proto/notification/v1/notification.proto\x12\x0fnotification.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"O\n" +
+	"\x05Prefs\x12!\n" +
+	"\freceive_push\x18\x01 \x01(\bR\vreceivePush\x12#\n" +
+	"\rreceive_email\x18\x02 \x01(\bR\freceiveEmail\"4\n" +
+	"\n" +
+	"QuietHours\x12\x14\n" +
+	"\x05start\x18\x01 \x01(\tR\x05start\x12\x10\n" +
+	"\x03end\x18\x02 \x01(\tR\x03end\"\x99\x02\n" +
+	"\tRecipient\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12#\n" +
+	"\rplatform_type\x18\x02 \x01(\tR\fplatformType\x12!\n" +
+	"\fdevice_token\x18\x03 \x01(\tR\vdeviceToken\x12#\n" +
+	"\remail_address\x18\x04 \x01(\tR\femailAddress\x12\x1a\n" +
+	"\btimezone\x18\x05 \x01(\tR\btimezone\x12<\n" +
+	"\vquiet_hours\x18\x06 \x01(\v2\x1b.notification.v1.QuietHoursR\n" +
+	"quietHours\x12,\n" +
+	"\x05prefs\x18\a \x01(\v2\x16.notification.v1.PrefsR\x05prefs\"\xe1\x03\n" +
+	"\aPayload\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12\x12\n" +
+	"\x04body\x18\x02 \x01(\tR\x04body\x12\x18\n" +
+	"\asubject\x18\x03 \x01(\tR\asubject\x12\x1b\n" +
+	"\tbody_html\x18\x04 \x01(\tR\bbodyHtml\x12\x1b\n" +
+	"\tdeep_link\x18\x05 \x01(\tR\bdeepLink\x12#\n" +
+	"\rtemplate_name\x18\x06 \x01(\tR\ftemplateName\x12%\n" +
+	"\x0eapplication_id\x18\a \x01(\x03R\rapplicationId\x12%\n" +
+	"\x0eopportunity_id\x18\b \x01(\x03R\ropportunityId\x12\x15\n" +
+	"\x06ngo_id\x18\t \x01(\x03R\x05ngoId\x12!\n" +
+	"\fvolunteer_id\x18\n" +
+	" \x01(\x03R\vvolunteerId\x12\x1d\n" +
+	"\n" +
+	"old_status\x18\v \x01(\tR\toldStatus\x12\x1d\n" +
+	"\n" +
+	"new_status\x18\f \x01(\tR\tnewStatus\x12+\n" +
+	"\x11opportunity_title\x18\r \x01(\tR\x10opportunityTitle\x12%\n" +
+	"\x0evolunteer_name\x18\x0e \x01(\tR\rvolunteerName\x12\x19\n" +
+	"\bngo_name\x18\x0f \x01(\tR\angoName\"\x90\x02\n" +
+	"\x13NotificationMessage\x12+\n" +
+	"\x11notification_type\x18\x01 \x01(\tR\x10notificationType\x12\x19\n" +
+	"\bevent_id\x18\x02 \x01(\tR\aeventId\x128\n" +
+	"\trecipient\x18\x03 \x01(\v2\x1a.notification.v1.RecipientR\trecipient\x122\n" +
+	"\apayload\x18\x04 \x01(\v2\x18.notification.v1.PayloadR\apayload\x12%\n" +
+	"\x0esender_service\x18\x05 \x01(\tR\rsenderService\x12\x1c\n" +
+	"\ttimestamp\x18\x06 \x01(\x03R\ttimestamp\"\x8a\x01\n" +
+	"\vSendRequest\x12H\n" +
+	"\fnotification\x18\x01 \x01(\v2$.notification.v1.NotificationMessageR\fnotification\x121\n" +
+	"\x04mode\x18\x02 \x01(\x0e2\x1d.notification.v1.DeliveryModeR\x04mode\"L\n" +
+	"\fSendResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"\x91\x01\n" +
+	"\x10SendBatchRequest\x12J\n" +
+	"\rnotifications\x18\x01 \x03(\v2$.notification.v1.NotificationMessageR\rnotifications\x121\n" +
+	"\x04mode\x18\x02 \x01(\x0e2\x1d.notification.v1.DeliveryModeR\x04mode\"L\n" +
+	"\x11SendBatchResponse\x127\n" +
+	"\aresults\x18\x01 \x03(\v2\x1d.notification.v1.SendResponseR\aresults\"\"\n" +
+	"\x10GetStatusRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"%\n" +
+	"\x13StreamStatusRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x9e\x02\n" +
+	"\x0eChannelAttempt\x12\x18\n" +
+	"\achannel\x18\x01 \x01(\tR\achannel\x12\x1a\n" +
+	"\bprovider\x18\x02 \x01(\tR\bprovider\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\x12.\n" +
+	"\x13provider_message_id\x18\x05 \x01(\tR\x11providerMessageId\x12\x14\n" +
+	"\x05error\x18\x06 \x01(\tR\x05error\x12%\n" +
+	"\x0eattempt_number\x18\a \x01(\x05R\rattemptNumber\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"y\n" +
+	"\x12NotificationStatus\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12;\n" +
+	"\battempts\x18\x03 \x03(\v2\x1f.notification.v1.ChannelAttemptR\battempts*^\n" +
+	"\fDeliveryMode\x12\x1d\n" +
+	"\x19DELIVERY_MODE_UNSPECIFIED\x10\x00\x12\x17\n" +
+	"\x13DELIVERY_MODE_ASYNC\x10\x01\x12\x16\n" +
+	"\x12DELIVERY_MODE_SYNC\x10\x022\xe0\x02\n" +
+	"\x13NotificationService\x12C\n" +
+	"\x04Send\x12\x1c.notification.v1.SendRequest\x1a\x1d.notification.v1.SendResponse\x12R\n" +
+	"\tSendBatch\x12!.notification.v1.SendBatchRequest\x1a\".notification.v1.SendBatchResponse\x12S\n" +
+	"\tGetStatus\x12!.notification.v1.GetStatusRequest\x1a#.notification.v1.NotificationStatus\x12[\n" +
+	"\fStreamStatus\x12$.notification.v1.StreamStatusRequest\x1a#.notification.v1.NotificationStatus0\x01BZ\n" +
+	"\x1aorg.volhub.notification.v1P\x01Z:notification-service/grpcapi/notificationpb;notificationpbb\x06proto3"
+
+var (
+	file_proto_notification_v1_notification_proto_rawDescOnce sync.Once
+	file_proto_notification_v1_notification_proto_rawDescData []byte
+)
+
+func file_proto_notification_v1_notification_proto_rawDescGZIP() []byte {
+	file_proto_notification_v1_notification_proto_rawDescOnce.Do(func() {
+		file_proto_notification_v1_notification_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_notification_v1_notification_proto_rawDesc), len(file_proto_notification_v1_notification_proto_rawDesc)))
+	})
+	return file_proto_notification_v1_notification_proto_rawDescData
+}
+
+var file_proto_notification_v1_notification_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_proto_notification_v1_notification_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_proto_notification_v1_notification_proto_goTypes = []any{
+	(DeliveryMode)(0),             // 0: notification.v1.DeliveryMode
+	(*Prefs)(nil),                 // 1: notification.v1.Prefs
+	(*QuietHours)(nil),            // 2: notification.v1.QuietHours
+	(*Recipient)(nil),             // 3: notification.v1.Recipient
+	(*Payload)(nil),               // 4: notification.v1.Payload
+	(*NotificationMessage)(nil),   // 5: notification.v1.NotificationMessage
+	(*SendRequest)(nil),           // 6: notification.v1.SendRequest
+	(*SendResponse)(nil),          // 7: notification.v1.SendResponse
+	(*SendBatchRequest)(nil),      // 8: notification.v1.SendBatchRequest
+	(*SendBatchResponse)(nil),     // 9: notification.v1.SendBatchResponse
+	(*GetStatusRequest)(nil),      // 10: notification.v1.GetStatusRequest
+	(*StreamStatusRequest)(nil),   // 11: notification.v1.StreamStatusRequest
+	(*ChannelAttempt)(nil),        // 12: notification.v1.ChannelAttempt
+	(*NotificationStatus)(nil),    // 13: notification.v1.NotificationStatus
+	(*timestamppb.Timestamp)(nil), // 14: google.protobuf.Timestamp
+}
+var file_proto_notification_v1_notification_proto_depIdxs = []int32{
+	2,  // 0: notification.v1.Recipient.quiet_hours:type_name -> notification.v1.QuietHours
+	1,  // 1: notification.v1.Recipient.prefs:type_name -> notification.v1.Prefs
+	3,  // 2: notification.v1.NotificationMessage.recipient:type_name -> notification.v1.Recipient
+	4,  // 3: notification.v1.NotificationMessage.payload:type_name -> notification.v1.Payload
+	5,  // 4: notification.v1.SendRequest.notification:type_name -> notification.v1.NotificationMessage
+	0,  // 5: notification.v1.SendRequest.mode:type_name -> notification.v1.DeliveryMode
+	5,  // 6: notification.v1.SendBatchRequest.notifications:type_name -> notification.v1.NotificationMessage
+	0,  // 7: notification.v1.SendBatchRequest.mode:type_name -> notification.v1.DeliveryMode
+	7,  // 8: notification.v1.SendBatchResponse.results:type_name -> notification.v1.SendResponse
+	14, // 9: notification.v1.ChannelAttempt.created_at:type_name -> google.protobuf.Timestamp
+	12, // 10: notification.v1.NotificationStatus.attempts:type_name -> notification.v1.ChannelAttempt
+	6,  // 11: notification.v1.NotificationService.Send:input_type -> notification.v1.SendRequest
+	8,  // 12: notification.v1.NotificationService.SendBatch:input_type -> notification.v1.SendBatchRequest
+	10, // 13: notification.v1.NotificationService.GetStatus:input_type -> notification.v1.GetStatusRequest
+	11, // 14: notification.v1.NotificationService.StreamStatus:input_type -> notification.v1.StreamStatusRequest
+	7,  // 15: notification.v1.NotificationService.Send:output_type -> notification.v1.SendResponse
+	9,  // 16: notification.v1.NotificationService.SendBatch:output_type -> notification.v1.SendBatchResponse
+	13, // 17: notification.v1.NotificationService.GetStatus:output_type -> notification.v1.NotificationStatus
+	13, // 18: notification.v1.NotificationService.StreamStatus:output_type -> notification.v1.NotificationStatus
+	15, // [15:19] is the sub-list for method output_type
+	11, // [11:15] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_proto_notification_v1_notification_proto_init() }
+func file_proto_notification_v1_notification_proto_init() {
+	if File_proto_notification_v1_notification_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_notification_v1_notification_proto_rawDesc), len(file_proto_notification_v1_notification_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   13,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_notification_v1_notification_proto_goTypes,
+		DependencyIndexes: file_proto_notification_v1_notification_proto_depIdxs,
+		EnumInfos:         file_proto_notification_v1_notification_proto_enumTypes,
+		MessageInfos:      file_proto_notification_v1_notification_proto_msgTypes,
+	}.Build()
+	File_proto_notification_v1_notification_proto = out.File
+	file_proto_notification_v1_notification_proto_goTypes = nil
+	file_proto_notification_v1_notification_proto_depIdxs = nil
+}
@@ -0,0 +1,262 @@
+// grpcapi/server.go
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	"notification-service/deliverylog"
+	pb "notification-service/grpcapi/notificationpb"
+	"notification-service/handlers"
+	"notification-service/models"
+	"notification-service/rabbitmq"
+)
+
+// SourceGRPC is recorded as the queue of notifications processed synchronously over gRPC.
+const SourceGRPC = "grpc"
+
+const (
+	statusPending      = "pending" // Not processed yet, e.g. still queued
+	statusQueued       = "queued"
+	statusPollInterval = time.Second
+	// A stream for an ID the delivery log has never seen ends with NotFound after
+	// statusNotFoundGrace, and any stream ends with DeadlineExceeded after maxStatusStream.
+	statusNotFoundGrace = 2 * time.Minute
+	maxStatusStream     = 30 * time.Minute
+	maxBatchSize        = 500
+)
+
+// Processor processes a notification in-process, as the RabbitMQ consumer would.
+type Processor interface {
+	ProcessNotification(ctx context.Context, msg models.NotificationMessage, source string) (string, error)
+}
+
+// Publisher enqueues a notification onto the exchange with its type's routing key.
+type Publisher interface {
+	PublishNotification(ctx context.Context, msg models.NotificationMessage) (string, error)
+}
+
+// Server is the gRPC NotificationService plus grpc.health.v1.
+type Server struct {
+	pb.UnimplementedNotificationServiceServer
+
+	processor  Processor
+	publisher  Publisher
+	store      deliverylog.Store
+	addr       string
+	grpcServer *grpc.Server
+	health     *health.Server
+}
+
+// NewServer creates a gRPC server listening on addr. Calls other than health checks
+// must carry one of apiKeys. publisher may be nil, in which case only sync mode works.
+func NewServer(addr string, processor Processor, publisher Publisher, store deliverylog.Store, apiKeys []string) *Server {
+	auth := apiKeyAuth{keys: apiKeys}
+	s := &Server{
+		processor: processor,
+		publisher: publisher,
+		store:     store,
+		addr:      addr,
+		grpcServer: grpc.NewServer(
+			grpc.ChainUnaryInterceptor(auth.unary),
+			grpc.ChainStreamInterceptor(auth.stream),
+		),
+		health: health.NewServer(),
+	}
+	pb.RegisterNotificationServiceServer(s.grpcServer, s)
+	healthpb.RegisterHealthServer(s.grpcServer, s.health)
+	return s
+}
+
+// Start serves gRPC requests in the background
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	s.health.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	s.health.SetServingStatus(pb.NotificationService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+
+	go func() {
+		log.Printf("gRPC API listening on %s", s.addr)
+		if err := s.grpcServer.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			log.Printf("gRPC API server stopped: %v", err)
+		}
+	}()
+	return nil
+}
+
+// Shutdown reports NOT_SERVING, then waits for in-flight calls until ctx is done
+func (s *Server) Shutdown(ctx context.Context) {
+	s.health.Shutdown()
+
+	done := make(chan struct{})
+	go func() {
+		s.grpcServer.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		s.grpcServer.Stop()
+	}
+}
+
+// Send submits one notification
+func (s *Server) Send(ctx context.Context, req *pb.SendRequest) (*pb.SendResponse, error) {
+	if req.GetNotification() == nil {
+		return nil, status.Error(codes.InvalidArgument, "notification is required")
+	}
+	// There is no JSON body to validate: the request was decoded by protobuf, which
+	// already enforced field types.
+	msg := fromProto(req.GetNotification())
+	if err := handlers.ValidateNotification(msg); err != nil {
+		return nil, invalidArgument(err)
+	}
+	if req.GetMode() != pb.DeliveryMode_DELIVERY_MODE_SYNC && s.publisher == nil {
+		return nil, status.Error(codes.FailedPrecondition, "asynchronous submission is not available; use DELIVERY_MODE_SYNC")
+	}
+
+	resp, err := s.submit(ctx, msg, req.GetMode())
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	return resp, nil
+}
+
+// SendBatch submits several notifications, reporting each one's outcome in order
+func (s *Server) SendBatch(ctx context.Context, req *pb.SendBatchRequest) (*pb.SendBatchResponse, error) {
+	if len(req.GetNotifications()) > maxBatchSize {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d notifications per batch", maxBatchSize)
+	}
+	if req.GetMode() != pb.DeliveryMode_DELIVERY_MODE_SYNC && s.publisher == nil {
+		return nil, status.Error(codes.FailedPrecondition, "asynchronous submission is not available; use DELIVERY_MODE_SYNC")
+	}
+
+	resp := &pb.SendBatchResponse{}
+	for _, n := range req.GetNotifications() {
+		msg := fromProto(n)
+		if err := handlers.ValidateNotification(msg); err != nil {
+			resp.Results = append(resp.Results, &pb.SendResponse{Id: msg.EventID, Error: err.Error()})
+			continue
+		}
+		result, err := s.submit(ctx, msg, req.GetMode())
+		if err != nil {
+			result = &pb.SendResponse{Id: msg.EventID, Error: err.Error()}
+		}
+		resp.Results = append(resp.Results, result)
+	}
+	return resp, nil
+}
+
+// GetStatus returns a notification's processing status and channel attempts
+func (s *Server) GetStatus(ctx context.Context, req *pb.GetStatusRequest) (*pb.NotificationStatus, error) {
+	summary, err := deliverylog.Summarize(ctx, s.store, req.GetId())
+	if errors.Is(err, deliverylog.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "notification not found; it may still be queued")
+	}
+	if err != nil {
+		log.Printf("Failed to look up notification status: %v", err)
+		return nil, status.Error(codes.Internal, "failed to look up notification")
+	}
+	return statusToProto(summary), nil
+}
+
+// StreamStatus sends the notification's status each time it changes until it is
+// final, the notification is still unknown after statusNotFoundGrace, or
+// maxStatusStream has passed
+func (s *Server) StreamStatus(req *pb.StreamStatusRequest, stream pb.NotificationService_StreamStatusServer) error {
+	if req.GetId() == "" {
+		return status.Error(codes.InvalidArgument, "id is required")
+	}
+	ctx, cancel := context.WithTimeout(stream.Context(), maxStatusStream)
+	defer cancel()
+	ticker := time.NewTicker(statusPollInterval)
+	defer ticker.Stop()
+	notFoundAfter := time.Now().Add(statusNotFoundGrace)
+
+	var lastStatus string
+	lastAttempts := -1
+	for {
+		summary, err := deliverylog.Summarize(ctx, s.store, req.GetId())
+		if errors.Is(err, deliverylog.ErrNotFound) {
+			if time.Now().After(notFoundAfter) {
+				return status.Error(codes.NotFound, "notification not found")
+			}
+			summary, err = deliverylog.Summary{EventID: req.GetId()}, nil
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return streamEnded(ctx, stream.Context())
+			}
+			log.Printf("Failed to look up notification status: %v", err)
+			return status.Error(codes.Internal, "failed to look up notification")
+		}
+
+		current := statusToProto(summary)
+		if current.Status != lastStatus || len(current.Attempts) != lastAttempts {
+			if err := stream.Send(current); err != nil {
+				return err
+			}
+			lastStatus, lastAttempts = current.Status, len(current.Attempts)
+		}
+		if finalStatus(current.Status) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return streamEnded(ctx, stream.Context())
+		case <-ticker.C:
+		}
+	}
+}
+
+// streamEnded is the error for a status stream whose ctx is done: DeadlineExceeded
+// if it hit maxStatusStream, or the client's own cancellation.
+func streamEnded(ctx, client context.Context) error {
+	if client.Err() != nil {
+		return client.Err()
+	}
+	return status.Error(codes.DeadlineExceeded, "notification did not reach a final status in time")
+}
+
+// submit enqueues or processes a validated notification.
+func (s *Server) submit(ctx context.Context, msg models.NotificationMessage, mode pb.DeliveryMode) (*pb.SendResponse, error) {
+	if msg.EventID == "" {
+		msg.EventID = rabbitmq.NewMessageID()
+	}
+
+	if mode == pb.DeliveryMode_DELIVERY_MODE_SYNC {
+		result, err := s.processor.ProcessNotification(ctx, msg, SourceGRPC)
+		resp := &pb.SendResponse{Id: msg.EventID, Status: result}
+		if err != nil {
+			resp.Error = err.Error()
+		}
+		return resp, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	id, err := s.publisher.PublishNotification(ctx, msg)
+	if err != nil {
+		log.Printf("Failed to enqueue notification %s: %v", msg.EventID, err)
+		return nil, errors.New("failed to enqueue notification")
+	}
+	return &pb.SendResponse{Id: id, Status: statusQueued}, nil
+}
+
+// finalStatus reports whether a message status will not change any more. Failed
+// messages are retried by RabbitMQ, so they are not final.
+func finalStatus(s string) bool {
+	return s == deliverylog.MessageProcessed || s == deliverylog.MessageDuplicate || s == deliverylog.MessageInvalid
+}
@@ -0,0 +1,53 @@
+// handlers/cloudevents.go
+package handlers
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"notification-service/cloudevents"
+	"notification-service/envelope"
+	"notification-service/models"
+	"notification-service/rabbitmq"
+)
+
+// notificationBody returns the notification a consumed delivery carries: the body, or
+// the data of the CloudEvent in binary or structured mode it returns as well.
+func notificationBody(ctx context.Context, d rabbitmq.Delivery) ([]byte, *cloudevents.Event, error) {
+	e, ok, err := cloudevents.Parse(d.Body, d.ContentType, d.Headers)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !ok {
+		return d.Body, nil, nil
+	}
+
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.String("cloudevents.event_id", e.ID),
+		attribute.String("cloudevents.event_type", e.Type),
+		attribute.String("cloudevents.event_source", e.Source),
+		attribute.String("cloudevents.mode", e.Mode),
+	)
+	return e.Data, &e, nil
+}
+
+// decodeNotification decodes the notification returned by notificationBody. It returns
+// the message and the envelope version it was written in.
+func decodeNotification(body []byte, e *cloudevents.Event) (models.NotificationMessage, int, error) {
+	if e == nil {
+		return envelope.Decode(body)
+	}
+	return e.Notification()
+}
+
+// deviceTokenBody returns the device token message in a delivery: the body, or the data
+// of a CloudEvent. A CloudEvent's type is the event type when its data names none.
+func deviceTokenBody(d rabbitmq.Delivery) ([]byte, string, error) {
+	e, ok, err := cloudevents.Parse(d.Body, d.ContentType, d.Headers)
+	if err != nil || !ok {
+		return d.Body, "", err
+	}
+	return e.Data, e.Type, nil
+}
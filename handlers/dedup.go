@@ -0,0 +1,16 @@
+// handlers/dedup.go
+package handlers
+
+import "notification-service/models"
+
+// alreadyDone reports whether the message's event was already handled in scope.
+func (h *NotificationHandler) alreadyDone(msg models.NotificationMessage, scope string) bool {
+	return h.Dedup != nil && h.Dedup.Done(msg.EventID, scope)
+}
+
+// markDone records that the message's event was handled in scope.
+func (h *NotificationHandler) markDone(msg models.NotificationMessage, scope string) {
+	if h.Dedup != nil {
+		h.Dedup.MarkDone(msg.EventID, scope)
+	}
+}
@@ -0,0 +1,83 @@
+// handlers/delivery_log.go
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"notification-service/deliverylog"
+	"notification-service/logging"
+	"notification-service/models"
+	"notification-service/preferences"
+	"notification-service/rabbitmq"
+)
+
+// recordMessage writes the outcome of processing a message to the delivery log.
+func (h *NotificationHandler) recordMessage(msg models.NotificationMessage, d rabbitmq.Delivery, status string, procErr error, receivedAt time.Time) {
+	if h.DeliveryLog == nil {
+		return
+	}
+	record := deliverylog.Message{
+		EventID:          msg.EventID,
+		NotificationType: msg.NotificationType,
+		UserID:           msg.Recipient.UserID,
+		ApplicationID:    msg.Payload.ApplicationID,
+		OpportunityID:    msg.Payload.OpportunityID,
+		Queue:            d.Queue,
+		RoutingKey:       d.RoutingKey,
+		Status:           status,
+		ReceivedAt:       receivedAt,
+		ProcessedAt:      time.Now(),
+	}
+	if procErr != nil {
+		record.Error = procErr.Error()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := h.DeliveryLog.RecordMessage(ctx, record); err != nil {
+		slog.Error("Failed to record message in delivery log", logging.KeyEventID, msg.EventID, "error", err)
+	}
+}
+
+// recordAttempt writes a channel attempt to the delivery log, filling in the message
+// fields, and publishes the matching status event.
+func (h *NotificationHandler) recordAttempt(msg models.NotificationMessage, a deliverylog.Attempt) {
+	a.EventID = msg.EventID
+	a.NotificationType = msg.NotificationType
+	a.UserID = msg.Recipient.UserID
+	a.ApplicationID = msg.Payload.ApplicationID
+	a.OpportunityID = msg.Payload.OpportunityID
+	a.CreatedAt = time.Now()
+
+	observeAttempt(a)
+	h.publishStatus(a)
+	if h.DeliveryLog == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := h.DeliveryLog.RecordAttempt(ctx, a); err != nil {
+		slog.Error("Failed to record attempt in delivery log",
+			logging.KeyEventID, msg.EventID, logging.KeyChannel, a.Channel, "error", err)
+	}
+}
+
+// recordSkipped records a channel that was not attempted because the recipient
+// opted out or has no address/token for it.
+func (h *NotificationHandler) recordSkipped(msg models.NotificationMessage, channel string, enabled, hasTarget bool) {
+	reason := deliverylog.ReasonPrefDisabled
+	if enabled && !hasTarget {
+		reason = deliverylog.ReasonNoDeviceToken
+		if channel == preferences.ChannelEmail {
+			reason = deliverylog.ReasonNoEmailAddress
+		}
+	}
+	h.recordAttempt(msg, deliverylog.Attempt{
+		Channel: channel,
+		Status:  deliverylog.StatusSkipped,
+		Reason:  reason,
+	})
+}
@@ -0,0 +1,190 @@
+// handlers/email.go
+package handlers
+
+import (
+	"context"
+	"errors"
+	"html"
+	"log/slog"
+	"strings"
+	"time"
+
+	"notification-service/bounces"
+	"notification-service/dedup"
+	"notification-service/deliverylog"
+	"notification-service/digest"
+	"notification-service/models"
+	"notification-service/preferences"
+	"notification-service/services/email"
+	"notification-service/unsubscribe"
+)
+
+// sendEmail emails the recipient, or buffers the message into a digest if the
+// recipient (or the type's default) asks for hourly/daily delivery.
+func (h *NotificationHandler) sendEmail(ctx context.Context, msg models.NotificationMessage, subject, body string) (err error) {
+	ctx, span := startChannelSpan(ctx, preferences.ChannelEmail, email.ProviderName)
+	defer func() { endChannelSpan(span, err) }()
+
+	if h.alreadyDone(msg, dedup.ScopeEmail) {
+		slog.InfoContext(ctx, "Email already sent for event; skipping")
+		return nil
+	}
+
+	if h.Suppressions != nil {
+		entry, err := h.Suppressions.Get(msg.Recipient.EmailAddress)
+		if err == nil {
+			slog.InfoContext(ctx, "Not emailing: address is suppressed", "reason", entry.Reason)
+			h.recordAttempt(msg, deliverylog.Attempt{
+				Channel: preferences.ChannelEmail,
+				Status:  deliverylog.StatusSuppressed,
+				Reason:  entry.Reason,
+			})
+			return nil
+		}
+		if !errors.Is(err, bounces.ErrNotFound) {
+			return err
+		}
+	}
+
+	if h.Digests != nil {
+		if mode := h.digestMode(msg); mode != digest.ModeImmediate {
+			if err := h.Digests.Add(msg, mode, h.recipientTimezone(msg)); err != nil {
+				return err
+			}
+			h.markDone(msg, dedup.ScopeEmail)
+			h.recordAttempt(msg, deliverylog.Attempt{
+				Channel: preferences.ChannelEmail,
+				Status:  deliverylog.StatusDigested,
+				Reason:  mode,
+			})
+			return nil
+		}
+	}
+
+	if h.EmailService == nil {
+		slog.InfoContext(ctx, "Email service not configured; not sending email")
+		return nil
+	}
+
+	reservation := h.reserve(msg, preferences.ChannelEmail)
+	if reservation == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	start := time.Now()
+	m := email.Message{
+		To:       msg.Recipient.EmailAddress,
+		Subject:  subject,
+		TextBody: body,
+		HTMLBody: msg.Payload.BodyHTML,
+	}
+	h.addTracking(msg, &m)
+	h.addUnsubscribe(msg, &m)
+	messageID, err := h.EmailService.Send(ctx, m)
+	h.recordProviderResult(email.ProviderName, err)
+	attempt := deliverylog.Attempt{
+		Channel:           preferences.ChannelEmail,
+		Provider:          email.ProviderName,
+		Status:            deliverylog.StatusSent,
+		ProviderMessageID: messageID,
+		Latency:           time.Since(start),
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "Error sending email", "error", err)
+		attempt.Status = deliverylog.StatusFailed
+		attempt.Error = err.Error()
+		h.recordAttempt(msg, attempt)
+		reservation.Cancel()
+		return err
+	}
+	h.recordAttempt(msg, attempt)
+	h.markDone(msg, dedup.ScopeEmail)
+	reservation.Commit()
+	return nil
+}
+
+// addTracking routes links through the click redirect and adds the open pixel,
+// unless the recipient has opted out of tracking.
+func (h *NotificationHandler) addTracking(msg models.NotificationMessage, m *email.Message) {
+	if h.Tracking == nil {
+		return
+	}
+	if h.Preferences != nil && !h.Preferences.TrackingAllowed(msg.Recipient.UserID) {
+		return
+	}
+	m.TextBody = h.Tracking.RewriteText(m.TextBody, msg)
+	if m.HTMLBody != "" {
+		m.HTMLBody = h.Tracking.RewriteHTML(m.HTMLBody, msg)
+	}
+}
+
+// addUnsubscribe appends a one-click unsubscribe link for this notification type
+// to the bodies and sets the RFC 8058 List-Unsubscribe headers.
+func (h *NotificationHandler) addUnsubscribe(msg models.NotificationMessage, m *email.Message) {
+	if h.Unsubscribe == nil {
+		return
+	}
+	link := h.Unsubscribe.URL(msg.Recipient.UserID, msg.NotificationType, preferences.ChannelEmail)
+	m.Headers = unsubscribe.Headers(link)
+	m.TextBody += "\n\nTo stop receiving these emails, visit: " + link
+
+	if m.HTMLBody == "" {
+		return
+	}
+	footer := `<p style="color: #888; font-size: 12px;"><a href="` + html.EscapeString(link) + `" style="color: #888;">Unsubscribe</a></p>`
+	if i := strings.LastIndex(strings.ToLower(m.HTMLBody), "</body>"); i >= 0 {
+		m.HTMLBody = m.HTMLBody[:i] + footer + m.HTMLBody[i:]
+	} else {
+		m.HTMLBody += footer
+	}
+}
+
+// digestMode returns the recipient's delivery mode for this notification type,
+// falling back to the configured default for the type.
+func (h *NotificationHandler) digestMode(msg models.NotificationMessage) string {
+	if h.Preferences != nil {
+		if mode, ok := h.Preferences.DigestMode(msg.Recipient.UserID, msg.NotificationType); ok {
+			return mode
+		}
+	}
+	return h.Digests.DefaultMode(msg.NotificationType)
+}
+
+// recipientTimezone returns the stored timezone, falling back to the message's.
+func (h *NotificationHandler) recipientTimezone(msg models.NotificationMessage) string {
+	if h.Preferences != nil {
+		if timezone, ok := h.Preferences.Timezone(msg.Recipient.UserID); ok {
+			return timezone
+		}
+	}
+	return msg.Recipient.Timezone
+}
+
+// RecordDigest records a digest send attempt, as the email channel attempt of every
+// event in the digest, in the delivery log and as status events.
+func (h *NotificationHandler) RecordDigest(r digest.Result) {
+	h.recordProviderResult(email.ProviderName, r.Err)
+	attempt := deliverylog.Attempt{
+		Channel:           preferences.ChannelEmail,
+		Provider:          email.ProviderName,
+		Status:            deliverylog.StatusSent,
+		Reason:            r.Batch.Mode + " digest",
+		ProviderMessageID: r.ProviderMessageID,
+		Latency:           r.Latency,
+	}
+	if r.Err != nil {
+		attempt.Status = deliverylog.StatusFailed
+		attempt.Error = r.Err.Error()
+	}
+
+	for _, entry := range r.Batch.Entries {
+		h.recordAttempt(models.NotificationMessage{
+			NotificationType: r.Batch.NotificationType,
+			EventID:          entry.EventID,
+			Recipient:        models.Recipient{UserID: r.Batch.UserID},
+			Payload:          models.Payload{ApplicationID: entry.ApplicationID, OpportunityID: entry.OpportunityID},
+		}, attempt)
+	}
+}
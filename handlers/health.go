@@ -0,0 +1,9 @@
+// handlers/health.go
+package handlers
+
+// recordProviderResult feeds a send result into the provider's readiness circuit.
+func (h *NotificationHandler) recordProviderResult(provider string, err error) {
+	if h.ProviderHealth != nil {
+		h.ProviderHealth.Record(provider, err)
+	}
+}
@@ -0,0 +1,27 @@
+// handlers/metrics.go
+package handlers
+
+import (
+	"time"
+
+	"notification-service/constants"
+	"notification-service/deliverylog"
+	"notification-service/metrics"
+)
+
+// observeProcessing records how long a message took to process and its outcome.
+// Unrecognised types share one label so bad input cannot grow the series without bound.
+func observeProcessing(notificationType, status string, receivedAt time.Time) {
+	if _, ok := constants.NotificationRoutingKeys[notificationType]; !ok {
+		notificationType = "unknown"
+	}
+	metrics.ProcessingDuration.WithLabelValues(notificationType, status).Observe(time.Since(receivedAt).Seconds())
+}
+
+// observeAttempt counts a channel attempt by outcome, and the provider latency of real sends.
+func observeAttempt(a deliverylog.Attempt) {
+	metrics.ChannelSends.WithLabelValues(a.Channel, a.Provider, a.Status).Inc()
+	if a.Status == deliverylog.StatusSent || a.Status == deliverylog.StatusFailed {
+		metrics.ChannelSendDuration.WithLabelValues(a.Channel, a.Provider).Observe(a.Latency.Seconds())
+	}
+}
@@ -2,78 +2,207 @@
 package handlers
 
 import (
-	"encoding/json"
-	"log"
-
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"notification-service/bounces"
+	"notification-service/dedup"
+	"notification-service/deliverylog"
+	"notification-service/digest"
+	"notification-service/health"
+	"notification-service/logging"
 	"notification-service/models" // Make sure this path is correct for your models
-	// You will eventually import your actual email and FCM service packages here, e.g.:
-	// "notification-service/services/email"
-	// "notification-service/services/fcm"
+	"notification-service/preferences"
+	"notification-service/rabbitmq"
+	"notification-service/ratelimit"
+	"notification-service/scheduler"
+	"notification-service/services/email"
+	"notification-service/services/push"
+	"notification-service/tokens"
+	"notification-service/tracing"
+	"notification-service/tracking"
+	"notification-service/unsubscribe"
 )
 
 // NotificationHandler handles incoming notification messages
 type NotificationHandler struct {
-	// You should add fields for your actual email and push notification services here.
-	// For example:
-	// EmailService *email.Service
-	// FCMService *fcm.Service
+	// EmailService sends email over SMTP. Nil means emails are only logged.
+	EmailService *email.Service
+	// PushService delivers pushes through FCM/APNs. Nil means pushes are only logged.
+	PushService *push.Service
+	// Tokens is the device token registry. Nil means only message-supplied tokens are used.
+	Tokens tokens.Store
+	// Events publishes service events (e.g. token invalidation) back to RabbitMQ.
+	Events EventPublisher
+	// Preferences resolves per-type, per-channel opt-outs. Nil means only message prefs are used.
+	Preferences *preferences.Resolver
+	// Scheduler holds notifications deferred by quiet hours. Nil disables quiet hours.
+	Scheduler *scheduler.Scheduler
+	// QuietHoursPolicy lists the urgent notification types that bypass quiet hours.
+	QuietHoursPolicy *scheduler.Policy
+	// Digests buffers emails for recipients who chose hourly/daily digests. Nil sends every email immediately.
+	Digests *digest.Digester
+	// RateLimiter caps and collapses notifications per recipient and type. Nil disables capping.
+	RateLimiter *ratelimit.Limiter
+	// Dedup makes redeliveries idempotent per event and per channel. Nil disables deduplication.
+	Dedup *dedup.Deduplicator
+	// Suppressions lists bounced and complaining addresses that must not be emailed. Nil disables the check.
+	Suppressions bounces.Store
+	// Unsubscribe adds signed one-click unsubscribe links and headers to emails. Nil omits them.
+	Unsubscribe *unsubscribe.Links
+	// Tracking rewrites email links through the click redirect and adds an open pixel,
+	// unless the recipient opted out. Nil disables tracking.
+	Tracking *tracking.Tracker
+	// DeliveryLog records every processed message and channel attempt. Nil disables the audit log.
+	DeliveryLog deliverylog.Store
+	// ProviderHealth tracks send failures per provider for the readiness probe. Nil disables tracking.
+	ProviderHealth *health.Circuits
+	// ValidationMode decides what happens to consumed messages that do not match their
+	// JSON Schema (schema.ModeWarn or schema.ModeStrict). Empty disables validation.
+	ValidationMode string
+}
+
+// EventPublisher publishes events from this service to the notification exchange.
+type EventPublisher interface {
+	Publish(routingKey string, event interface{}) error
 }
 
 // NewNotificationHandler creates a new notification handler
 func NewNotificationHandler() *NotificationHandler {
-	return &NotificationHandler{
-		// Initialize your services here when they are implemented, e.g.:
-		// EmailService: email.NewService(),
-		// FCMService: fcm.NewService(),
-	}
+	return &NotificationHandler{}
 }
 
-// ProcessMessage processes a notification message by unmarshaling it
-// and routing it to the appropriate handler based on its type.
-func (h *NotificationHandler) ProcessMessage(body []byte) error {
+// ProcessMessage processes a notification message, plain or carried by a CloudEvent,
+// by decoding its schema version and routing it to the appropriate handler based on its type.
+func (h *NotificationHandler) ProcessMessage(ctx context.Context, d rabbitmq.Delivery) error {
+	receivedAt := time.Now()
+
+	body, event, err := notificationBody(ctx, d)
 	var msg models.NotificationMessage
+	version := 0
+	if err == nil {
+		msg, version, err = decodeNotification(body, event)
+	}
+	if msg.EventID == "" {
+		msg.EventID = d.MessageID
+	}
+	// The body is checked rather than the decoded message, so a message whose fields are
+	// of the wrong type is reported as invalid even though it fails to decode.
+	if body != nil {
+		if err := h.validateMessage(withMessageFields(ctx, msg), body, event); err != nil {
+			h.recordMessage(msg, d, deliverylog.MessageInvalid, err, receivedAt)
+			observeProcessing(msg.NotificationType, deliverylog.MessageInvalid, receivedAt)
+			return err
+		}
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to decode message", "error", err, "schema_version", version, "body_bytes", len(d.Body))
+		h.recordMessage(models.NotificationMessage{EventID: d.MessageID}, d, deliverylog.MessageInvalid, err, receivedAt)
+		observeProcessing("", deliverylog.MessageInvalid, receivedAt)
+		// Redelivering a malformed message, or one of a version this build does not know, cannot fix it.
+		return fmt.Errorf("%w: %v", rabbitmq.ErrDeadLetter, err)
+	}
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Int("notification.schema_version", version))
+
+	status, err := h.process(ctx, msg, d)
+	h.recordMessage(msg, d, status, err, receivedAt)
+	observeProcessing(msg.NotificationType, status, receivedAt)
+	return err
+}
+
+// ProcessNotification processes a message submitted directly (HTTP or gRPC) instead of
+// consumed from RabbitMQ. source is recorded as the queue in the delivery log.
+// It returns the delivery log status for the message. Processing is not cut short
+// if ctx is cancelled, e.g. because the caller disconnected.
+func (h *NotificationHandler) ProcessNotification(ctx context.Context, msg models.NotificationMessage, source string) (string, error) {
+	receivedAt := time.Now()
+	d := rabbitmq.Delivery{MessageID: msg.EventID, Queue: source}
+
+	ctx, span := tracing.Tracer().Start(context.WithoutCancel(ctx), source+" process")
+	defer span.End()
+	status, err := h.process(ctx, msg, d)
+	h.recordMessage(msg, d, status, err, receivedAt)
+	observeProcessing(msg.NotificationType, status, receivedAt)
+	return status, err
+}
+
+// process deduplicates, applies quiet hours and dispatches a decoded message.
+// It returns the delivery log status for the message.
+func (h *NotificationHandler) process(ctx context.Context, msg models.NotificationMessage, d rabbitmq.Delivery) (string, error) {
+	ctx = withMessageFields(ctx, msg)
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.String("notification.type", msg.NotificationType),
+		attribute.String("notification.event_id", msg.EventID),
+		attribute.String("notification.user_id", msg.Recipient.UserID))
+
+	if h.alreadyDone(msg, messageScope(d.Queue)) {
+		slog.InfoContext(ctx, "Skipping already processed event", "redelivered", d.Redelivered)
+		return deliverylog.MessageDuplicate, nil
+	}
 
-	err := json.Unmarshal(body, &msg)
+	slog.InfoContext(ctx, "Processing message",
+		"push_pref", msg.Recipient.Prefs.ReceivePush, "email_pref", msg.Recipient.Prefs.ReceiveEmail)
+
+	// Hold non-urgent notifications until the recipient's quiet hours end
+	deferred, err := h.deferForQuietHours(ctx, msg, d.Queue)
 	if err != nil {
-		log.Printf("Failed to unmarshal JSON message: %v. Raw Body: %s", err, body)
-		return err
+		return deliverylog.MessageFailed, err
+	}
+	if deferred {
+		h.markDone(msg, messageScope(d.Queue))
+		return deliverylog.MessageDeferred, nil
 	}
 
-	log.Printf("Successfully unmarshaled message: NotificationType=%s, RecipientID=%s, PushPref=%t, EmailPref=%t",
-		msg.NotificationType, msg.Recipient.UserID, msg.Recipient.Prefs.ReceivePush, msg.Recipient.Prefs.ReceiveEmail)
+	if err := h.dispatch(ctx, msg, d.Queue); err != nil {
+		return deliverylog.MessageFailed, err
+	}
+	h.markDone(msg, messageScope(d.Queue))
+	return deliverylog.MessageProcessed, nil
+}
 
+// dispatch routes a decoded message to the handler for its notification type. queue is
+// the queue it was consumed from, which decides the channels it is sent on.
+func (h *NotificationHandler) dispatch(ctx context.Context, msg models.NotificationMessage, queue string) error {
 	// Process based on notification type
 	switch msg.NotificationType {
 	// --- Volunteer-centric Application Status Updates ---
 	case "APPLICATION_ACCEPTED":
-		return h.handleApplicationStatusUpdate(msg)
+		return h.handleApplicationStatusUpdate(ctx, msg, queue)
 	case "APPLICATION_REJECTED":
-		return h.handleApplicationStatusUpdate(msg)
+		return h.handleApplicationStatusUpdate(ctx, msg, queue)
 	case "APPLICATION_COMPLETED":
-		return h.handleApplicationStatusUpdate(msg)
+		return h.handleApplicationStatusUpdate(ctx, msg, queue)
 	case "APPLICATION_STATUS_CHANGED": // General fallback for any other status change to volunteer
-		return h.handleApplicationStatusUpdate(msg)
+		return h.handleApplicationStatusUpdate(ctx, msg, queue)
+	case "VOLUNTEER_APPLICATION_STATUS_UPDATE":
+		return h.handleApplicationStatusUpdate(ctx, msg, queue)
 
 	// --- NGO-centric Application Events ---
 	case "APPLICATION_WITHDRAWN": // This event is directed to the NGO
-		return h.handleNgoApplicationEvent(msg)
+		return h.handleNgoApplicationEvent(ctx, msg, queue)
 	case "NGO_NEW_APPLICATION":
-		return h.handleNgoNewApplication(msg) // Existing handler for new applications to NGOs
+		return h.handleNgoNewApplication(ctx, msg, queue) // Existing handler for new applications to NGOs
 
 	// --- Other Specific Notification Types ---
 	case "VOLUNTEER_NEW_MATCHING_OPPORTUNITY":
-		return h.handleVolunteerNewOpportunity(msg)
+		return h.handleVolunteerNewOpportunity(ctx, msg, queue)
 
 		// --- Opportunity Management Notifications ---
 	case "OPPORTUNITY_UPDATED":
-		log.Printf("Handling Opportunity Updated: %s", msg.Payload.OpportunityTitle)
-		return h.handleOpportunityUpdate(msg)
+		slog.DebugContext(ctx, "Routing opportunity update")
+		return h.handleOpportunityUpdate(ctx, msg, queue)
 	case "OPPORTUNITY_DELETED":
-		log.Printf("Handling Opportunity Deleted: %s", msg.Payload.OpportunityTitle)
-		return h.handleOppotunityDeleted(msg)
+		slog.DebugContext(ctx, "Routing opportunity deletion")
+		return h.handleOppotunityDeleted(ctx, msg, queue)
 
 	default:
-		log.Printf("Unknown notification type received: %s. Raw Message: %s", msg.NotificationType, body)
+		slog.WarnContext(ctx, "Unknown notification type received")
 		// Consider returning an error for unhandled types in a production system
 		// return fmt.Errorf("unknown notification type: %s", msg.NotificationType)
 	}
@@ -82,10 +211,11 @@ func (h *NotificationHandler) ProcessMessage(body []byte) error {
 }
 
 // handleApplicationStatusUpdate processes notifications for volunteers about their application status changes.
-// This single function handles ACCEPTED, REJECTED, COMPLETED, and general STATUS_CHANGED notifications.
-func (h *NotificationHandler) handleApplicationStatusUpdate(msg models.NotificationMessage) error {
-	log.Printf("Handling Volunteer Application Status Update: Type=%s, AppID=%d, OldStatus=%s, NewStatus=%s, VolunteerName=%s",
-		msg.NotificationType, msg.Payload.ApplicationID, msg.Payload.OldStatus, msg.Payload.NewStatus, msg.Payload.VolunteerName)
+// This single function handles ACCEPTED, REJECTED, COMPLETED, VOLUNTEER_APPLICATION_STATUS_UPDATE and general
+// STATUS_CHANGED notifications.
+func (h *NotificationHandler) handleApplicationStatusUpdate(ctx context.Context, msg models.NotificationMessage, queue string) error {
+	slog.InfoContext(ctx, "Handling volunteer application status update",
+		"application_id", msg.Payload.ApplicationID, "old_status", msg.Payload.OldStatus, "new_status", msg.Payload.NewStatus)
 
 	// Extract common notification content from payload
 	title := msg.Payload.Title
@@ -94,44 +224,38 @@ func (h *NotificationHandler) handleApplicationStatusUpdate(msg models.Notificat
 	deepLink := msg.Payload.DeepLink // Declared and assigned
 
 	// --- Email Logic for Volunteer ---
-	if msg.Recipient.Prefs.ReceiveEmail && msg.Recipient.EmailAddress != "" {
-		log.Printf("Attempting to send Email to Volunteer %s (%s) for status '%s'. Subject: '%s'",
-			msg.Recipient.UserID, msg.Recipient.EmailAddress, msg.Payload.NewStatus, subject)
-		// TODO: Call your actual Email sending function here, e.g.:
-		// if h.EmailService != nil {
-		//     err := h.EmailService.SendEmail(msg.Recipient.EmailAddress, subject, body /* , optional HTML body */)
-		//     if err != nil {
-		//         log.Printf("Error sending email to %s: %v", msg.Recipient.EmailAddress, err)
-		//     }
-		// }
-	} else {
-		log.Printf("Skipping Email for Volunteer %s (Pref: %t, Email: %t). Status: %s",
-			msg.Recipient.UserID, msg.Recipient.Prefs.ReceiveEmail, msg.Recipient.EmailAddress != "", msg.Payload.NewStatus)
+	var emailErr error
+	if sendsOn(queue, preferences.ChannelEmail) {
+		emailEnabled := h.channelEnabled(msg, preferences.ChannelEmail)
+		if emailEnabled && msg.Recipient.EmailAddress != "" {
+			slog.InfoContext(ctx, "Sending email to volunteer", "subject", subject)
+			emailErr = h.sendEmail(ctx, msg, subject, body)
+		} else {
+			slog.InfoContext(ctx, "Skipping email", "pref_enabled", emailEnabled, "has_address", msg.Recipient.EmailAddress != "")
+			h.recordSkipped(msg, preferences.ChannelEmail, emailEnabled, msg.Recipient.EmailAddress != "")
+		}
 	}
 
 	// --- Push Notification Logic for Volunteer ---
-	if msg.Recipient.Prefs.ReceivePush && msg.Recipient.DeviceToken != "" {
-		// Used deepLink in log.Printf
-		log.Printf("Attempting to send Push to Volunteer %s (%s) for status '%s'. Title: '%s', DeepLink: '%s', Body: '%s'",
-			msg.Recipient.UserID, msg.Recipient.DeviceToken, msg.Payload.NewStatus, title, deepLink, body)
-		// TODO: Call your actual FCM sending function here, e.g.:
-		// if h.FCMService != nil {
-		//     err := h.FCMService.SendPushNotification(msg.Recipient.DeviceToken, title, body, deepLink)
-		//     if err != nil {
-		//         log.Printf("Error sending push notification to %s: %v", msg.Recipient.UserID, err)
-		//     }
-		// }
-	} else {
-		log.Printf("Skipping Push for Volunteer %s (Pref: %t, Token: %t). Status: %s",
-			msg.Recipient.UserID, msg.Recipient.Prefs.ReceivePush, msg.Recipient.DeviceToken != "", msg.Payload.NewStatus)
+	var pushErr error
+	if sendsOn(queue, preferences.ChannelPush) {
+		pushTargets := h.pushTargets(ctx, msg)
+		pushEnabled := h.channelEnabled(msg, preferences.ChannelPush)
+		if pushEnabled && len(pushTargets) > 0 {
+			slog.InfoContext(ctx, "Sending push to volunteer", "devices", len(pushTargets), "deep_link", deepLink)
+			pushErr = h.sendPush(ctx, msg, pushTargets, title, body, deepLink)
+		} else {
+			slog.InfoContext(ctx, "Skipping push", "pref_enabled", pushEnabled, "has_token", len(pushTargets) > 0)
+			h.recordSkipped(msg, preferences.ChannelPush, pushEnabled, len(pushTargets) > 0)
+		}
 	}
-	return nil
+	return errors.Join(emailErr, pushErr)
 }
 
 // handleNgoApplicationEvent processes notifications for NGOs about application events (e.g., withdrawn).
-func (h *NotificationHandler) handleNgoApplicationEvent(msg models.NotificationMessage) error {
-	log.Printf("Handling NGO Application Event: Type=%s, AppID=%d, VolunteerName=%s, OpportunityTitle=%s",
-		msg.NotificationType, msg.Payload.ApplicationID, msg.Payload.VolunteerName, msg.Payload.OpportunityTitle)
+func (h *NotificationHandler) handleNgoApplicationEvent(ctx context.Context, msg models.NotificationMessage, queue string) error {
+	slog.InfoContext(ctx, "Handling NGO application event",
+		"application_id", msg.Payload.ApplicationID, "opportunity_title", msg.Payload.OpportunityTitle)
 
 	// Extract common notification content from payload
 	title := msg.Payload.Title
@@ -140,34 +264,38 @@ func (h *NotificationHandler) handleNgoApplicationEvent(msg models.NotificationM
 	deepLink := msg.Payload.DeepLink // Declared and assigned
 
 	// --- Email Logic for NGO ---
-	if msg.Recipient.Prefs.ReceiveEmail && msg.Recipient.EmailAddress != "" {
-		log.Printf("Attempting to send Email to NGO %s (%s) for event '%s'. Subject: '%s'",
-			msg.Recipient.UserID, msg.Recipient.EmailAddress, msg.NotificationType, subject)
-		// TODO: Call your actual Email sending function here
-		// if h.EmailService != nil { ... }
-	} else {
-		log.Printf("Skipping Email for NGO %s (Pref: %t, Email: %t). Event: %s",
-			msg.Recipient.UserID, msg.Recipient.Prefs.ReceiveEmail, msg.Recipient.EmailAddress != "", msg.NotificationType)
+	var emailErr error
+	if sendsOn(queue, preferences.ChannelEmail) {
+		emailEnabled := h.channelEnabled(msg, preferences.ChannelEmail)
+		if emailEnabled && msg.Recipient.EmailAddress != "" {
+			slog.InfoContext(ctx, "Sending email to NGO", "subject", subject)
+			emailErr = h.sendEmail(ctx, msg, subject, body)
+		} else {
+			slog.InfoContext(ctx, "Skipping email", "pref_enabled", emailEnabled, "has_address", msg.Recipient.EmailAddress != "")
+			h.recordSkipped(msg, preferences.ChannelEmail, emailEnabled, msg.Recipient.EmailAddress != "")
+		}
 	}
 
 	// --- Push Notification Logic for NGO ---
-	if msg.Recipient.Prefs.ReceivePush && msg.Recipient.DeviceToken != "" {
-		// Used deepLink in log.Printf
-		log.Printf("Attempting to send Push to NGO %s (%s) for event '%s'. Title: '%s', DeepLink: '%s', Body: '%s'",
-			msg.Recipient.UserID, msg.Recipient.DeviceToken, msg.NotificationType, title, deepLink, body)
-		// TODO: Call your actual FCM sending function here
-		// if h.FCMService != nil { ... }
-	} else {
-		log.Printf("Skipping Push for NGO %s (Pref: %t, Token: %t). Event: %s",
-			msg.Recipient.UserID, msg.Recipient.Prefs.ReceivePush, msg.Recipient.DeviceToken != "", msg.NotificationType)
+	var pushErr error
+	if sendsOn(queue, preferences.ChannelPush) {
+		pushTargets := h.pushTargets(ctx, msg)
+		pushEnabled := h.channelEnabled(msg, preferences.ChannelPush)
+		if pushEnabled && len(pushTargets) > 0 {
+			slog.InfoContext(ctx, "Sending push to NGO", "devices", len(pushTargets), "deep_link", deepLink)
+			pushErr = h.sendPush(ctx, msg, pushTargets, title, body, deepLink)
+		} else {
+			slog.InfoContext(ctx, "Skipping push", "pref_enabled", pushEnabled, "has_token", len(pushTargets) > 0)
+			h.recordSkipped(msg, preferences.ChannelPush, pushEnabled, len(pushTargets) > 0)
+		}
 	}
-	return nil
+	return errors.Join(emailErr, pushErr)
 }
 
 // handleNgoNewApplication handles new applications for NGOs.
-func (h *NotificationHandler) handleNgoNewApplication(msg models.NotificationMessage) error {
-	log.Printf("Handling NGO New Application: Type=%s, AppID=%d, VolunteerName=%s, OpportunityTitle=%s",
-		msg.NotificationType, msg.Payload.ApplicationID, msg.Payload.VolunteerName, msg.Payload.OpportunityTitle)
+func (h *NotificationHandler) handleNgoNewApplication(ctx context.Context, msg models.NotificationMessage, queue string) error {
+	slog.InfoContext(ctx, "Handling NGO new application",
+		"application_id", msg.Payload.ApplicationID, "opportunity_title", msg.Payload.OpportunityTitle)
 
 	title := msg.Payload.Title
 	body := msg.Payload.Body
@@ -175,32 +303,38 @@ func (h *NotificationHandler) handleNgoNewApplication(msg models.NotificationMes
 	deepLink := msg.Payload.DeepLink // Declared and assigned
 
 	// --- Email Logic for NGO ---
-	if msg.Recipient.Prefs.ReceiveEmail && msg.Recipient.EmailAddress != "" {
-		log.Printf("Attempting to send Email for NGO: %s - Subject: '%s'",
-			msg.Recipient.UserID, subject)
-		// TODO: Call actual Email sending function here
-	} else {
-		log.Printf("Skipping Email for NGO %s (Pref: %t, Email: %t)",
-			msg.Recipient.UserID, msg.Recipient.Prefs.ReceiveEmail, msg.Recipient.EmailAddress != "")
+	var emailErr error
+	if sendsOn(queue, preferences.ChannelEmail) {
+		emailEnabled := h.channelEnabled(msg, preferences.ChannelEmail)
+		if emailEnabled && msg.Recipient.EmailAddress != "" {
+			slog.InfoContext(ctx, "Sending email to NGO", "subject", subject)
+			emailErr = h.sendEmail(ctx, msg, subject, body)
+		} else {
+			slog.InfoContext(ctx, "Skipping email", "pref_enabled", emailEnabled, "has_address", msg.Recipient.EmailAddress != "")
+			h.recordSkipped(msg, preferences.ChannelEmail, emailEnabled, msg.Recipient.EmailAddress != "")
+		}
 	}
 
 	// --- Push Notification Logic for NGO ---
-	if msg.Recipient.Prefs.ReceivePush && msg.Recipient.DeviceToken != "" {
-		// Used deepLink in log.Printf
-		log.Printf("Attempting to send Push for NGO: %s - Title: '%s', DeepLink: '%s', Body: '%s'",
-			msg.Recipient.UserID, title, deepLink, body)
-		// TODO: Call actual FCM sending function here for NGO
-	} else {
-		log.Printf("Skipping Push for NGO %s (Pref: %t, Token: %t)",
-			msg.Recipient.UserID, msg.Recipient.Prefs.ReceivePush, msg.Recipient.DeviceToken != "")
+	var pushErr error
+	if sendsOn(queue, preferences.ChannelPush) {
+		pushTargets := h.pushTargets(ctx, msg)
+		pushEnabled := h.channelEnabled(msg, preferences.ChannelPush)
+		if pushEnabled && len(pushTargets) > 0 {
+			slog.InfoContext(ctx, "Sending push to NGO", "devices", len(pushTargets), "deep_link", deepLink)
+			pushErr = h.sendPush(ctx, msg, pushTargets, title, body, deepLink)
+		} else {
+			slog.InfoContext(ctx, "Skipping push", "pref_enabled", pushEnabled, "has_token", len(pushTargets) > 0)
+			h.recordSkipped(msg, preferences.ChannelPush, pushEnabled, len(pushTargets) > 0)
+		}
 	}
-	return nil
+	return errors.Join(emailErr, pushErr)
 }
 
 // handleVolunteerNewOpportunity handles notifications for volunteers about new matching opportunities.
-func (h *NotificationHandler) handleVolunteerNewOpportunity(msg models.NotificationMessage) error {
-	log.Printf("Handling Volunteer New Matching Opportunity: Type=%s, OpportunityTitle=%s",
-		msg.NotificationType, msg.Payload.OpportunityTitle)
+func (h *NotificationHandler) handleVolunteerNewOpportunity(ctx context.Context, msg models.NotificationMessage, queue string) error {
+	slog.InfoContext(ctx, "Handling volunteer new matching opportunity",
+		"opportunity_title", msg.Payload.OpportunityTitle)
 
 	title := msg.Payload.Title
 	body := msg.Payload.Body
@@ -208,32 +342,38 @@ func (h *NotificationHandler) handleVolunteerNewOpportunity(msg models.Notificat
 	deepLink := msg.Payload.DeepLink // Declared and assigned
 
 	// For new opportunities, assume only push notifications for volunteers (or add email if desired)
-	if msg.Recipient.Prefs.ReceivePush && msg.Recipient.DeviceToken != "" {
-		// Used deepLink in log.Printf
-		log.Printf("Attempting to send Push for Volunteer (New Matching Opportunity): %s - Title: '%s', DeepLink: '%s', Body: '%s'",
-			msg.Recipient.UserID, title, deepLink, body)
-		// TODO: Call actual FCM sending function here
-	} else {
-		log.Printf("Skipping Push for Volunteer %s (Pref: %t, Token: %t). Type: %s",
-			msg.Recipient.UserID, msg.Recipient.Prefs.ReceivePush, msg.Recipient.DeviceToken != "", msg.NotificationType)
+	var pushErr error
+	if sendsOn(queue, preferences.ChannelPush) {
+		pushTargets := h.pushTargets(ctx, msg)
+		pushEnabled := h.channelEnabled(msg, preferences.ChannelPush)
+		if pushEnabled && len(pushTargets) > 0 {
+			slog.InfoContext(ctx, "Sending push to volunteer", "devices", len(pushTargets), "deep_link", deepLink)
+			pushErr = h.sendPush(ctx, msg, pushTargets, title, body, deepLink)
+		} else {
+			slog.InfoContext(ctx, "Skipping push", "pref_enabled", pushEnabled, "has_token", len(pushTargets) > 0)
+			h.recordSkipped(msg, preferences.ChannelPush, pushEnabled, len(pushTargets) > 0)
+		}
 	}
 
 	// If email should also be sent for new opportunities:
-	if msg.Recipient.Prefs.ReceiveEmail && msg.Recipient.EmailAddress != "" {
-		log.Printf("Attempting to send Email for Volunteer (New Matching Opportunity): %s - Subject: '%s'",
-			msg.Recipient.UserID, subject)
-		// TODO: Call actual Email sending function here
-	} else {
-		log.Printf("Skipping Email for Volunteer %s (Pref: %t, Email: %t). Type: %s",
-			msg.Recipient.UserID, msg.Recipient.Prefs.ReceiveEmail, msg.Recipient.EmailAddress != "", msg.NotificationType)
+	var emailErr error
+	if sendsOn(queue, preferences.ChannelEmail) {
+		emailEnabled := h.channelEnabled(msg, preferences.ChannelEmail)
+		if emailEnabled && msg.Recipient.EmailAddress != "" {
+			slog.InfoContext(ctx, "Sending email to volunteer", "subject", subject)
+			emailErr = h.sendEmail(ctx, msg, subject, body)
+		} else {
+			slog.InfoContext(ctx, "Skipping email", "pref_enabled", emailEnabled, "has_address", msg.Recipient.EmailAddress != "")
+			h.recordSkipped(msg, preferences.ChannelEmail, emailEnabled, msg.Recipient.EmailAddress != "")
+		}
 	}
-	return nil
+	return errors.Join(emailErr, pushErr)
 }
 
 // handleOpportunityUpdate handles notifications for updates to opportunities.
-func (h *NotificationHandler) handleOpportunityUpdate(msg models.NotificationMessage) error {
-	log.Printf("Handling Opportunity Update: Type=%s, OpportunityID=%d, Title=%s",
-		msg.NotificationType, msg.Payload.OpportunityID, msg.Payload.OpportunityTitle)
+func (h *NotificationHandler) handleOpportunityUpdate(ctx context.Context, msg models.NotificationMessage, queue string) error {
+	slog.InfoContext(ctx, "Handling opportunity update",
+		"opportunity_id", msg.Payload.OpportunityID, "opportunity_title", msg.Payload.OpportunityTitle)
 
 	title := msg.Payload.Title
 	body := msg.Payload.Body
@@ -241,32 +381,38 @@ func (h *NotificationHandler) handleOpportunityUpdate(msg models.NotificationMes
 	deepLink := msg.Payload.DeepLink // Declared and assigned
 
 	// --- Email Logic for NGO ---
-	if msg.Recipient.Prefs.ReceiveEmail && msg.Recipient.EmailAddress != "" {
-		log.Printf("Attempting to send Email for Opportunity Update to NGO: %s - Subject: '%s'",
-			msg.Recipient.UserID, subject)
-		// TODO: Call actual Email sending function here
-	} else {
-		log.Printf("Skipping Email for NGO %s (Pref: %t, Email: %t). Type: %s",
-			msg.Recipient.UserID, msg.Recipient.Prefs.ReceiveEmail, msg.Recipient.EmailAddress != "", msg.NotificationType)
+	var emailErr error
+	if sendsOn(queue, preferences.ChannelEmail) {
+		emailEnabled := h.channelEnabled(msg, preferences.ChannelEmail)
+		if emailEnabled && msg.Recipient.EmailAddress != "" {
+			slog.InfoContext(ctx, "Sending email to NGO", "subject", subject)
+			emailErr = h.sendEmail(ctx, msg, subject, body)
+		} else {
+			slog.InfoContext(ctx, "Skipping email", "pref_enabled", emailEnabled, "has_address", msg.Recipient.EmailAddress != "")
+			h.recordSkipped(msg, preferences.ChannelEmail, emailEnabled, msg.Recipient.EmailAddress != "")
+		}
 	}
 	// --- Push Notification Logic for NGO ---
-	if msg.Recipient.Prefs.ReceivePush && msg.Recipient.DeviceToken != "" {
-		// Used deepLink in log.Printf
-		log.Printf("Attempting to send Push for Opportunity Update to NGO: %s - Title: '%s', DeepLink: '%s', Body: '%s'",
-			msg.Recipient.UserID, title, deepLink, body)
-		// TODO: Call actual FCM sending function here
-	} else {
-		log.Printf("Skipping Push for NGO %s (Pref: %t, Token: %t). Type: %s",
-			msg.Recipient.UserID, msg.Recipient.Prefs.ReceivePush, msg.Recipient.DeviceToken != "", msg.NotificationType)
+	var pushErr error
+	if sendsOn(queue, preferences.ChannelPush) {
+		pushTargets := h.pushTargets(ctx, msg)
+		pushEnabled := h.channelEnabled(msg, preferences.ChannelPush)
+		if pushEnabled && len(pushTargets) > 0 {
+			slog.InfoContext(ctx, "Sending push to NGO", "devices", len(pushTargets), "deep_link", deepLink)
+			pushErr = h.sendPush(ctx, msg, pushTargets, title, body, deepLink)
+		} else {
+			slog.InfoContext(ctx, "Skipping push", "pref_enabled", pushEnabled, "has_token", len(pushTargets) > 0)
+			h.recordSkipped(msg, preferences.ChannelPush, pushEnabled, len(pushTargets) > 0)
+		}
 	}
 
-	return nil
+	return errors.Join(emailErr, pushErr)
 }
 
 // handleOppotunityDeleted handles notifications for deleted opportunities.
-func (h *NotificationHandler) handleOppotunityDeleted(msg models.NotificationMessage) error {
-	log.Printf("Handling Opportunity Deleted: Type=%s, OpportunityID=%d, Title=%s",
-		msg.NotificationType, msg.Payload.OpportunityID, msg.Payload.OpportunityTitle)
+func (h *NotificationHandler) handleOppotunityDeleted(ctx context.Context, msg models.NotificationMessage, queue string) error {
+	slog.InfoContext(ctx, "Handling opportunity deletion",
+		"opportunity_id", msg.Payload.OpportunityID, "opportunity_title", msg.Payload.OpportunityTitle)
 
 	title := msg.Payload.Title
 	body := msg.Payload.Body
@@ -274,24 +420,51 @@ func (h *NotificationHandler) handleOppotunityDeleted(msg models.NotificationMes
 	deepLink := msg.Payload.DeepLink // Declared and assigned
 
 	// --- Email Logic for NGO ---
-	if msg.Recipient.Prefs.ReceiveEmail && msg.Recipient.EmailAddress != "" {
-		log.Printf("Attempting to send Email for Opportunity Deletion to Volunteers: %s - Subject: '%s'",
-			msg.Recipient.UserID, subject)
-		// TODO: Call actual Email sending function here
-	} else {
-		log.Printf("Skipping Email for NGO %s (Pref: %t, Email: %t). Type: %s",
-			msg.Recipient.UserID, msg.Recipient.Prefs.ReceiveEmail, msg.Recipient.EmailAddress != "", msg.NotificationType)
+	var emailErr error
+	if sendsOn(queue, preferences.ChannelEmail) {
+		emailEnabled := h.channelEnabled(msg, preferences.ChannelEmail)
+		if emailEnabled && msg.Recipient.EmailAddress != "" {
+			slog.InfoContext(ctx, "Sending email to volunteer", "subject", subject)
+			emailErr = h.sendEmail(ctx, msg, subject, body)
+		} else {
+			slog.InfoContext(ctx, "Skipping email", "pref_enabled", emailEnabled, "has_address", msg.Recipient.EmailAddress != "")
+			h.recordSkipped(msg, preferences.ChannelEmail, emailEnabled, msg.Recipient.EmailAddress != "")
+		}
 	}
 
 	// --- Push Notification Logic for Volunteers ---
-	if msg.Recipient.Prefs.ReceivePush && msg.Recipient.DeviceToken != "" {
-		// Used deepLink in log.Printf
-		log.Printf("Attempting to send Push for Opportunity Deletion to Volunteers: %s - Title: '%s', DeepLink: '%s', Body: '%s'",
-			msg.Recipient.UserID, title, deepLink, body)
-		// TODO: Call actual FCM sending function here
-	} else {
-		log.Printf("Skipping Push for NGO %s (Pref: %t, Token: %t). Type: %s",
-			msg.Recipient.UserID, msg.Recipient.Prefs.ReceivePush, msg.Recipient.DeviceToken != "", msg.NotificationType)
+	var pushErr error
+	if sendsOn(queue, preferences.ChannelPush) {
+		pushTargets := h.pushTargets(ctx, msg)
+		pushEnabled := h.channelEnabled(msg, preferences.ChannelPush)
+		if pushEnabled && len(pushTargets) > 0 {
+			slog.InfoContext(ctx, "Sending push to volunteers", "devices", len(pushTargets), "deep_link", deepLink)
+			pushErr = h.sendPush(ctx, msg, pushTargets, title, body, deepLink)
+		} else {
+			slog.InfoContext(ctx, "Skipping push", "pref_enabled", pushEnabled, "has_token", len(pushTargets) > 0)
+			h.recordSkipped(msg, preferences.ChannelPush, pushEnabled, len(pushTargets) > 0)
+		}
 	}
-	return nil
+	return errors.Join(emailErr, pushErr)
+}
+
+// withMessageFields adds the message's identifying fields to every record logged with ctx.
+func withMessageFields(ctx context.Context, msg models.NotificationMessage) context.Context {
+	return logging.With(ctx,
+		slog.String(logging.KeyNotificationType, msg.NotificationType),
+		slog.String(logging.KeyUserID, msg.Recipient.UserID),
+		slog.String(logging.KeyEventID, msg.EventID))
+}
+
+// channelEnabled reports whether the recipient wants this notification type on the channel,
+// combining stored preferences with the prefs NestJS sent in the message.
+func (h *NotificationHandler) channelEnabled(msg models.NotificationMessage, channel string) bool {
+	messagePref := msg.Recipient.Prefs.ReceiveEmail
+	if channel == preferences.ChannelPush {
+		messagePref = msg.Recipient.Prefs.ReceivePush
+	}
+	if h.Preferences == nil {
+		return messagePref
+	}
+	return h.Preferences.Allowed(msg.Recipient.UserID, msg.NotificationType, channel, messagePref)
 }
@@ -0,0 +1,188 @@
+// handlers/push.go
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"notification-service/constants"
+	"notification-service/dedup"
+	"notification-service/deliverylog"
+	"notification-service/logging"
+	"notification-service/models"
+	"notification-service/preferences"
+	"notification-service/services/push"
+	"notification-service/tokens"
+)
+
+// pushTargets returns the device tokens a push for this message should go to:
+// every active token registered for the recipient, plus the token carried in the
+// message unless the registry already knows it is dead.
+func (h *NotificationHandler) pushTargets(ctx context.Context, msg models.NotificationMessage) []tokens.Token {
+	var targets []tokens.Token
+	seen := make(map[string]bool)
+
+	if h.Tokens != nil {
+		registered, err := h.Tokens.ListByUser(msg.Recipient.UserID)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to load device tokens", "error", err)
+		}
+		for _, t := range registered {
+			seen[t.Token] = true
+			if !t.Disabled {
+				targets = append(targets, t)
+			}
+		}
+	}
+
+	if token := msg.Recipient.DeviceToken; token != "" && !seen[token] {
+		if h.Tokens != nil {
+			if known, err := h.Tokens.Get(token); err == nil && known.Disabled {
+				slog.InfoContext(ctx, "Ignoring disabled device token supplied in message", "reason", known.DisabledReason)
+				return targets
+			}
+		}
+		targets = append(targets, tokens.Token{
+			UserID:       msg.Recipient.UserID,
+			Token:        token,
+			PlatformType: msg.Recipient.PlatformType,
+		})
+	}
+
+	return targets
+}
+
+// sendPush delivers a push to every target token. Tokens the provider reports as dead
+// are disabled and announced, and tokens of a provider that is not configured are
+// skipped; other failures are returned so the message is retried.
+func (h *NotificationHandler) sendPush(ctx context.Context, msg models.NotificationMessage, targets []tokens.Token, title, body, deepLink string) error {
+	if h.PushService == nil {
+		slog.InfoContext(ctx, "Push service not configured; not sending push")
+		return nil
+	}
+
+	// Devices a previous delivery of this event already reached are skipped before
+	// the rate limit, so a retry is not capped or collapsed by its own earlier send.
+	var pending []tokens.Token
+	for _, target := range targets {
+		if h.alreadyDone(msg, dedup.PushScope(target.Token)) {
+			slog.InfoContext(ctx, "Push already sent to this device for event; skipping", "device_token", target.Token)
+			continue
+		}
+		pending = append(pending, target)
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	reservation := h.reserve(msg, preferences.ChannelPush)
+	if reservation == nil {
+		return nil
+	}
+
+	var firstErr error
+	sent := false
+	for _, target := range pending {
+		scope := dedup.PushScope(target.Token)
+		sendCtx, span := startChannelSpan(ctx, preferences.ChannelPush, h.providerName(target))
+		sendCtx, cancel := context.WithTimeout(sendCtx, 15*time.Second)
+		start := time.Now()
+		messageID, err := h.PushService.Send(sendCtx, target.Provider, push.Notification{
+			Token:    target.Token,
+			Title:    title,
+			Body:     body,
+			DeepLink: deepLink,
+			Data:     map[string]string{"notification_type": msg.NotificationType},
+		})
+		cancel()
+		endChannelSpan(span, err)
+		if errors.Is(err, push.ErrProviderNotConfigured) {
+			// Retrying would resend the other channels and devices without ever reaching this one.
+			slog.WarnContext(ctx, "Skipping push to device of unconfigured provider", logging.KeyProvider, h.providerName(target))
+			h.recordAttempt(msg, deliverylog.Attempt{
+				Channel:  preferences.ChannelPush,
+				Provider: h.providerName(target),
+				Status:   deliverylog.StatusSkipped,
+				Reason:   deliverylog.ReasonNoProvider,
+			})
+			continue
+		}
+		if !errors.Is(err, push.ErrInvalidToken) {
+			h.recordProviderResult(h.providerName(target), err)
+		}
+
+		attempt := deliverylog.Attempt{
+			Channel:           preferences.ChannelPush,
+			Provider:          h.providerName(target),
+			Status:            deliverylog.StatusSent,
+			ProviderMessageID: messageID,
+			Latency:           time.Since(start),
+		}
+		if err != nil {
+			attempt.Status = deliverylog.StatusFailed
+			attempt.Error = err.Error()
+		}
+		h.recordAttempt(msg, attempt)
+
+		if err == nil {
+			slog.InfoContext(ctx, "Push sent", logging.KeyProvider, attempt.Provider, "provider_message_id", messageID)
+			h.markDone(msg, scope)
+			sent = true
+			continue
+		}
+
+		var invalid *push.InvalidTokenError
+		if errors.As(err, &invalid) {
+			h.invalidateToken(ctx, target, invalid)
+			h.markDone(msg, scope)
+			continue
+		}
+
+		slog.ErrorContext(ctx, "Error sending push notification", logging.KeyProvider, attempt.Provider, "error", err)
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	if sent {
+		reservation.Commit()
+	} else {
+		reservation.Cancel()
+	}
+	return firstErr
+}
+
+// invalidateToken disables a dead token and tells NestJS about it.
+func (h *NotificationHandler) invalidateToken(ctx context.Context, target tokens.Token, invalid *push.InvalidTokenError) {
+	slog.WarnContext(ctx, "Device token rejected; disabling",
+		logging.KeyProvider, invalid.Provider, "reason", invalid.Reason, "device_token", target.Token)
+
+	if h.Tokens != nil {
+		if _, err := h.Tokens.Disable(target.UserID, target.Token, invalid.Provider, invalid.Reason); err != nil {
+			slog.ErrorContext(ctx, "Failed to disable device token", "error", err)
+		}
+	}
+
+	if h.Events == nil {
+		return
+	}
+	event := models.DeviceTokenInvalidatedEvent{
+		UserID:      target.UserID,
+		DeviceToken: target.Token,
+		Provider:    invalid.Provider,
+		Reason:      invalid.Reason,
+		Timestamp:   time.Now().Unix(),
+	}
+	if err := h.Events.Publish(constants.RoutingKeyDeviceTokenInvalidated, event); err != nil {
+		slog.ErrorContext(ctx, "Failed to publish token invalidation", "error", err)
+	}
+}
+
+func (h *NotificationHandler) providerName(target tokens.Token) string {
+	if target.Provider != "" {
+		return target.Provider
+	}
+	return h.PushService.DefaultProvider()
+}
@@ -0,0 +1,32 @@
+// handlers/queues.go
+package handlers
+
+import (
+	"notification-service/constants"
+	"notification-service/dedup"
+	"notification-service/preferences"
+)
+
+// queueChannels names the one channel a message consumed from a queue is sent on, for
+// the queues bound with the same routing key as a queue for another channel. Each sends
+// its own channel, so pausing one stops just that channel. Messages from any other queue,
+// or submitted directly, are sent on every channel.
+var queueChannels = map[string]string{
+	constants.NgoEmailQueue: preferences.ChannelEmail,
+	constants.NgoPushQueue:  preferences.ChannelPush,
+}
+
+// sendsOn reports whether a message from queue is sent on channel.
+func sendsOn(queue, channel string) bool {
+	only, ok := queueChannels[queue]
+	return !ok || only == channel
+}
+
+// messageScope is the dedup scope that marks a message from queue as processed. The
+// single-channel queues receive the same events, so each gets a scope of its own.
+func messageScope(queue string) string {
+	if channel, ok := queueChannels[queue]; ok {
+		return dedup.ScopeMessage + ":" + channel
+	}
+	return dedup.ScopeMessage
+}
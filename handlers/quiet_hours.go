@@ -0,0 +1,79 @@
+// handlers/quiet_hours.go
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"notification-service/deliverylog"
+	"notification-service/models"
+	"notification-service/rabbitmq"
+	"notification-service/scheduler"
+	"notification-service/tracing"
+)
+
+// ProcessScheduled delivers a notification that was held back by quiet hours, on the
+// channels of the queue it was consumed from. It is the Scheduler's dispatch function
+// and skips the quiet hours check.
+func (h *NotificationHandler) ProcessScheduled(msg models.NotificationMessage, queue string) error {
+	receivedAt := time.Now()
+	ctx, span := tracing.Tracer().Start(context.Background(), "scheduled process")
+	defer span.End()
+	ctx = withMessageFields(ctx, msg)
+	d := rabbitmq.Delivery{Queue: queue}
+	if err := h.dispatch(ctx, msg, queue); err != nil {
+		h.recordMessage(msg, d, deliverylog.MessageFailed, err, receivedAt)
+		return err
+	}
+	h.markDone(msg, messageScope(queue))
+	h.recordMessage(msg, d, deliverylog.MessageProcessed, nil, receivedAt)
+	return nil
+}
+
+// deferForQuietHours schedules the message for later if the recipient is in quiet hours
+// and the notification type is not urgent. It reports whether the message was deferred.
+func (h *NotificationHandler) deferForQuietHours(ctx context.Context, msg models.NotificationMessage, queue string) (bool, error) {
+	if h.Scheduler == nil {
+		return false, nil
+	}
+	if h.QuietHoursPolicy != nil && h.QuietHoursPolicy.BypassesQuietHours(msg.NotificationType) {
+		return false, nil
+	}
+
+	qh, timezone, ok := h.quietHoursFor(msg)
+	if !ok {
+		return false, nil
+	}
+
+	until, quiet, err := scheduler.QuietUntil(time.Now(), qh, timezone)
+	if err != nil {
+		// A bad window from NestJS should not block delivery.
+		slog.WarnContext(ctx, "Ignoring invalid quiet hours", "error", err)
+		return false, nil
+	}
+	if !quiet {
+		return false, nil
+	}
+
+	slog.InfoContext(ctx, "Recipient is in quiet hours; deferring",
+		"quiet_start", qh.Start, "quiet_end", qh.End, "timezone", timezone, "deliver_at", until)
+	if _, err := h.Scheduler.Schedule(msg, queue, until); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// quietHoursFor returns the recipient's quiet hours, preferring stored preferences
+// over the values carried in the message.
+func (h *NotificationHandler) quietHoursFor(msg models.NotificationMessage) (models.QuietHours, string, bool) {
+	if h.Preferences != nil {
+		if qh, timezone, ok := h.Preferences.QuietHours(msg.Recipient.UserID); ok {
+			return qh, timezone, true
+		}
+	}
+	if msg.Recipient.QuietHours != nil {
+		return *msg.Recipient.QuietHours, msg.Recipient.Timezone, true
+	}
+	return models.QuietHours{}, "", false
+}
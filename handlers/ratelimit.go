@@ -0,0 +1,27 @@
+// handlers/ratelimit.go
+package handlers
+
+import (
+	"notification-service/deliverylog"
+	"notification-service/models"
+	"notification-service/ratelimit"
+)
+
+// reserve takes rate limit capacity for sending msg on channel. It returns nil, after
+// logging the suppression, when the message is capped or collapsed. The caller must
+// Commit the reservation after a successful send and Cancel it otherwise.
+func (h *NotificationHandler) reserve(msg models.NotificationMessage, channel string) *ratelimit.Reservation {
+	if h.RateLimiter == nil {
+		return &ratelimit.Reservation{}
+	}
+	reservation, allowed, reason := h.RateLimiter.Reserve(msg, channel)
+	if !allowed {
+		h.recordAttempt(msg, deliverylog.Attempt{
+			Channel: channel,
+			Status:  deliverylog.StatusSuppressed,
+			Reason:  reason,
+		})
+		return nil
+	}
+	return reservation
+}
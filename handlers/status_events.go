@@ -0,0 +1,65 @@
+// handlers/status_events.go
+package handlers
+
+import (
+	"log/slog"
+
+	"notification-service/constants"
+	"notification-service/deliverylog"
+	"notification-service/logging"
+	"notification-service/models"
+)
+
+// Status values carried by NotificationStatusEvent.
+const (
+	eventStatusDelivered = "delivered"
+	eventStatusFailed    = "failed"
+	eventStatusSkipped   = "skipped"
+)
+
+// publishStatus tells NestJS how a channel attempt ended: notification.delivered,
+// notification.failed, or notification.skipped with the reason (opted out, no
+// address/token, rate limited, held for a digest).
+func (h *NotificationHandler) publishStatus(a deliverylog.Attempt) {
+	if h.Events == nil {
+		return
+	}
+
+	event := models.NotificationStatusEvent{
+		EventID:           a.EventID,
+		NotificationType:  a.NotificationType,
+		UserID:            a.UserID,
+		Channel:           a.Channel,
+		Reason:            a.Reason,
+		Error:             a.Error,
+		Provider:          a.Provider,
+		ProviderMessageID: a.ProviderMessageID,
+		ApplicationID:     a.ApplicationID,
+		OpportunityID:     a.OpportunityID,
+		Timestamp:         a.CreatedAt.Unix(),
+	}
+
+	var routingKey string
+	switch a.Status {
+	case deliverylog.StatusSent:
+		event.Status = eventStatusDelivered
+		routingKey = constants.RoutingKeyNotificationDelivered
+	case deliverylog.StatusFailed:
+		event.Status = eventStatusFailed
+		routingKey = constants.RoutingKeyNotificationFailed
+	default:
+		event.Status = eventStatusSkipped
+		routingKey = constants.RoutingKeyNotificationSkipped
+		if event.Reason == "" {
+			event.Reason = a.Status
+		} else if a.Status != deliverylog.StatusSkipped {
+			// Keep suppressed/digested distinguishable from a plain skip.
+			event.Reason = a.Status + ": " + event.Reason
+		}
+	}
+
+	if err := h.Events.Publish(routingKey, event); err != nil {
+		slog.Error("Failed to publish status event",
+			"status", event.Status, logging.KeyEventID, a.EventID, logging.KeyChannel, a.Channel, "error", err)
+	}
+}
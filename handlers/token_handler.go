@@ -0,0 +1,85 @@
+// handlers/token_handler.go
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"notification-service/constants"
+	"notification-service/logging"
+	"notification-service/models"
+	"notification-service/rabbitmq"
+	"notification-service/tokens"
+)
+
+// TokenHandler handles device token register/unregister messages from NestJS
+type TokenHandler struct {
+	Tokens tokens.Store
+}
+
+// NewTokenHandler creates a new device token handler
+func NewTokenHandler(store tokens.Store) *TokenHandler {
+	return &TokenHandler{
+		Tokens: store,
+	}
+}
+
+// ProcessMessage processes a device token message, plain or carried by a CloudEvent,
+// by unmarshaling it and applying it to the token registry.
+func (h *TokenHandler) ProcessMessage(ctx context.Context, d rabbitmq.Delivery) error {
+	var msg models.DeviceTokenMessage
+
+	body, eventType, err := deviceTokenBody(d)
+	if err == nil {
+		err = json.Unmarshal(body, &msg)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to unmarshal device token message", "error", err, "body_bytes", len(d.Body))
+		return fmt.Errorf("%w: %v", rabbitmq.ErrDeadLetter, err)
+	}
+	if msg.EventType == "" {
+		msg.EventType = eventType
+	}
+
+	if msg.UserID == "" || msg.DeviceToken == "" {
+		// Re-queueing can never fix a message without a user or token, so drop it.
+		slog.WarnContext(ctx, "Ignoring device token message without user_id or device_token", "event_type", msg.EventType)
+		return nil
+	}
+
+	switch msg.EventType {
+	case constants.DeviceTokenEventRegister:
+		if !tokens.ValidProvider(msg.Provider) {
+			// Pushes to a token of an unknown provider could never be sent.
+			slog.WarnContext(ctx, "Ignoring device token registration for unknown provider",
+				logging.KeyUserID, msg.UserID, logging.KeyProvider, msg.Provider)
+			return nil
+		}
+		_, err := h.Tokens.Register(tokens.Token{
+			UserID:       msg.UserID,
+			Token:        msg.DeviceToken,
+			Provider:     msg.Provider,
+			PlatformType: msg.PlatformType,
+		})
+		if err != nil {
+			return err
+		}
+		slog.InfoContext(ctx, "Registered device token",
+			logging.KeyUserID, msg.UserID, logging.KeyProvider, msg.Provider, "platform", msg.PlatformType)
+
+	case constants.DeviceTokenEventUnregister:
+		err := h.Tokens.Unregister(msg.UserID, msg.DeviceToken)
+		if err != nil && !errors.Is(err, tokens.ErrNotFound) {
+			return err
+		}
+		slog.InfoContext(ctx, "Unregistered device token", logging.KeyUserID, msg.UserID)
+
+	default:
+		slog.WarnContext(ctx, "Unknown device token event type received", "event_type", msg.EventType, logging.KeyUserID, msg.UserID)
+	}
+
+	return nil
+}
@@ -0,0 +1,31 @@
+// handlers/tracing.go
+package handlers
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"notification-service/tracing"
+)
+
+// startChannelSpan starts the span for one delivery on a channel.
+func startChannelSpan(ctx context.Context, channel, provider string) (context.Context, trace.Span) {
+	return tracing.Tracer().Start(ctx, "send "+channel,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("notification.channel", channel),
+			attribute.String("notification.provider", provider),
+		))
+}
+
+// endChannelSpan ends a channel span, marking it failed if err is set.
+func endChannelSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
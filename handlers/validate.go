@@ -0,0 +1,60 @@
+// handlers/validate.go
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"notification-service/cloudevents"
+	"notification-service/models"
+	"notification-service/rabbitmq"
+	"notification-service/schema"
+)
+
+// ValidateNotification checks a message submitted directly over gRPC before it is
+// processed or enqueued, against the JSON Schema for its type. A schema mismatch is
+// returned as a *schema.ValidationError listing every invalid field.
+//
+// Unlike HTTP and queue bodies, gRPC input is already typed: protobuf rejects fields
+// of the wrong type and drops unknown ones while unmarshalling, so the decoded message
+// is all there is to check.
+func ValidateNotification(msg models.NotificationMessage) error {
+	return schema.Validate(msg)
+}
+
+// ValidateNotificationBody checks the body of a message submitted over HTTP like
+// ValidateNotification, before it is decoded, so fields of the wrong type and unknown
+// fields are reported too.
+func ValidateNotificationBody(body []byte) error {
+	return schema.ValidateBody(body, "")
+}
+
+// validateMessage checks the body of a consumed message against its schema, before it
+// is decoded; e is the CloudEvent carrying it, if any. In strict mode an invalid message
+// is returned as a permanent error carrying the validation report for the dead letter
+// queue; otherwise it is only logged.
+func (h *NotificationHandler) validateMessage(ctx context.Context, body []byte, e *cloudevents.Event) error {
+	if h.ValidationMode == "" || h.ValidationMode == schema.ModeOff {
+		return nil
+	}
+	eventType := ""
+	if e != nil {
+		eventType = e.Type
+	}
+	err := schema.ValidateBody(body, eventType)
+	if err == nil {
+		return nil
+	}
+	var verr *schema.ValidationError
+	if !errors.As(err, &verr) {
+		return err
+	}
+
+	if h.ValidationMode == schema.ModeStrict {
+		slog.ErrorContext(ctx, "Message does not match its schema", "validation_errors", verr.Fields)
+		return rabbitmq.DeadLetter(verr, verr.Headers())
+	}
+	slog.WarnContext(ctx, "Message does not match its schema; processing anyway", "validation_errors", verr.Fields)
+	return nil
+}
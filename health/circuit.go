@@ -0,0 +1,152 @@
+// health/circuit.go
+package health
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Circuit states.
+const (
+	CircuitClosed = "closed"
+	CircuitOpen   = "open"
+)
+
+// DefaultFailureThreshold is the number of consecutive failed sends that opens a circuit.
+const DefaultFailureThreshold = 5
+
+// Circuit follows the recent send results of one delivery provider. It opens after
+// threshold consecutive failures and closes again on the next success. It only
+// informs readiness; sends are never blocked.
+type Circuit struct {
+	threshold int
+	probe     func(ctx context.Context) error
+
+	mu          sync.Mutex
+	failures    int
+	lastError   string
+	lastFailure time.Time
+}
+
+// CircuitStatus is the detail reported for a circuit by its readiness check.
+type CircuitStatus struct {
+	State               string     `json:"state"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	LastFailure         *time.Time `json:"last_failure,omitempty"`
+	LastError           string     `json:"last_error,omitempty"`
+	Reachable           *bool      `json:"reachable,omitempty"` // Only probed while the circuit is open
+}
+
+// Record counts the result of a send.
+func (c *Circuit) Record(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err == nil {
+		c.failures = 0
+		return
+	}
+	c.failures++
+	c.lastError = err.Error()
+	c.lastFailure = time.Now()
+}
+
+// Status returns the circuit state without probing.
+func (c *Circuit) Status() CircuitStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	status := CircuitStatus{
+		State:               CircuitClosed,
+		ConsecutiveFailures: c.failures,
+		LastError:           c.lastError,
+	}
+	if !c.lastFailure.IsZero() {
+		lastFailure := c.lastFailure
+		status.LastFailure = &lastFailure
+	}
+	if c.failures >= c.threshold {
+		status.State = CircuitOpen
+	}
+	return status
+}
+
+// Check is ok while the circuit is closed. Once it is open the provider is probed:
+// reachable is degraded (sends are failing for some other reason), unreachable is down.
+func (c *Circuit) Check(ctx context.Context) Result {
+	status := c.Status()
+	if status.State == CircuitClosed {
+		return Result{Status: StatusOK, Detail: status}
+	}
+	if c.probe == nil {
+		return Result{Status: StatusDown, Detail: status, Error: status.LastError}
+	}
+
+	err := c.probe(ctx)
+	reachable := err == nil
+	status.Reachable = &reachable
+	if err != nil {
+		return Result{Status: StatusDown, Detail: status, Error: err.Error()}
+	}
+	return Result{Status: StatusDegraded, Detail: status, Error: status.LastError}
+}
+
+// Circuits holds one circuit per provider.
+type Circuits struct {
+	threshold int
+
+	mu       sync.Mutex
+	circuits map[string]*Circuit
+}
+
+// NewCircuits creates an empty set of circuits that open after threshold consecutive failures.
+func NewCircuits(threshold int) *Circuits {
+	if threshold <= 0 {
+		threshold = DefaultFailureThreshold
+	}
+	return &Circuits{threshold: threshold, circuits: make(map[string]*Circuit)}
+}
+
+// Add registers a provider with the probe used to check it while its circuit is open.
+func (cs *Circuits) Add(provider string, probe func(ctx context.Context) error) *Circuit {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	c := &Circuit{threshold: cs.threshold, probe: probe}
+	cs.circuits[provider] = c
+	return c
+}
+
+// Record counts a send result for the provider. Unregistered providers are ignored.
+func (cs *Circuits) Record(provider string, err error) {
+	cs.mu.Lock()
+	c, ok := cs.circuits[provider]
+	cs.mu.Unlock()
+	if ok {
+		c.Record(err)
+	}
+}
+
+// Providers returns the registered provider names.
+func (cs *Circuits) Providers() []string {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	names := make([]string, 0, len(cs.circuits))
+	for name := range cs.circuits {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Check returns the readiness check for the provider's circuit.
+func (cs *Circuits) Check(provider string) Check {
+	return func(ctx context.Context) Result {
+		cs.mu.Lock()
+		c, ok := cs.circuits[provider]
+		cs.mu.Unlock()
+		if !ok {
+			return Result{Status: StatusOK}
+		}
+		return c.Check(ctx)
+	}
+}
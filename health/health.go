@@ -0,0 +1,93 @@
+// health/health.go
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Check statuses. A degraded dependency still counts as ready.
+const (
+	StatusOK       = "ok"
+	StatusDegraded = "degraded"
+	StatusDown     = "down"
+)
+
+// checkTimeout bounds each readiness check, so one slow dependency cannot stall the probe.
+const checkTimeout = 2 * time.Second
+
+// Result is the outcome of checking one dependency.
+type Result struct {
+	Status string      `json:"status"`
+	Detail interface{} `json:"detail,omitempty"`
+	Error  string      `json:"error,omitempty"`
+}
+
+// Check reports the state of one dependency.
+type Check func(ctx context.Context) Result
+
+// Report is the outcome of all readiness checks.
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+// Ready reports whether no dependency is down.
+func (r Report) Ready() bool {
+	return r.Status != StatusDown
+}
+
+// Checker runs the registered readiness checks.
+type Checker struct {
+	mu     sync.Mutex
+	checks map[string]Check
+}
+
+// NewChecker creates a checker with no checks.
+func NewChecker() *Checker {
+	return &Checker{checks: make(map[string]Check)}
+}
+
+// Add registers a check under name, replacing any check with the same name.
+func (c *Checker) Add(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks[name] = check
+}
+
+// Run runs every check concurrently. The report is down if any check is down,
+// degraded if any is degraded, and ok otherwise.
+func (c *Checker) Run(ctx context.Context) Report {
+	c.mu.Lock()
+	checks := make(map[string]Check, len(c.checks))
+	for name, check := range c.checks {
+		checks[name] = check
+	}
+	c.mu.Unlock()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]Result, len(checks))}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, checkTimeout)
+			defer cancel()
+			result := check(checkCtx)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[name] = result
+			switch {
+			case result.Status == StatusDown:
+				report.Status = StatusDown
+			case result.Status == StatusDegraded && report.Status == StatusOK:
+				report.Status = StatusDegraded
+			}
+		}()
+	}
+	wg.Wait()
+	return report
+}
@@ -0,0 +1,38 @@
+// logging/context.go
+package logging
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Field names shared by every log record that concerns a message.
+const (
+	KeyNotificationType = "notification_type"
+	KeyUserID           = "user_id"
+	KeyEventID          = "event_id"
+	KeyQueue            = "queue"
+	KeyRoutingKey       = "routing_key"
+	KeyDeliveryTag      = "delivery_tag"
+	KeyChannel          = "channel"
+	KeyProvider         = "provider"
+	KeyTraceID          = "trace_id"
+	KeySpanID           = "span_id"
+)
+
+type contextKey struct{}
+
+// With returns ctx carrying attrs, which are added to every record logged with it.
+func With(ctx context.Context, attrs ...slog.Attr) context.Context {
+	existing := fromContext(ctx)
+	merged := make([]slog.Attr, 0, len(existing)+len(attrs))
+	merged = append(merged, existing...)
+	merged = append(merged, attrs...)
+	return context.WithValue(ctx, contextKey{}, merged)
+}
+
+// fromContext returns the attributes added with With.
+func fromContext(ctx context.Context) []slog.Attr {
+	attrs, _ := ctx.Value(contextKey{}).([]slog.Attr)
+	return attrs
+}
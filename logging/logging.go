@@ -0,0 +1,96 @@
+// logging/logging.go
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Output formats accepted by Setup.
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// Setup installs the default slog logger: JSON (or text) records at the given level,
+// with PII masked and the fields carried in the context added to every record.
+// The standard log package is routed through the same handler, so log.Printf output
+// is structured and redacted too.
+func Setup(w io.Writer, level, format string) error {
+	var lvl slog.Level
+	if level != "" {
+		if err := lvl.UnmarshalText([]byte(level)); err != nil {
+			return fmt.Errorf("invalid log level %q: %w", level, err)
+		}
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl}
+	var base slog.Handler
+	switch strings.ToLower(format) {
+	case "", FormatJSON:
+		base = slog.NewJSONHandler(w, opts)
+	case FormatText:
+		base = slog.NewTextHandler(w, opts)
+	default:
+		return fmt.Errorf("unknown log format %q (want %s or %s)", format, FormatJSON, FormatText)
+	}
+
+	slog.SetDefault(slog.New(NewHandler(base)))
+	log.SetFlags(0)
+	return nil
+}
+
+// Handler masks PII in messages and attributes and adds the context's fields
+// (see With) and trace IDs before passing records on.
+type Handler struct {
+	next slog.Handler
+}
+
+// NewHandler wraps next with redaction and context fields.
+func NewHandler(next slog.Handler) *Handler {
+	return &Handler{next: next}
+}
+
+// Enabled reports whether next handles records at level.
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle redacts the record, adds context fields and passes it on.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	out := slog.NewRecord(r.Time, r.Level, RedactString(r.Message), r.PC)
+
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		out.AddAttrs(
+			slog.String(KeyTraceID, span.TraceID().String()),
+			slog.String(KeySpanID, span.SpanID().String()))
+	}
+	for _, a := range fromContext(ctx) {
+		out.AddAttrs(redactAttr(a))
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		out.AddAttrs(redactAttr(a))
+		return true
+	})
+	return h.next.Handle(ctx, out)
+}
+
+// WithAttrs returns a handler whose records carry the redacted attributes.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redacted[i] = redactAttr(a)
+	}
+	return &Handler{next: h.next.WithAttrs(redacted)}
+}
+
+// WithGroup returns a handler that nests later attributes under name.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return &Handler{next: h.next.WithGroup(name)}
+}
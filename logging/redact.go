@@ -0,0 +1,112 @@
+// logging/redact.go
+package logging
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9\-]+(?:\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,}`)
+	// Only international (+...) numbers are recognised in free text; bare digit runs
+	// are too easily confused with IDs and dates. Attributes named like phones are always masked.
+	phonePattern = regexp.MustCompile(`\+\d[\d\s().\-]{6,}\d`)
+	// FCM registration tokens and APNs device tokens are long opaque strings.
+	tokenPattern = regexp.MustCompile(`[A-Za-z0-9_:\-]{64,}`)
+)
+
+// Attribute keys (or key fragments) whose values are always masked.
+var (
+	secretKeys = []string{"password", "secret", "authorization", "api_key", "apikey"}
+	tokenKeys  = []string{"token"}
+	emailKeys  = []string{"email"}
+	phoneKeys  = []string{"phone"}
+)
+
+const redacted = "[REDACTED]"
+
+// RedactString masks email addresses, international phone numbers and device tokens in s.
+func RedactString(s string) string {
+	s = tokenPattern.ReplaceAllStringFunc(s, MaskToken)
+	s = emailPattern.ReplaceAllStringFunc(s, MaskEmail)
+	return phonePattern.ReplaceAllStringFunc(s, MaskPhone)
+}
+
+// MaskEmail keeps the first character of the local part and the domain: j***@example.com.
+func MaskEmail(address string) string {
+	local, domain, ok := strings.Cut(address, "@")
+	if !ok || local == "" {
+		return redacted
+	}
+	return local[:1] + "***@" + domain
+}
+
+// MaskToken keeps the last four characters, enough to tell tokens apart: ***a1b2.
+func MaskToken(token string) string {
+	if len(token) <= 8 {
+		return "***"
+	}
+	return "***" + token[len(token)-4:]
+}
+
+// MaskPhone keeps the last two digits: ***42.
+func MaskPhone(phone string) string {
+	digits := make([]byte, 0, len(phone))
+	for i := 0; i < len(phone); i++ {
+		if phone[i] >= '0' && phone[i] <= '9' {
+			digits = append(digits, phone[i])
+		}
+	}
+	if len(digits) <= 4 {
+		return "***"
+	}
+	return "***" + string(digits[len(digits)-2:])
+}
+
+// redactAttr masks an attribute by key, or by content for other string values.
+func redactAttr(a slog.Attr) slog.Attr {
+	a.Value = a.Value.Resolve()
+
+	if a.Value.Kind() == slog.KindGroup {
+		group := a.Value.Group()
+		masked := make([]slog.Attr, len(group))
+		for i, member := range group {
+			masked[i] = redactAttr(member)
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(masked...)}
+	}
+
+	key := strings.ToLower(a.Key)
+	switch {
+	case keyMatches(key, secretKeys):
+		return slog.String(a.Key, redacted)
+	case a.Value.Kind() != slog.KindString && a.Value.Kind() != slog.KindAny:
+		return a
+	case keyMatches(key, emailKeys):
+		return slog.String(a.Key, emailPattern.ReplaceAllStringFunc(a.Value.String(), MaskEmail))
+	case keyMatches(key, tokenKeys):
+		return slog.String(a.Key, MaskToken(a.Value.String()))
+	case keyMatches(key, phoneKeys):
+		return slog.String(a.Key, MaskPhone(a.Value.String()))
+	}
+
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, RedactString(a.Value.String()))
+	case slog.KindAny:
+		// Structs and errors are flattened so nested addresses cannot slip through.
+		return slog.String(a.Key, RedactString(fmt.Sprintf("%+v", a.Value.Any())))
+	}
+	return a
+}
+
+func keyMatches(key string, fragments []string) bool {
+	for _, fragment := range fragments {
+		if strings.Contains(key, fragment) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+// logging/redact_test.go
+package logging
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestRedactString(t *testing.T) {
+	token := strings.Repeat("a", 60) + "WXYZ1234"
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"empty", "", ""},
+		{"email", "sent to jane.doe@example.com", "sent to j***@example.com"},
+		{"several emails", "a@b.io and c@d.org", "a***@b.io and c***@d.org"},
+		{"international phone", "call +1 (415) 555-0142 now", "call ***42 now"},
+		{"device token", "token " + token + " rejected", "token ***1234 rejected"},
+		{"ids and dates untouched", "order 12345678 on 2024-05-01", "order 12345678 on 2024-05-01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RedactString(tt.in); got != tt.want {
+				t.Errorf("RedactString(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactAttr(t *testing.T) {
+	tests := []struct {
+		name string
+		attr slog.Attr
+		want string
+	}{
+		{"secret key", slog.String("password", "hunter2"), redacted},
+		{"secret key with non-string value", slog.Int("api_key", 5), redacted},
+		{"email key", slog.String("email_address", "jane@example.com"), "j***@example.com"},
+		{"token key", slog.String("device_token", "abcdefghijkl"), "***ijkl"},
+		{"short token", slog.String("device_token", "abc"), "***"},
+		{"phone key without international prefix", slog.String("phone_number", "0412 345 678"), "***78"},
+		{"non-string value kept", slog.Int("token_count", 3), "3"},
+		{"plain key kept", slog.String("user_id", "u1"), "u1"},
+		{"email in free text", slog.String("note", "contact jane@example.com"), "contact j***@example.com"},
+		{"email in error", slog.Any("error", errors.New("smtp: rejected jane@example.com")), "smtp: rejected j***@example.com"},
+		{"group members", slog.Group("recipient", slog.String("email", "jane@example.com"), slog.String("user_id", "u1")),
+			"[email=j***@example.com user_id=u1]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := redactAttr(tt.attr)
+			if got.Key != tt.attr.Key || got.Value.String() != tt.want {
+				t.Errorf("redactAttr(%v) = %v, want %s=%s", tt.attr, got, tt.attr.Key, tt.want)
+			}
+		})
+	}
+}
@@ -1,24 +1,55 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
+	"notification-service/api"
+	"notification-service/bounces"
+	"notification-service/cloudevents"
+	"notification-service/constants"
+	"notification-service/dedup"
+	"notification-service/deliverylog"
+	"notification-service/digest"
+	"notification-service/dlq"
+	"notification-service/grpcapi"
 	"notification-service/handlers"
+	"notification-service/health"
+	"notification-service/logging"
+	"notification-service/preferences"
 	"notification-service/rabbitmq"
-	// "notification-service/services/email"
-	// "notification-service/services/fcm"
+	"notification-service/ratelimit"
+	"notification-service/scheduler"
+	"notification-service/schema"
+	"notification-service/services/email"
+	"notification-service/services/push"
+	"notification-service/sqlstore"
+	"notification-service/tokens"
+	"notification-service/tracing"
+	"notification-service/tracking"
+	"notification-service/unsubscribe"
 )
 
 func main() {
+	// Logging: JSON records with emails, device tokens and phone numbers masked; log.Printf goes through the same handler
+	handleErrorMessage(logging.Setup(os.Stderr, getEnv("LOG_LEVEL", "info"), getEnv("LOG_FORMAT", logging.FormatJSON)),
+		"Failed to configure logging")
 	log.Println("Starting Go Notification Microservice...")
 
+	// Tracing: spans continue the traceparent NestJS puts in AMQP headers; TRACE_EXPORTER=otlp|stdout exports them
+	shutdownTracing, err := tracing.Setup(context.Background(),
+		getEnv("TRACE_EXPORTER", tracing.ExporterNone), getEnv("OTEL_SERVICE_NAME", constants.ServiceName))
+	handleErrorMessage(err, "Failed to configure tracing")
+
 	// Create RabbitMQ connection
 	// Use constant from the new package
-	conn, err := rabbitmq.NewConnection(DefaultRabbitMQURL)
+	conn, err := rabbitmq.NewConnection(constants.DefaultRabbitMQURL)
 	if err != nil {
 		log.Fatalf("Failed to connect to RabbitMQ: %s", err)
 	}
@@ -26,115 +57,418 @@ func main() {
 
 	// Declare exchange
 	// Use constant from the new package
-	err = conn.DeclareExchange(ExchangeName, "topic")
+	err = conn.DeclareExchange(constants.ExchangeName, "topic")
 	if err != nil {
 		log.Fatalf("Failed to declare exchange: %s", err)
 	}
 
 	// Declare and bind queues
 	setupQueues(conn) // This function will also use
-	// // Initialize Email and FCM Services
-	// emailService := email.NewService()
-	// fcmService := fcm.NewService()
+	// Initialize Email Service
+	emailService := newEmailService()
+
+	// State store: tokens and other state that must survive restarts, in SQLite or Postgres
+	stateDB, err := sqlstore.Open(
+		getEnv("STORE_DRIVER", sqlstore.DriverSQLite),
+		getEnv("STORE_DSN", constants.DefaultStoreDSN))
+	if err != nil {
+		log.Fatalf("Failed to open state store: %s", err)
+	}
+	defer stateDB.Close()
+
+	// Device token registry shared by the handler, the AMQP token consumer and the HTTP API
+	tokenStore, err := tokens.NewSQLStore(stateDB)
+	handleErrorMessage(err, "Failed to open device token store")
+
+	// Recipient preferences; PREFERENCES_MODE decides how message-supplied prefs combine with stored ones
+	preferenceStore, err := preferences.NewSQLStore(stateDB)
+	handleErrorMessage(err, "Failed to open preference store")
+	preferenceResolver, err := preferences.NewResolver(preferenceStore, os.Getenv("PREFERENCES_MODE"))
+	if err != nil {
+		log.Fatalf("Failed to configure preferences: %s", err)
+	}
 
 	// // Create notification handler, passing the initialized services
 	notificationHandler := handlers.NewNotificationHandler()
+	notificationHandler.EmailService = emailService
+	pushService := newPushService()
+	notificationHandler.PushService = pushService
+	notificationHandler.Tokens = tokenStore
+	// Status and token events are published as CloudEvents, in ce- headers unless CLOUDEVENTS_MODE=structured
+	cloudEventsMode := getEnv("CLOUDEVENTS_MODE", cloudevents.ModeBinary)
+	if !cloudevents.ValidMode(cloudEventsMode) {
+		log.Fatalf("Invalid CLOUDEVENTS_MODE '%s': must be binary or structured", cloudEventsMode)
+	}
+	eventPublisher, err := rabbitmq.NewEventPublisher(conn, constants.ExchangeName, cloudEventsMode)
+	handleErrorMessage(err, "Failed to open event publisher")
+	defer eventPublisher.Close()
+	notificationHandler.Events = eventPublisher
+	notificationHandler.Preferences = preferenceResolver
+
+	// Quiet hours: non-urgent notifications are held in the scheduler until the window ends
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	scheduledJobs, err := scheduler.NewSQLStore(stateDB)
+	handleErrorMessage(err, "Failed to open scheduled job store")
+	notificationHandler.Scheduler = scheduler.NewScheduler(scheduledJobs, 30*time.Second, notificationHandler.ProcessScheduled)
+	notificationHandler.QuietHoursPolicy = scheduler.NewPolicy(
+		strings.Split(getEnv("URGENT_NOTIFICATION_TYPES", constants.NotificationTypeOpportunityDeleted), ",")...)
+	go notificationHandler.Scheduler.Run(workerCtx)
+
+	// Frequency capping: per-recipient token buckets plus collapsing of repeated opportunity updates
+	ratePolicies, err := ratelimit.ParsePolicies(
+		getEnv("RATE_LIMITS", constants.DefaultRateLimits),
+		getEnv("COLLAPSE_WINDOWS", constants.DefaultCollapseWindows))
+	if err != nil {
+		log.Fatalf("Failed to configure rate limits: %s", err)
+	}
+	suppressionLog := ratelimit.NewMemorySuppressionLog(10000)
+	notificationHandler.RateLimiter = ratelimit.NewLimiter(ratePolicies, ratelimit.NewMemoryBucketStore(), suppressionLog)
+	go notificationHandler.RateLimiter.Run(workerCtx)
+
+	// Deduplication: redelivered events are skipped, and retries only re-send the channels that failed
+	notificationHandler.Dedup = dedup.NewDeduplicator(newDedupStore(stateDB), constants.DedupTTL)
+	go notificationHandler.Dedup.Run(workerCtx)
+
+	// Schema validation: VALIDATION_MODE=strict moves messages that do not match their type's JSON Schema to the DLQ
+	validationMode := getEnv("VALIDATION_MODE", schema.ModeWarn)
+	if !schema.ValidMode(validationMode) {
+		log.Fatalf("Invalid VALIDATION_MODE '%s': must be off, warn or strict", validationMode)
+	}
+	notificationHandler.ValidationMode = validationMode
+
+	// Delivery log: every processed message and channel attempt is persisted for auditing
+	deliveryLog, err := deliverylog.Open(
+		getEnv("DELIVERY_LOG_DRIVER", deliverylog.DriverSQLite),
+		getEnv("DELIVERY_LOG_DSN", constants.DefaultDeliveryLogDSN))
+	if err != nil {
+		log.Fatalf("Failed to open delivery log: %s", err)
+	}
+	defer deliveryLog.Close()
+	notificationHandler.DeliveryLog = deliveryLog
+
+	// Bounces and complaints: suppressed addresses are never emailed again
+	softBounceTTL, err := time.ParseDuration(getEnv("SOFT_BOUNCE_SUPPRESSION", constants.DefaultSoftBounceSuppression.String()))
+	if err != nil {
+		log.Fatalf("Invalid SOFT_BOUNCE_SUPPRESSION: %s", err)
+	}
+	bouncePolicy := bounces.Policy{SoftBounceTTL: softBounceTTL}
+	emailSuppressions, err := bounces.NewSQLStore(stateDB)
+	handleErrorMessage(err, "Failed to open email suppression store")
+	notificationHandler.Suppressions = emailSuppressions
+
+	// Unsubscribe: emails carry signed one-click unsubscribe links when UNSUBSCRIBE_SECRET is set
+	var unsubscribeSigner *unsubscribe.Signer
+	if secret := os.Getenv("UNSUBSCRIBE_SECRET"); secret != "" {
+		unsubscribeSigner, err = unsubscribe.NewSigner([]byte(secret), constants.DefaultUnsubscribeTokenTTL)
+		if err != nil {
+			log.Fatalf("Failed to configure unsubscribe links: %s", err)
+		}
+		notificationHandler.Unsubscribe = unsubscribe.NewLinks(unsubscribeSigner, publicBaseURL())
+	}
+
+	// Email tracking: opens and clicks are recorded in the delivery log when EMAIL_TRACKING=true
+	var tracker *tracking.Tracker
+	if getEnv("EMAIL_TRACKING", "false") == "true" {
+		tracker, err = tracking.NewTracker([]byte(os.Getenv("TRACKING_SECRET")), publicBaseURL())
+		if err != nil {
+			log.Fatalf("Failed to configure email tracking: %s", err)
+		}
+		notificationHandler.Tracking = tracker
+	}
+
+	// Digests: emails for recipients on hourly/daily delivery are buffered and sent as one summary
+	if emailService != nil {
+		digestDefaults := parseKeyValues(os.Getenv("DIGEST_DEFAULTS"))
+		for notificationType, mode := range digestDefaults {
+			if !digest.ValidMode(mode) {
+				log.Fatalf("Invalid digest mode '%s' for %s in DIGEST_DEFAULTS", mode, notificationType)
+			}
+		}
+		digestStore, err := digest.NewSQLStore(stateDB)
+		handleErrorMessage(err, "Failed to open digest store")
+		digester, err := digest.NewDigester(digestStore, emailService, digestDefaults, time.Minute)
+		if err != nil {
+			log.Fatalf("Failed to configure digests: %s", err)
+		}
+		digester.SetResultHandler(notificationHandler.RecordDigest)
+		if notificationHandler.Unsubscribe != nil {
+			digester.SetUnsubscribeLinks(notificationHandler.Unsubscribe)
+		}
+		notificationHandler.Digests = digester
+		go digester.Run(workerCtx)
+	}
+
+	// Provider circuits: repeated send failures mark a provider unhealthy in /readyz until a send succeeds
+	providerHealth := health.NewCircuits(health.DefaultFailureThreshold)
+	if emailService != nil {
+		providerHealth.Add(email.ProviderName, emailService.Ping)
+	}
+	if pushService != nil {
+		for _, name := range pushService.Providers() {
+			providerHealth.Add(name, func(ctx context.Context) error { return pushService.Ping(ctx, name) })
+		}
+	}
+	notificationHandler.ProviderHealth = providerHealth
+
+	// Bound the unacknowledged messages the broker pushes to each consumer
+	prefetch, err := strconv.Atoi(getEnv("PREFETCH_COUNT", strconv.Itoa(constants.DefaultPrefetchCount)))
+	if err != nil {
+		log.Fatalf("Invalid PREFETCH_COUNT: %s", err)
+	}
+	handleErrorMessage(conn.SetPrefetch(prefetch), "Failed to set consumer prefetch")
+
+	// Dead letter queue: messages that fail permanently or MAX_DELIVERY_ATTEMPTS times are parked there
+	maxAttempts, err := strconv.Atoi(getEnv("MAX_DELIVERY_ATTEMPTS", strconv.Itoa(constants.DefaultMaxDeliveryAttempts)))
+	if err != nil {
+		log.Fatalf("Invalid MAX_DELIVERY_ATTEMPTS: %s", err)
+	}
+	deadLetters, err := rabbitmq.NewDeadLetterQueue(conn, constants.DeadLetterQueue, maxAttempts)
+	handleErrorMessage(err, "Failed to set up dead letter queue")
+	defer deadLetters.Close()
 
 	// Create consumer
 	consumer := rabbitmq.NewConsumer(conn, notificationHandler.ProcessMessage)
+	consumer.SetDeadLetterQueue(deadLetters)
 
 	// Start consuming from all queues
 	log.Println("Starting to consume messages...")
 	// Use from the new package
-	err = consumer.StartConsuming(VolunteerPushQueue)
+	err = consumer.StartConsuming(constants.VolunteerPushQueue)
 	if err != nil {
 		log.Fatalf("Failed to register volunteer push consumer: %s", err)
 	}
 
-	err = consumer.StartConsuming(NgoEmailQueue)
+	err = consumer.StartConsuming(constants.NgoEmailQueue)
 	if err != nil {
 		log.Fatalf("Failed to register NGO email consumer: %s", err)
 	}
 
-	err = consumer.StartConsuming(NgoPushQueue)
+	err = consumer.StartConsuming(constants.NgoPushQueue)
 	if err != nil {
 		log.Fatalf("Failed to register NGO push consumer: %s", err)
 	}
 
+	tokenConsumer := rabbitmq.NewConsumer(conn, handlers.NewTokenHandler(tokenStore).ProcessMessage)
+	tokenConsumer.SetDeadLetterQueue(deadLetters)
+	err = tokenConsumer.StartConsuming(constants.DeviceTokenQueue)
+	if err != nil {
+		log.Fatalf("Failed to register device token consumer: %s", err)
+	}
+
+	// Start HTTP API
+	apiServer := api.NewServer(getEnv("HTTP_ADDR", constants.DefaultHTTPAddr))
+	apiServer.HandleHealth(newHealthChecker(conn, providerHealth, consumer, tokenConsumer))
+	apiServer.HandleMetrics()
+	// Device tokens and preferences are managed by NestJS with one of API_KEYS
+	if apiKeys := splitList(os.Getenv("API_KEYS")); len(apiKeys) > 0 {
+		apiServer.HandleTokens(tokenStore, apiKeys)
+		apiServer.HandlePreferences(preferenceStore, apiKeys)
+	} else {
+		log.Println("API_KEYS not set; the device token and preference APIs are disabled")
+	}
+	// Bounce and complaint webhooks authenticate with BOUNCE_WEBHOOK_TOKEN
+	if token := os.Getenv("BOUNCE_WEBHOOK_TOKEN"); token != "" {
+		apiServer.HandleBounces(emailSuppressions, bouncePolicy, token)
+	} else {
+		log.Println("BOUNCE_WEBHOOK_TOKEN not set; the bounce and complaint webhooks are disabled")
+	}
+	if unsubscribeSigner != nil {
+		apiServer.HandleUnsubscribe(unsubscribeSigner, preferenceStore)
+	}
+	if tracker != nil {
+		apiServer.HandleTracking(tracker, deliveryLog)
+	}
+
+	// Direct submission over HTTP and gRPC, for tools that cannot publish to RabbitMQ
+	var grpcServer *grpcapi.Server
+	if apiKeys := splitList(os.Getenv("NOTIFICATIONS_API_KEYS")); len(apiKeys) > 0 {
+		notificationPublisher, err := rabbitmq.NewPublisher(conn, constants.ExchangeName)
+		handleErrorMessage(err, "Failed to open notification publisher")
+		defer notificationPublisher.Close()
+		apiServer.HandleNotifications(notificationHandler, notificationPublisher, deliveryLog, apiKeys)
+
+		grpcServer = grpcapi.NewServer(getEnv("GRPC_ADDR", constants.DefaultGRPCAddr),
+			notificationHandler, notificationPublisher, deliveryLog, apiKeys)
+		handleErrorMessage(grpcServer.Start(), "Failed to start gRPC API")
+	} else {
+		log.Println("NOTIFICATIONS_API_KEYS not set; POST /v1/notifications and the gRPC API are disabled")
+	}
+
+	// Admin API for the dead letter queue and pausing queues, used by notifyctl, the
+	// suppressed notification log, the delivery log queries and the email suppression list
+	if adminKeys := splitList(os.Getenv("ADMIN_API_KEYS")); len(adminKeys) > 0 {
+		replayPublisher, err := rabbitmq.NewPublisher(conn, constants.ExchangeName)
+		handleErrorMessage(err, "Failed to open replay publisher")
+		defer replayPublisher.Close()
+		dlqAudit, err := dlq.NewSQLAuditLog(stateDB)
+		handleErrorMessage(err, "Failed to open dead letter audit log")
+		dlqManager := dlq.NewManager(conn, replayPublisher, constants.DeadLetterQueue, dlqAudit)
+		apiServer.HandleDLQ(dlqManager, adminKeys)
+		apiServer.HandleQueues(adminKeys, consumer, tokenConsumer)
+		apiServer.HandleSuppressions(suppressionLog, adminKeys)
+		apiServer.HandleDeliveries(deliveryLog, adminKeys)
+		apiServer.HandleEmailSuppressions(emailSuppressions, adminKeys)
+	} else {
+		log.Println("ADMIN_API_KEYS not set; the /v1/admin API, the suppressed notification log, the delivery log API and the email suppression list API are disabled")
+	}
+	apiServer.Start()
+
 	log.Println("Go Notification Microservice started. Waiting for messages. To exit, press CTRL+C")
 
 	// Wait for termination signal
 	waitForShutdown()
-}
 
-// setupQueues declares and binds all necessary queues
-func setupQueues(conn *rabbitmq.Connection) {
-	// Volunteer Push Queue
-	// Use from the new package
-	_, err := conn.DeclareQueue(VolunteerPushQueue)
-	if err != nil {
-		log.Fatalf("Failed to declare '%s': %s", VolunteerPushQueue, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := apiServer.Shutdown(ctx); err != nil {
+		log.Printf("Failed to shut down HTTP API: %s", err)
 	}
-
-	err = conn.BindQueue(VolunteerPushQueue, RoutingKeyAppStatusChanged, ExchangeName)
-	if err != nil {
-		log.Fatalf("Failed to bind '%s' for '%s': %s", VolunteerPushQueue, RoutingKeyAppStatusChanged, err)
+	if grpcServer != nil {
+		grpcServer.Shutdown(ctx)
+	}
+	if err := shutdownTracing(ctx); err != nil {
+		log.Printf("Failed to flush traces: %s", err)
 	}
+}
 
-	// Removed binding for RoutingKeyAppCancelled, as it's not used by NestJS.
-	// If you later decide to use a separate routing key for application cancellations,
-	// you would add it back here and in go.
+// setupQueues declares the queues in constants.QueueOrder and binds them to the
+// exchange with their constants.QueueBindings routing keys
+func setupQueues(conn *rabbitmq.Connection) {
+	for _, queue := range constants.QueueOrder {
+		_, err := conn.DeclareQueue(queue)
+		if err != nil {
+			log.Fatalf("Failed to declare '%s': %s", queue, err)
+		}
 
-	err = conn.BindQueue(VolunteerPushQueue, RoutingKeyOpportunityCreated, ExchangeName)
-	if err != nil {
-		log.Fatalf("Failed to bind '%s' for '%s': %s", VolunteerPushQueue, RoutingKeyOpportunityCreated, err)
+		for _, routingKey := range constants.QueueBindings[queue] {
+			err = conn.BindQueue(queue, routingKey, constants.ExchangeName)
+			if err != nil {
+				log.Fatalf("Failed to bind '%s' for '%s': %s", queue, routingKey, err)
+			}
+		}
 	}
+}
 
-	// NEW: Bind for Opportunity Deleted and Updated
-	err = conn.BindQueue(VolunteerPushQueue, RoutingKeyOpportunityDeleted, ExchangeName)
-	if err != nil {
-		log.Fatalf("Failed to bind '%s' for '%s': %s", VolunteerPushQueue, RoutingKeyOpportunityDeleted, err)
+// newHealthChecker builds the readiness checks: the broker connection, every
+// consumer registration and each delivery provider's circuit.
+func newHealthChecker(conn *rabbitmq.Connection, providers *health.Circuits, consumers ...*rabbitmq.Consumer) *health.Checker {
+	checker := health.NewChecker()
+	checker.Add("rabbitmq", func(ctx context.Context) health.Result {
+		status := conn.Status()
+		if !status.Connected {
+			return health.Result{Status: health.StatusDown, Detail: status, Error: status.LastError}
+		}
+		return health.Result{Status: health.StatusOK, Detail: status}
+	})
+	checker.Add("consumers", func(ctx context.Context) health.Result {
+		var queues []rabbitmq.QueueStatus
+		for _, consumer := range consumers {
+			queues = append(queues, consumer.Status()...)
+		}
+		var paused []string
+		for _, queue := range queues {
+			if queue.Paused {
+				paused = append(paused, queue.Queue)
+				continue
+			}
+			if !queue.Registered {
+				return health.Result{Status: health.StatusDown, Detail: queues, Error: "consumer not registered for " + queue.Queue}
+			}
+		}
+		// A paused queue was stopped on purpose; it is reported but does not fail readiness.
+		if len(paused) > 0 {
+			return health.Result{Status: health.StatusDegraded, Detail: queues, Error: "paused: " + strings.Join(paused, ", ")}
+		}
+		return health.Result{Status: health.StatusOK, Detail: queues}
+	})
+	for _, provider := range providers.Providers() {
+		checker.Add("provider:"+provider, providers.Check(provider))
 	}
-	err = conn.BindQueue(VolunteerPushQueue, RoutingKeyOpportunityUpdated, ExchangeName)
-	if err != nil {
-		log.Fatalf("Failed to bind '%s' for '%s': %s", VolunteerPushQueue, RoutingKeyOpportunityUpdated, err)
+	return checker
+}
+
+// newDedupStore returns an in-memory LRU in front of the dedup table in the state store
+func newDedupStore(db *sqlstore.DB) dedup.Store {
+	backend, err := dedup.NewSQLStore(db)
+	handleErrorMessage(err, "Failed to open dedup store")
+	return dedup.NewLayeredStore(dedup.NewMemoryStore(constants.DedupCacheSize), backend, time.Hour)
+}
+
+// newEmailService configures the SMTP relay from the environment.
+// Without SMTP_HOST, emails are only logged.
+func newEmailService() *email.Service {
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		log.Println("No SMTP relay configured (set SMTP_HOST); emails will only be logged")
+		return nil
 	}
 
-	// NGO Email Queue
-	// Use from the new package
-	_, err = conn.DeclareQueue(NgoEmailQueue)
+	emailService, err := email.NewService(email.Config{
+		Host:     host,
+		Port:     os.Getenv("SMTP_PORT"),
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     getEnv("EMAIL_FROM", constants.DefaultEmailFrom),
+		DKIM:     loadDKIMKeys(os.Getenv("DKIM_KEYS")),
+	})
 	if err != nil {
-		log.Fatalf("Failed to declare '%s': %s", NgoEmailQueue, err)
+		log.Fatalf("Failed to configure email: %s", err)
 	}
+	return emailService
+}
 
-	err = conn.BindQueue(NgoEmailQueue, RoutingKeyApplicationNew, ExchangeName)
-	if err != nil {
-		log.Fatalf("Failed to bind '%s' for '%s': %s", NgoEmailQueue, RoutingKeyApplicationNew, err)
+// loadDKIMKeys loads DKIM_KEYS, formatted "domain:selector=/path/key.pem,...".
+// A domain may be listed more than once, e.g. with an RSA and an Ed25519 key.
+func loadDKIMKeys(raw string) []email.DKIMKey {
+	var keys []email.DKIMKey
+	for name, path := range parseKeyValues(raw) {
+		domain, selector, ok := strings.Cut(name, ":")
+		if !ok || domain == "" || selector == "" {
+			log.Fatalf("Invalid DKIM_KEYS entry '%s': expected domain:selector=/path/key.pem", name)
+		}
+		key, err := email.LoadDKIMKey(domain, selector, path)
+		if err != nil {
+			log.Fatalf("Failed to load DKIM key for %s: %s", name, err)
+		}
+		log.Printf("DKIM signing enabled for %s (Selector: %s, Algorithm: %s)", key.Domain, key.Selector, key.Algorithm())
+		keys = append(keys, key)
 	}
+	return keys
+}
 
-	// Removed binding for RoutingKeyAppCancelled
-	// err = conn.BindQueue(NgoEmailQueue, RoutingKeyAppCancelled, ExchangeName)
-	// if err != nil {
-	// 	log.Fatalf("Failed to bind '%s' for '%s': %s", NgoEmailQueue, RoutingKeyAppCancelled, err)
-	// }
+// newPushService configures the push providers that have credentials in the environment.
+// FCM is the default provider for tokens that arrive without one.
+func newPushService() *push.Service {
+	var providers []push.Provider
 
-	// NGO Push Queue
-	// Use from the new package
-	_, err = conn.DeclareQueue(NgoPushQueue)
-	if err != nil {
-		log.Fatalf("Failed to declare '%s': %s", NgoPushQueue, err)
+	if credentials := os.Getenv("FCM_CREDENTIALS_FILE"); credentials != "" {
+		fcm, err := push.NewFCMProvider(credentials)
+		if err != nil {
+			log.Fatalf("Failed to configure FCM: %s", err)
+		}
+		providers = append(providers, fcm)
 	}
 
-	err = conn.BindQueue(NgoPushQueue, RoutingKeyApplicationNew, ExchangeName)
-	if err != nil {
-		log.Fatalf("Failed to bind '%s' for '%s': %s", NgoPushQueue, RoutingKeyApplicationNew, err)
+	if keyFile := os.Getenv("APNS_KEY_FILE"); keyFile != "" {
+		apns, err := push.NewAPNsProvider(push.APNsConfig{
+			KeyFile:    keyFile,
+			KeyID:      os.Getenv("APNS_KEY_ID"),
+			TeamID:     os.Getenv("APNS_TEAM_ID"),
+			Topic:      os.Getenv("APNS_TOPIC"),
+			Production: os.Getenv("APNS_PRODUCTION") == "true",
+		})
+		if err != nil {
+			log.Fatalf("Failed to configure APNs: %s", err)
+		}
+		providers = append(providers, apns)
 	}
 
-	// Removed binding for RoutingKeyAppCancelled
-	// err = conn.BindQueue(NgoPushQueue, RoutingKeyAppCancelled, ExchangeName)
-	// if err != nil {
-	// 	log.Fatalf("Failed to bind '%s' for '%s': %s", NgoPushQueue, RoutingKeyAppCancelled, err)
-	// }
+	if len(providers) == 0 {
+		log.Println("No push providers configured (set FCM_CREDENTIALS_FILE and/or APNS_KEY_FILE); pushes will only be logged")
+		return nil
+	}
+	return push.NewService(tokens.ProviderFCM, providers...)
 }
 
 // waitForShutdown waits for a termination signal
@@ -0,0 +1,128 @@
+// metrics/metrics.go
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "notification"
+
+// Registry holds the service's metrics plus the Go runtime and process collectors.
+var Registry = prometheus.NewRegistry()
+
+// Consumer metrics, labelled by queue and routing key.
+var (
+	MessagesReceived = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "messages_received_total",
+		Help:      "Messages delivered to a consumer.",
+	}, []string{"queue", "routing_key"})
+
+	MessagesAcked = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "messages_acked_total",
+		Help:      "Messages acknowledged after processing.",
+	}, []string{"queue", "routing_key"})
+
+	MessagesNacked = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "messages_nacked_total",
+		Help:      "Messages negatively acknowledged after a processing error.",
+	}, []string{"queue", "routing_key", "requeue"})
+
+	// MessagesRedelivered counts retries: deliveries the broker flagged as redelivered
+	// after an earlier nack or a lost consumer.
+	MessagesRedelivered = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "messages_redelivered_total",
+		Help:      "Messages received again after an earlier delivery was not acknowledged.",
+	}, []string{"queue", "routing_key"})
+
+	MessagesDeadLettered = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "messages_dead_lettered_total",
+		Help:      "Messages moved to the dead letter queue, or rejected without requeue when there is none.",
+	}, []string{"queue", "routing_key"})
+
+	ConsumerPrefetch = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "consumer_prefetch",
+		Help:      "Prefetch limit of the consumer channel (0 is unlimited).",
+	}, []string{"queue"})
+
+	ConsumerPaused = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "consumer_paused",
+		Help:      "1 while consumption of the queue is paused by an operator.",
+	}, []string{"queue"})
+
+	ConsumerUnacked = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "consumer_unacked",
+		Help:      "Messages the broker has delivered to a consumer and not yet had acknowledged, including those waiting behind the one being processed.",
+	}, []string{"queue"})
+
+	ConsumerPrefetchUtilization = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "consumer_prefetch_utilization",
+		Help:      "consumer_unacked as a fraction of the prefetch limit; 1 means the broker is holding back further messages.",
+	}, []string{"queue"})
+
+	BrokerConnected = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "broker_connected",
+		Help:      "1 while the RabbitMQ connection is open, 0 after it closes.",
+	})
+)
+
+// Handler metrics.
+var (
+	ProcessingDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "processing_duration_seconds",
+		Help:      "Time to process one notification message, by type and delivery log status.",
+		Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+	}, []string{"notification_type", "status"})
+
+	ChannelSends = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "channel_sends_total",
+		Help:      "Channel delivery attempts, by channel, provider and outcome (sent, failed, skipped, suppressed, digested).",
+	}, []string{"channel", "provider", "outcome"})
+
+	ChannelSendDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "channel_send_duration_seconds",
+		Help:      "Provider latency of sent and failed channel deliveries.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"channel", "provider"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		MessagesReceived,
+		MessagesAcked,
+		MessagesNacked,
+		MessagesRedelivered,
+		MessagesDeadLettered,
+		ConsumerPrefetch,
+		ConsumerPaused,
+		ConsumerUnacked,
+		ConsumerPrefetchUtilization,
+		BrokerConnected,
+		ProcessingDuration,
+		ChannelSends,
+		ChannelSendDuration,
+	)
+}
+
+// Handler serves the registry in the Prometheus exposition format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
@@ -0,0 +1,24 @@
+// models/device_token.go
+package models
+
+// DeviceTokenMessage is the message NestJS publishes on the device token routing keys
+// to register or unregister a push token for a user.
+type DeviceTokenMessage struct {
+	// event_type is either "DEVICE_TOKEN_REGISTER" or "DEVICE_TOKEN_UNREGISTER".
+	EventType    string `json:"event_type"`
+	UserID       string `json:"user_id"`                 // Owner of the token
+	DeviceToken  string `json:"device_token"`            // FCM registration token or APNs device token
+	Provider     string `json:"provider,omitempty"`      // "fcm" or "apns"; defaults to "fcm"
+	PlatformType string `json:"platform_type,omitempty"` // e.g., "android", "ios", "web"
+}
+
+// DeviceTokenInvalidatedEvent is published back to the notification exchange when a
+// push provider reports that a token is dead (FCM UNREGISTERED, APNs 410).
+// NestJS consumes it to stop sending the token in future messages.
+type DeviceTokenInvalidatedEvent struct {
+	UserID      string `json:"user_id"`
+	DeviceToken string `json:"device_token"`
+	Provider    string `json:"provider"`
+	Reason      string `json:"reason"`    // Provider-supplied reason, e.g. "UNREGISTERED"
+	Timestamp   int64  `json:"timestamp"` // When the token was disabled (Unix timestamp)
+}
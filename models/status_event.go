@@ -0,0 +1,20 @@
+// models/status_event.go
+package models
+
+// NotificationStatusEvent is published back to the notification exchange after every
+// channel attempt (notification.delivered, notification.failed, notification.skipped)
+// so NestJS can show delivery state and react to failures.
+type NotificationStatusEvent struct {
+	EventID           string `json:"event_id,omitempty"`
+	NotificationType  string `json:"notification_type"`
+	UserID            string `json:"user_id"`
+	Channel           string `json:"channel"` // "email" or "push"
+	Status            string `json:"status"`  // "delivered", "failed" or "skipped"
+	Reason            string `json:"reason,omitempty"`
+	Error             string `json:"error,omitempty"`
+	Provider          string `json:"provider,omitempty"`
+	ProviderMessageID string `json:"provider_message_id,omitempty"`
+	ApplicationID     int    `json:"application_id,omitempty"`
+	OpportunityID     int    `json:"opportunity_id,omitempty"`
+	Timestamp         int64  `json:"timestamp"` // When the attempt finished (Unix timestamp)
+}
@@ -4,10 +4,12 @@ package models
 // Recipient defines the structure for the notification target user's details.
 // This data will be provided by the NestJS backend within the message.
 type Recipient struct {
-	UserID       string `json:"user_id"`                 // Unique ID of the user (volunteer or NGO)
-	PlatformType string `json:"platform_type,omitempty"` // e.g., "mobile", "web" (for push)
-	DeviceToken  string `json:"device_token,omitempty"`  // FCM token for push notifications
-	EmailAddress string `json:"email_address,omitempty"` // Email address for email notifications
+	UserID       string      `json:"user_id"`                 // Unique ID of the user (volunteer or NGO)
+	PlatformType string      `json:"platform_type,omitempty"` // e.g., "mobile", "web" (for push)
+	DeviceToken  string      `json:"device_token,omitempty"`  // FCM token for push notifications
+	EmailAddress string      `json:"email_address,omitempty"` // Email address for email notifications
+	Timezone     string      `json:"timezone,omitempty"`      // IANA name, e.g. "Asia/Kolkata"; used for quiet hours
+	QuietHours   *QuietHours `json:"quiet_hours,omitempty"`   // Optional window in the recipient's timezone
 	// PhoneNumber   string `json:"phone_number,omitempty"` // Uncomment if you add SMS later
 
 	// Prefs contains the user's general notification preferences.
@@ -19,6 +21,13 @@ type Recipient struct {
 	} `json:"prefs"`
 }
 
+// QuietHours is a daily window, in the recipient's local time, during which non-urgent
+// notifications are held back. Times are "HH:MM"; a window may wrap past midnight (22:00-07:00).
+type QuietHours struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
 // Payload defines the actual content of the notification.
 // This content is prepared by the NestJS backend.
 type Payload struct {
@@ -48,6 +57,7 @@ type NotificationMessage struct {
 	// notification_type specifies what kind of business event this notification is for.
 	// This helps the Go service route to the correct internal logic.
 	NotificationType string    `json:"notification_type"`        // e.g., "VOLUNTEER_APP_STATUS_UPDATE", "NGO_NEW_APPLICATION"
+	EventID          string    `json:"event_id,omitempty"`       // Stable ID for deduplication; falls back to the AMQP message-id
 	Recipient        Recipient `json:"recipient"`                // Details about who receives the notification
 	Payload          Payload   `json:"payload"`                  // The actual content to be delivered
 	SenderService    string    `json:"sender_service,omitempty"` // Optional: for auditing/debugging, e.g., "VolHub_ApplicationsService"
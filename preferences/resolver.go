@@ -0,0 +1,97 @@
+// preferences/resolver.go
+package preferences
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"notification-service/models"
+)
+
+// How message-supplied prefs (Recipient.Prefs from NestJS) combine with stored preferences.
+const (
+	// ModeFallback uses stored preferences and falls back to the message prefs
+	// when the user has not set anything for the type/channel.
+	ModeFallback = "fallback"
+	// ModeOverride lets the message prefs switch a channel off regardless of stored
+	// preferences; when the message allows a channel, stored preferences decide.
+	ModeOverride = "override"
+)
+
+// Resolver decides whether a notification may be delivered on a channel.
+type Resolver struct {
+	store Store
+	mode  string
+}
+
+// NewResolver creates a resolver over a preference store
+func NewResolver(store Store, mode string) (*Resolver, error) {
+	if mode == "" {
+		mode = ModeFallback
+	}
+	if mode != ModeFallback && mode != ModeOverride {
+		return nil, fmt.Errorf("unknown preferences mode %q (expected %q or %q)", mode, ModeFallback, ModeOverride)
+	}
+	return &Resolver{store: store, mode: mode}, nil
+}
+
+// Allowed reports whether a notification of the given type may be sent to the user on channel.
+// messagePref is the matching boolean from the message's Recipient.Prefs.
+func (r *Resolver) Allowed(userID, notificationType, channel string, messagePref bool) bool {
+	if r.mode == ModeOverride && !messagePref {
+		return false
+	}
+
+	p, err := r.store.Get(userID)
+	if err != nil {
+		if !errors.Is(err, ErrNotFound) {
+			log.Printf("Failed to load preferences for %s, using message prefs: %v", userID, err)
+		}
+		return messagePref
+	}
+
+	if enabled, ok := p.Lookup(notificationType, channel); ok {
+		return enabled
+	}
+	return messagePref
+}
+
+// QuietHours returns the user's stored quiet hours window and timezone.
+// ok is false when the user has not configured quiet hours.
+func (r *Resolver) QuietHours(userID string) (qh models.QuietHours, timezone string, ok bool) {
+	p, err := r.store.Get(userID)
+	if err != nil || p.QuietHours == nil {
+		return models.QuietHours{}, "", false
+	}
+	return *p.QuietHours, p.Timezone, true
+}
+
+// Timezone returns the user's stored timezone, if any.
+func (r *Resolver) Timezone(userID string) (string, bool) {
+	p, err := r.store.Get(userID)
+	if err != nil || p.Timezone == "" {
+		return "", false
+	}
+	return p.Timezone, true
+}
+
+// DigestMode returns the user's chosen email delivery mode for a notification type.
+// ok is false when the user has not chosen one.
+func (r *Resolver) DigestMode(userID, notificationType string) (string, bool) {
+	p, err := r.store.Get(userID)
+	if err != nil {
+		return "", false
+	}
+	mode, ok := p.Digest[notificationType]
+	return mode, ok
+}
+
+// TrackingAllowed reports whether the user allows email open/click tracking.
+func (r *Resolver) TrackingAllowed(userID string) bool {
+	p, err := r.store.Get(userID)
+	if err != nil {
+		return true
+	}
+	return !p.TrackingDisabled
+}
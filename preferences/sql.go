@@ -0,0 +1,191 @@
+// preferences/sql.go
+package preferences
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"notification-service/sqlstore"
+)
+
+// Preferences are stored as one JSON document per user; updated_at is Unix milliseconds.
+var schema = map[string][]string{
+	sqlstore.DriverSQLite: {
+		`CREATE TABLE IF NOT EXISTS user_preferences (
+			user_id TEXT PRIMARY KEY,
+			preferences TEXT NOT NULL,
+			updated_at INTEGER NOT NULL
+		)`,
+	},
+	sqlstore.DriverPostgres: {
+		`CREATE TABLE IF NOT EXISTS user_preferences (
+			user_id TEXT PRIMARY KEY,
+			preferences TEXT NOT NULL,
+			updated_at BIGINT NOT NULL
+		)`,
+	},
+}
+
+// SQLStore is a Store backed by SQLite or Postgres, so opt-outs (including one-click
+// unsubscribes) survive restarts.
+type SQLStore struct {
+	db *sqlstore.DB
+}
+
+// NewSQLStore creates the preference table in db if needed
+func NewSQLStore(db *sqlstore.DB) (*SQLStore, error) {
+	if err := db.Migrate(schema[db.Driver]); err != nil {
+		return nil, fmt.Errorf("creating preference schema: %w", err)
+	}
+	return &SQLStore{db: db}, nil
+}
+
+// Get returns a user's preferences
+func (s *SQLStore) Get(userID string) (Preferences, error) {
+	return s.get(context.Background(), s.db, userID, "")
+}
+
+// Put replaces a user's preferences
+func (s *SQLStore) Put(p Preferences) (Preferences, error) {
+	p = clone(p)
+	p.UpdatedAt = time.Now().UTC()
+	return p, s.put(context.Background(), s.db, p)
+}
+
+// Set stores a single channel setting for a notification type (or the channel default)
+func (s *SQLStore) Set(userID, notificationType, channel string, enabled bool) (Preferences, error) {
+	return s.update(userID, func(p *Preferences) {
+		if notificationType == "" {
+			p.Channels[channel] = enabled
+			return
+		}
+		if p.Types[notificationType] == nil {
+			p.Types[notificationType] = make(map[string]bool)
+		}
+		p.Types[notificationType][channel] = enabled
+	})
+}
+
+// SetDigestMode stores the email delivery mode for a notification type
+func (s *SQLStore) SetDigestMode(userID, notificationType, mode string) (Preferences, error) {
+	return s.update(userID, func(p *Preferences) {
+		p.Digest[notificationType] = mode
+	})
+}
+
+// SetTracking stores the user's email tracking opt-out
+func (s *SQLStore) SetTracking(userID string, enabled bool) (Preferences, error) {
+	return s.update(userID, func(p *Preferences) {
+		p.TrackingDisabled = !enabled
+	})
+}
+
+// Delete removes a user's preferences
+func (s *SQLStore) Delete(userID string) error {
+	result, err := s.db.ExecContext(context.Background(), s.db.Rebind(
+		`DELETE FROM user_preferences WHERE user_id = ?`), userID)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// querier is the part of *sql.DB and *sql.Tx the store uses.
+type querier interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// update applies change to the user's preferences, creating them if needed, in a
+// transaction so concurrent single-setting updates do not overwrite each other.
+func (s *SQLStore) update(userID string, change func(p *Preferences)) (Preferences, error) {
+	ctx := context.Background()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return Preferences{}, err
+	}
+	defer tx.Rollback()
+
+	lock := ""
+	if s.db.Driver == sqlstore.DriverPostgres {
+		lock = " FOR UPDATE"
+	}
+	p, err := s.get(ctx, tx, userID, lock)
+	if errors.Is(err, ErrNotFound) {
+		p, err = clone(Preferences{UserID: userID}), nil
+	}
+	if err != nil {
+		return Preferences{}, err
+	}
+
+	change(&p)
+	p.UpdatedAt = time.Now().UTC()
+	if err := s.put(ctx, tx, p); err != nil {
+		return Preferences{}, err
+	}
+	return p, tx.Commit()
+}
+
+func (s *SQLStore) get(ctx context.Context, q querier, userID, suffix string) (Preferences, error) {
+	var document string
+	err := q.QueryRowContext(ctx, s.db.Rebind(
+		`SELECT preferences FROM user_preferences WHERE user_id = ?`+suffix), userID).Scan(&document)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Preferences{}, ErrNotFound
+	}
+	if err != nil {
+		return Preferences{}, err
+	}
+
+	var p Preferences
+	if err := json.Unmarshal([]byte(document), &p); err != nil {
+		return Preferences{}, fmt.Errorf("decoding preferences of %s: %w", userID, err)
+	}
+	return clone(p), nil
+}
+
+func (s *SQLStore) put(ctx context.Context, q querier, p Preferences) error {
+	document, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	_, err = q.ExecContext(ctx, s.db.Rebind(`INSERT INTO user_preferences (user_id, preferences, updated_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT (user_id) DO UPDATE SET preferences = excluded.preferences, updated_at = excluded.updated_at`),
+		p.UserID, string(document), p.UpdatedAt.UnixMilli())
+	return err
+}
+
+// clone deep-copies the maps so callers cannot mutate stored state.
+func clone(p Preferences) Preferences {
+	c := p
+	c.Channels = make(map[string]bool, len(p.Channels))
+	for k, v := range p.Channels {
+		c.Channels[k] = v
+	}
+	c.Types = make(map[string]map[string]bool, len(p.Types))
+	for t, channels := range p.Types {
+		c.Types[t] = make(map[string]bool, len(channels))
+		for k, v := range channels {
+			c.Types[t][k] = v
+		}
+	}
+	c.Digest = make(map[string]string, len(p.Digest))
+	for k, v := range p.Digest {
+		c.Digest[k] = v
+	}
+	if p.QuietHours != nil {
+		qh := *p.QuietHours
+		c.QuietHours = &qh
+	}
+	return c
+}
@@ -0,0 +1,67 @@
+// preferences/sql_test.go
+package preferences
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"notification-service/sqlstore"
+)
+
+func TestSQLStorePersistsSettings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "preferences.db")
+	db, err := sqlstore.Open(sqlstore.DriverSQLite, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := NewSQLStore(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := store.Set("u1", "VOLUNTEER_NEW_MATCHING_OPPORTUNITY", ChannelEmail, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Set("u1", "", ChannelPush, true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.SetDigestMode("u1", "NGO_NEW_APPLICATION", "daily"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.SetTracking("u1", false); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	// Reopening the database must return everything that was set.
+	db, err = sqlstore.Open(sqlstore.DriverSQLite, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	store, err = NewSQLStore(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := store.Get("u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if enabled, ok := p.Lookup("VOLUNTEER_NEW_MATCHING_OPPORTUNITY", ChannelEmail); !ok || enabled {
+		t.Errorf("Lookup(email) = %t, %t; want an opt-out", enabled, ok)
+	}
+	if enabled, ok := p.Lookup("VOLUNTEER_NEW_MATCHING_OPPORTUNITY", ChannelPush); !ok || !enabled {
+		t.Errorf("Lookup(push) = %t, %t; want the channel default", enabled, ok)
+	}
+	if p.Digest["NGO_NEW_APPLICATION"] != "daily" || !p.TrackingDisabled {
+		t.Errorf("Get() = %+v, want the daily digest and tracking opt-out", p)
+	}
+
+	if err := store.Delete("u1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get("u1"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get after Delete = %v, want ErrNotFound", err)
+	}
+}
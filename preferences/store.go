@@ -0,0 +1,71 @@
+// preferences/store.go
+package preferences
+
+import (
+	"errors"
+	"time"
+
+	"notification-service/models"
+)
+
+// Delivery channels a preference can be set for.
+const (
+	ChannelEmail = "email"
+	ChannelPush  = "push"
+)
+
+// ErrNotFound is returned when a user has no stored preferences.
+var ErrNotFound = errors.New("preferences not found")
+
+// Preferences are a user's stored notification settings. The most specific setting wins:
+// a per-type channel setting overrides the user's channel default.
+type Preferences struct {
+	UserID string `json:"user_id"`
+	// Channels holds the user's default per-channel setting, e.g. {"email": false}.
+	Channels map[string]bool `json:"channels,omitempty"`
+	// Types holds per notification type settings,
+	// e.g. {"VOLUNTEER_NEW_MATCHING_OPPORTUNITY": {"email": false, "push": true}}.
+	Types map[string]map[string]bool `json:"types,omitempty"`
+	// Digest holds the email delivery mode per notification type: "immediate", "hourly" or "daily".
+	Digest map[string]string `json:"digest,omitempty"`
+	// Timezone and QuietHours take precedence over the values sent in the message.
+	Timezone   string             `json:"timezone,omitempty"`
+	QuietHours *models.QuietHours `json:"quiet_hours,omitempty"`
+	// TrackingDisabled opts the user out of email open/click tracking.
+	TrackingDisabled bool      `json:"tracking_disabled,omitempty"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// Lookup returns the stored setting for a notification type and channel.
+// ok is false when the user has not expressed a preference for it.
+func (p Preferences) Lookup(notificationType, channel string) (enabled bool, ok bool) {
+	if channels, found := p.Types[notificationType]; found {
+		if enabled, ok := channels[channel]; ok {
+			return enabled, true
+		}
+	}
+	enabled, ok = p.Channels[channel]
+	return enabled, ok
+}
+
+// ValidChannel reports whether channel is a known delivery channel.
+func ValidChannel(channel string) bool {
+	return channel == ChannelEmail || channel == ChannelPush
+}
+
+// Store persists user preferences.
+type Store interface {
+	// Get returns a user's preferences, or ErrNotFound.
+	Get(userID string) (Preferences, error)
+	// Put replaces a user's preferences.
+	Put(p Preferences) (Preferences, error)
+	// Set stores a single per-type channel setting, creating the user's preferences if needed.
+	// An empty notificationType sets the channel default.
+	Set(userID, notificationType, channel string, enabled bool) (Preferences, error)
+	// SetDigestMode stores the email delivery mode for a notification type.
+	SetDigestMode(userID, notificationType, mode string) (Preferences, error)
+	// SetTracking stores whether email opens and clicks may be tracked for the user.
+	SetTracking(userID string, enabled bool) (Preferences, error)
+	// Delete removes a user's preferences. It returns ErrNotFound if there were none.
+	Delete(userID string) error
+}
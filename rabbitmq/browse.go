@@ -0,0 +1,60 @@
+// rabbitmq/browse.go
+package rabbitmq
+
+import amqp "github.com/rabbitmq/amqp091-go"
+
+// BrowseFunc is called for each message read by Browse. Returning remove acknowledges
+// (deletes) the message; returning stop ends the browse.
+type BrowseFunc func(d Delivery) (remove, stop bool, err error)
+
+// Browse reads messages from a queue in order without consuming them, on a channel of
+// its own. It stops when visit says so, after limit messages (0 means no limit), or when
+// the queue is empty. Messages visit did not remove are returned to the queue when Browse
+// ends, flagged as redelivered. It is meant for queues without consumers, like a dead letter queue.
+func (c *Connection) Browse(queue string, limit int, visit BrowseFunc) error {
+	ch, err := c.openChannel()
+	if err != nil {
+		return err
+	}
+	// Closing the channel requeues every message still unacknowledged.
+	defer ch.Close()
+
+	for read := 0; limit <= 0 || read < limit; read++ {
+		d, ok, err := ch.Get(queue, false)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+
+		remove, stop, err := visit(deliveryFrom(d, queue))
+		if err != nil {
+			return err
+		}
+		if remove {
+			if err := d.Ack(false); err != nil {
+				return err
+			}
+		}
+		if stop {
+			return nil
+		}
+	}
+	return nil
+}
+
+// deliveryFrom converts an AMQP delivery read from queue.
+func deliveryFrom(d amqp.Delivery, queue string) Delivery {
+	return Delivery{
+		Body:        d.Body,
+		MessageID:   d.MessageId,
+		ContentType: d.ContentType,
+		Timestamp:   d.Timestamp,
+		RoutingKey:  d.RoutingKey,
+		Queue:       queue,
+		Redelivered: d.Redelivered,
+		DeliveryTag: d.DeliveryTag,
+		Headers:     d.Headers,
+	}
+}
@@ -2,16 +2,51 @@
 package rabbitmq
 
 import (
+	"errors"
 	"log"
 	"os"
+	"sync"
+	"time"
 
 	amqp "github.com/rabbitmq/amqp091-go"
+
+	"notification-service/metrics"
+)
+
+// Reconnect backoff bounds.
+const (
+	minReconnectDelay = time.Second
+	maxReconnectDelay = 30 * time.Second
 )
 
-// Connection holds the RabbitMQ connection and channel
+// ErrNotConnected is returned while the connection to the broker is down.
+var ErrNotConnected = errors.New("not connected to RabbitMQ")
+
+// Connection holds the RabbitMQ connection and channel. When the broker connection
+// drops it reconnects in the background, and when only the shared channel closes it
+// reopens it; either way it then runs the OnReconnect hooks, so consumers and
+// publishers can restore their channels.
 type Connection struct {
-	Conn    *amqp.Connection
-	Channel *amqp.Channel
+	url string
+
+	mu         sync.RWMutex
+	conn       *amqp.Connection
+	channel    *amqp.Channel
+	prefetch   int // Per-consumer prefetch limit set by SetPrefetch; 0 is unlimited
+	connected  bool
+	since      time.Time // When connected last changed
+	lastError  string
+	reconnects int
+	hooks      []func() error
+	closing    bool
+}
+
+// ConnectionStatus is a snapshot of the broker connection state.
+type ConnectionStatus struct {
+	Connected  bool      `json:"connected"`
+	Since      time.Time `json:"since"`
+	LastError  string    `json:"last_error,omitempty"`
+	Reconnects int       `json:"reconnects"`
 }
 
 // NewConnection creates a new RabbitMQ connection
@@ -22,40 +57,266 @@ func NewConnection(defaultURL string) (*Connection, error) {
 		amqpURL = defaultURL
 	}
 
+	return Dial(amqpURL)
+}
+
+// Dial creates a RabbitMQ connection to the given URL
+func Dial(amqpURL string) (*Connection, error) {
+	c := &Connection{url: amqpURL}
+	if err := c.dial(); err != nil {
+		return nil, err
+	}
+	log.Println("Successfully connected to RabbitMQ and opened a channel!")
+	return c, nil
+}
+
+// dial connects, opens the shared channel and starts watching for the connection to close.
+func (c *Connection) dial() error {
 	// Connect to RabbitMQ
-	conn, err := amqp.Dial(amqpURL)
+	conn, err := amqp.Dial(c.url)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	// Create a channel
-	ch, err := conn.Channel()
+	ch, err := c.openShared(conn)
 	if err != nil {
 		conn.Close()
+		return err
+	}
+
+	c.mu.Lock()
+	c.conn = conn
+	c.channel = ch
+	c.connected = true
+	c.since = time.Now()
+	c.mu.Unlock()
+
+	metrics.BrokerConnected.Set(1)
+	go c.watch(conn.NotifyClose(make(chan *amqp.Error, 1)))
+	go c.watchChannel(conn, ch.NotifyClose(make(chan *amqp.Error, 1)))
+	return nil
+}
+
+// openShared opens the shared channel on conn with the configured prefetch.
+func (c *Connection) openShared(conn *amqp.Connection) (*amqp.Channel, error) {
+	ch, err := conn.Channel()
+	if err != nil {
 		return nil, err
 	}
+	c.mu.RLock()
+	prefetch := c.prefetch
+	c.mu.RUnlock()
+	if prefetch > 0 {
+		if err := ch.Qos(prefetch, 0, false); err != nil {
+			ch.Close()
+			return nil, err
+		}
+	}
+	return ch, nil
+}
 
-	log.Println("Successfully connected to RabbitMQ and opened a channel!")
+// watch waits for the connection to close and, unless Close was called, reconnects.
+func (c *Connection) watch(closed <-chan *amqp.Error) {
+	amqpErr, ok := <-closed
+	metrics.BrokerConnected.Set(0)
+
+	c.mu.Lock()
+	c.connected = false
+	c.since = time.Now()
+	if ok && amqpErr != nil {
+		c.lastError = amqpErr.Error()
+	}
+	closing := c.closing
+	c.mu.Unlock()
+
+	if closing {
+		return
+	}
+	log.Printf("RabbitMQ connection lost: %v. Reconnecting.", amqpErr)
+	c.reconnect()
+}
+
+// watchChannel waits for the shared channel to close while its connection stays up,
+// e.g. after a channel-level error such as a failed declare, and reopens it.
+func (c *Connection) watchChannel(conn *amqp.Connection, closed <-chan *amqp.Error) {
+	amqpErr, ok := <-closed
+	if conn.IsClosed() {
+		return // watch reconnects
+	}
+
+	c.mu.Lock()
+	closing := c.closing
+	if !closing {
+		c.channel = nil
+		if ok && amqpErr != nil {
+			c.lastError = amqpErr.Error()
+		}
+	}
+	c.mu.Unlock()
+	if closing {
+		return
+	}
+	log.Printf("RabbitMQ channel closed: %v. Reopening.", amqpErr)
+
+	delay := minReconnectDelay
+	for {
+		ch, err := c.openShared(conn)
+		if err == nil {
+			c.mu.Lock()
+			c.channel = ch
+			c.mu.Unlock()
+			go c.watchChannel(conn, ch.NotifyClose(make(chan *amqp.Error, 1)))
+			break
+		}
+		if conn.IsClosed() {
+			return
+		}
+		log.Printf("Failed to reopen RabbitMQ channel: %v. Retrying in %s.", err, delay)
+		time.Sleep(delay)
+		if delay *= 2; delay > maxReconnectDelay {
+			delay = maxReconnectDelay
+		}
+	}
+
+	log.Println("Reopened RabbitMQ channel")
+	c.runHooks()
+}
+
+// reconnect dials with exponential backoff until it succeeds or Close is called,
+// then runs the reconnect hooks.
+func (c *Connection) reconnect() {
+	delay := minReconnectDelay
+	for {
+		time.Sleep(delay)
 
-	return &Connection{
-		Conn:    conn,
-		Channel: ch,
-	}, nil
+		c.mu.RLock()
+		closing := c.closing
+		c.mu.RUnlock()
+		if closing {
+			return
+		}
+
+		err := c.dial()
+		if err == nil {
+			break
+		}
+		log.Printf("Failed to reconnect to RabbitMQ: %v. Retrying in %s.", err, delay)
+		c.mu.Lock()
+		c.lastError = err.Error()
+		c.mu.Unlock()
+		if delay *= 2; delay > maxReconnectDelay {
+			delay = maxReconnectDelay
+		}
+	}
+
+	c.mu.Lock()
+	c.reconnects++
+	c.mu.Unlock()
+	log.Println("Reconnected to RabbitMQ")
+	c.runHooks()
+}
+
+// runHooks runs the OnReconnect hooks.
+func (c *Connection) runHooks() {
+	c.mu.RLock()
+	hooks := append([]func() error(nil), c.hooks...)
+	c.mu.RUnlock()
+
+	for _, hook := range hooks {
+		if err := hook(); err != nil {
+			log.Printf("Failed to restore RabbitMQ state after reconnecting: %v", err)
+		}
+	}
+}
+
+// OnReconnect registers fn to run after every successful reconnection, and after
+// the shared channel is reopened.
+func (c *Connection) OnReconnect(fn func() error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.hooks = append(c.hooks, fn)
+}
+
+// Status reports whether the broker is connected and since when.
+func (c *Connection) Status() ConnectionStatus {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return ConnectionStatus{
+		Connected:  c.connected,
+		Since:      c.since,
+		LastError:  c.lastError,
+		Reconnects: c.reconnects,
+	}
+}
+
+// Prefetch returns the per-consumer prefetch limit; 0 is unlimited.
+func (c *Connection) Prefetch() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.prefetch
+}
+
+// sharedChannel returns the channel used for topology and consumers.
+func (c *Connection) sharedChannel() (*amqp.Channel, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if !c.connected || c.channel == nil {
+		return nil, ErrNotConnected
+	}
+	return c.channel, nil
+}
+
+// openChannel opens a new channel on the current connection.
+func (c *Connection) openChannel() (*amqp.Channel, error) {
+	c.mu.RLock()
+	conn, connected := c.conn, c.connected
+	c.mu.RUnlock()
+	if !connected {
+		return nil, ErrNotConnected
+	}
+	return conn.Channel()
+}
+
+// SetPrefetch limits how many unacknowledged messages the broker delivers to each
+// consumer started afterwards on the channel. It is reapplied after reconnecting.
+func (c *Connection) SetPrefetch(count int) error {
+	ch, err := c.sharedChannel()
+	if err != nil {
+		return err
+	}
+	if err := ch.Qos(count, 0, false); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.prefetch = count
+	c.mu.Unlock()
+	log.Printf("Set consumer prefetch to %d", count)
+	return nil
 }
 
 // Close closes the RabbitMQ connection and channel
 func (c *Connection) Close() {
-	if c.Channel != nil {
-		c.Channel.Close()
+	c.mu.Lock()
+	c.closing = true
+	conn, ch := c.conn, c.channel
+	c.mu.Unlock()
+
+	if ch != nil {
+		ch.Close()
 	}
-	if c.Conn != nil {
-		c.Conn.Close()
+	if conn != nil {
+		conn.Close()
 	}
 }
 
 // DeclareExchange declares a RabbitMQ exchange
 func (c *Connection) DeclareExchange(name, exchangeType string) error {
-	err := c.Channel.ExchangeDeclare(
+	ch, err := c.sharedChannel()
+	if err != nil {
+		return err
+	}
+	err = ch.ExchangeDeclare(
 		name,         // name
 		exchangeType, // type
 		true,         // durable
@@ -74,7 +335,11 @@ func (c *Connection) DeclareExchange(name, exchangeType string) error {
 
 // DeclareQueue declares a RabbitMQ queue
 func (c *Connection) DeclareQueue(name string) (amqp.Queue, error) {
-	queue, err := c.Channel.QueueDeclare(
+	ch, err := c.sharedChannel()
+	if err != nil {
+		return amqp.Queue{}, err
+	}
+	queue, err := ch.QueueDeclare(
 		name,  // name
 		true,  // durable
 		false, // delete when unused
@@ -92,7 +357,11 @@ func (c *Connection) DeclareQueue(name string) (amqp.Queue, error) {
 
 // BindQueue binds a queue to an exchange with a routing key
 func (c *Connection) BindQueue(queueName, routingKey, exchangeName string) error {
-	err := c.Channel.QueueBind(
+	ch, err := c.sharedChannel()
+	if err != nil {
+		return err
+	}
+	err = ch.QueueBind(
 		queueName,    // queue name
 		routingKey,   // routing key
 		exchangeName, // exchange name
@@ -106,3 +375,15 @@ func (c *Connection) BindQueue(queueName, routingKey, exchangeName string) error
 	log.Printf("Bound queue '%s' to exchange '%s' with routing key '%s'", queueName, exchangeName, routingKey)
 	return nil
 }
+
+// InspectQueue returns a queue's message and consumer counts without declaring it.
+// It fails if the queue does not exist.
+func (c *Connection) InspectQueue(name string) (amqp.Queue, error) {
+	// A failed passive declare closes the channel, so use one of its own.
+	ch, err := c.openChannel()
+	if err != nil {
+		return amqp.Queue{}, err
+	}
+	defer ch.Close()
+	return ch.QueueDeclarePassive(name, true, false, false, false, nil)
+}
@@ -2,51 +2,419 @@
 package rabbitmq
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log"
+	"log/slog"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"notification-service/constants"
+	"notification-service/logging"
+	"notification-service/metrics"
+	"notification-service/tracing"
 )
 
-// Consumer represents a RabbitMQ message consumer
+// ErrDeadLetter marks a handler error as permanent: the message is moved to the dead
+// letter queue (or, without one, rejected without requeue) instead of redelivered.
+var ErrDeadLetter = errors.New("dead-letter message")
+
+// ErrUnknownQueue is returned when pausing or resuming a queue the consumer was not started on.
+var ErrUnknownQueue = errors.New("consumer was not started on queue")
+
+// Delivery is the part of an AMQP delivery that message handlers need
+type Delivery struct {
+	Body        []byte
+	MessageID   string // AMQP message-id property, if the publisher set one
+	ContentType string
+	Timestamp   time.Time // AMQP timestamp property; zero if the publisher did not set one
+	RoutingKey  string
+	Queue       string
+	Redelivered bool
+	DeliveryTag uint64
+	Headers     map[string]interface{}
+}
+
+// Handler processes a single delivery. ctx carries the message's processing span,
+// continued from the publisher's traceparent header when present.
+// Returning an error re-queues the message, unless it wraps ErrDeadLetter or the
+// message has run out of attempts.
+type Handler func(ctx context.Context, d Delivery) error
+
+// Consumer represents a RabbitMQ message consumer. It supervises the queues it was
+// started on: after the connection is re-established they are consumed again, except
+// those paused with Pause.
 type Consumer struct {
-	conn    *Connection
-	handler func([]byte) error
+	conn        *Connection
+	handler     Handler
+	deadLetters *DeadLetterQueue // nil: permanent failures are rejected without requeue
+
+	mu     sync.Mutex
+	queues []*queueState // In the order StartConsuming was called
+}
+
+// queueState tracks the consumer registration for one queue.
+type queueState struct {
+	name        string
+	registered  bool
+	paused      bool // Paused by an operator; not consumed until resumed, even after reconnecting
+	consumerTag string
+	since       time.Time
+	lastError   string
+}
+
+// QueueStatus is a snapshot of one queue's consumer registration.
+type QueueStatus struct {
+	Queue       string    `json:"queue"`
+	Registered  bool      `json:"registered"`
+	Paused      bool      `json:"paused"`
+	ConsumerTag string    `json:"consumer_tag,omitempty"`
+	Since       time.Time `json:"since"`
+	LastError   string    `json:"last_error,omitempty"`
 }
 
 // NewConsumer creates a new RabbitMQ message consumer
-func NewConsumer(conn *Connection, handler func([]byte) error) *Consumer {
-	return &Consumer{
+func NewConsumer(conn *Connection, handler Handler) *Consumer {
+	c := &Consumer{
 		conn:    conn,
 		handler: handler,
 	}
+	conn.OnReconnect(c.restart)
+	return c
+}
+
+// SetDeadLetterQueue moves messages that fail permanently, or too many times, to q.
+// It must be called before StartConsuming.
+func (c *Consumer) SetDeadLetterQueue(q *DeadLetterQueue) {
+	c.deadLetters = q
 }
 
 // StartConsuming starts consuming messages from a queue
 func (c *Consumer) StartConsuming(queueName string) error {
-	msgs, err := c.conn.Channel.Consume(
-		queueName, // queue
-		"",        // consumer
-		false,     // auto-ack (manual ack)
-		false,     // exclusive
-		false,     // no-local
-		false,     // no-wait
-		nil,       // args
-	)
-	if err != nil {
+	state := &queueState{name: queueName}
+	if err := c.consume(state); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.queues = append(c.queues, state)
+	c.mu.Unlock()
+	log.Printf("Started consuming messages from queue: %s", queueName)
+	return nil
+}
+
+// Status reports the registration of every queue the consumer was started on.
+func (c *Consumer) Status() []QueueStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	statuses := make([]QueueStatus, len(c.queues))
+	for i, q := range c.queues {
+		statuses[i] = QueueStatus{
+			Queue:       q.name,
+			Registered:  q.registered,
+			Paused:      q.paused,
+			ConsumerTag: q.consumerTag,
+			Since:       q.since,
+			LastError:   q.lastError,
+		}
+	}
+	return statuses
+}
+
+// Pause stops consuming a queue by cancelling its consumer tag, leaving its messages in
+// the broker. A message being processed is finished first; messages the broker had
+// already delivered are re-queued unprocessed. The queue stays paused across
+// reconnections until Resume is called.
+func (c *Consumer) Pause(queueName string) error {
+	c.mu.Lock()
+	q := c.queue(queueName)
+	if q == nil {
+		c.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrUnknownQueue, queueName)
+	}
+	if q.paused {
+		c.mu.Unlock()
+		return nil
+	}
+	q.paused = true
+	tag := q.consumerTag
+	q.registered = false
+	q.consumerTag = ""
+	q.since = time.Now()
+	c.mu.Unlock()
+	metrics.ConsumerPaused.WithLabelValues(queueName).Set(1)
+
+	if tag != "" {
+		if ch, err := c.conn.sharedChannel(); err == nil {
+			// If this fails the channel is gone, and the consumer with it.
+			if err := ch.Cancel(tag, false); err != nil {
+				log.Printf("Failed to cancel consumer %s: %v", tag, err)
+			}
+		}
+	}
+	log.Printf("Paused consuming messages from queue: %s", queueName)
+	return nil
+}
+
+// Resume consumes a paused queue again. If the broker is unreachable the error is
+// returned, but the queue is no longer paused and is consumed once the connection is
+// re-established.
+func (c *Consumer) Resume(queueName string) error {
+	c.mu.Lock()
+	q := c.queue(queueName)
+	if q == nil {
+		c.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrUnknownQueue, queueName)
+	}
+	if !q.paused {
+		c.mu.Unlock()
+		return nil
+	}
+	q.paused = false
+	c.mu.Unlock()
+	metrics.ConsumerPaused.WithLabelValues(queueName).Set(0)
+
+	if err := c.consume(q); err != nil {
 		return err
 	}
+	log.Printf("Resumed consuming messages from queue: %s", queueName)
+	return nil
+}
+
+// queue returns the state of a supervised queue, or nil. The caller holds c.mu.
+func (c *Consumer) queue(name string) *queueState {
+	for _, q := range c.queues {
+		if q.name == name {
+			return q
+		}
+	}
+	return nil
+}
+
+// restart consumes every supervised queue that is not paused again after a reconnection.
+func (c *Consumer) restart() error {
+	c.mu.Lock()
+	var queues []*queueState
+	for _, q := range c.queues {
+		if !q.paused {
+			queues = append(queues, q)
+		}
+	}
+	c.mu.Unlock()
+
+	var errs []error
+	for _, q := range queues {
+		if err := c.consume(q); err != nil {
+			errs = append(errs, fmt.Errorf("consuming %s: %w", q.name, err))
+			continue
+		}
+		log.Printf("Resumed consuming messages from queue: %s", q.name)
+	}
+	return errors.Join(errs...)
+}
+
+// consume registers a consumer for the queue and processes its deliveries in the background.
+func (c *Consumer) consume(q *queueState) error {
+	ch, err := c.conn.sharedChannel()
+	if err == nil {
+		tag := consumerTag(q.name)
+		var msgs <-chan amqp.Delivery
+		msgs, err = ch.Consume(
+			q.name, // queue
+			tag,    // consumer
+			false,  // auto-ack (manual ack)
+			false,  // exclusive
+			false,  // no-local
+			false,  // no-wait
+			nil,    // args
+		)
+		if err == nil {
+			if !c.setRegistered(q, tag, "") {
+				// Paused while registering.
+				ch.Cancel(tag, false)
+			}
+			go c.run(q, tag, msgs)
+			return nil
+		}
+	}
+	c.setRegistered(q, "", err.Error())
+	return err
+}
 
+// setRegistered records a queue's registration state; an empty tag means unregistered.
+// It reports false, recording nothing, when registering a queue that has been paused.
+func (c *Consumer) setRegistered(q *queueState, tag, lastError string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if tag != "" && q.paused {
+		return false
+	}
+	q.registered = tag != ""
+	q.consumerTag = tag
+	q.since = time.Now()
+	if lastError != "" {
+		q.lastError = lastError
+	}
+	return true
+}
+
+// run processes deliveries until the broker closes the delivery channel.
+func (c *Consumer) run(q *queueState, tag string, msgs <-chan amqp.Delivery) {
+	queueName := q.name
+	prefetch := c.conn.Prefetch()
+	metrics.ConsumerPrefetch.WithLabelValues(queueName).Set(float64(prefetch))
+	var unacked atomic.Int64
+	addUnacked := func(delta int64) {
+		n := unacked.Add(delta)
+		metrics.ConsumerUnacked.WithLabelValues(queueName).Set(float64(n))
+		if prefetch > 0 {
+			metrics.ConsumerPrefetchUtilization.WithLabelValues(queueName).Set(float64(n) / float64(prefetch))
+		}
+	}
+
+	// Messages are processed one at a time, but the broker pushes up to prefetch of them
+	// ahead. Read them into our own buffer as they arrive, instead of leaving them in the
+	// client library where they cannot be counted, so the unacked gauge reflects what
+	// the broker has delivered and is waiting on.
+	buffered := make(chan amqp.Delivery, max(prefetch, 1))
 	go func() {
+		defer close(buffered)
 		for d := range msgs {
-			log.Printf("Received message from queue '%s' (Routing Key: %s)", queueName, d.RoutingKey)
-
-			if err := c.handler(d.Body); err != nil {
-				log.Printf("Error processing message: %v. Re-queueing.", err)
-				d.Nack(false, true)
-			} else {
-				d.Ack(false)
+			metrics.MessagesReceived.WithLabelValues(queueName, d.RoutingKey).Inc()
+			if d.Redelivered {
+				metrics.MessagesRedelivered.WithLabelValues(queueName, d.RoutingKey).Inc()
 			}
+			addUnacked(1)
+			buffered <- d
 		}
 	}()
 
-	log.Printf("Started consuming messages from queue: %s", queueName)
-	return nil
+	for d := range buffered {
+		if ch, ok := d.Acknowledger.(*amqp.Channel); ok && ch.IsClosed() {
+			// The broker requeued it when the channel closed; it cannot be acknowledged now.
+			addUnacked(-1)
+			continue
+		}
+		if c.isPaused(q) {
+			// Delivered before the consumer was cancelled; leave it for after the resume.
+			d.Nack(false, true)
+			metrics.MessagesNacked.WithLabelValues(queueName, d.RoutingKey, "true").Inc()
+			addUnacked(-1)
+			continue
+		}
+
+		delivery := deliveryFrom(d, queueName)
+		ctx, span := startSpan(delivery)
+		ctx = logging.With(ctx,
+			slog.String(logging.KeyQueue, queueName),
+			slog.String(logging.KeyRoutingKey, d.RoutingKey),
+			slog.Uint64(logging.KeyDeliveryTag, d.DeliveryTag))
+		slog.DebugContext(ctx, "Received message", "redelivered", d.Redelivered)
+
+		err := c.handler(ctx, delivery)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+
+		if err != nil {
+			c.fail(ctx, d, queueName, err)
+		} else {
+			if c.deadLetters != nil {
+				c.deadLetters.forget(d)
+			}
+			d.Ack(false)
+			metrics.MessagesAcked.WithLabelValues(queueName, d.RoutingKey).Inc()
+		}
+		addUnacked(-1)
+	}
+
+	// The channel or connection closed, or the consumer was cancelled.
+	c.mu.Lock()
+	if q.consumerTag == tag {
+		q.registered = false
+		q.consumerTag = ""
+		q.since = time.Now()
+	}
+	c.mu.Unlock()
+	slog.Warn("Stopped consuming messages", logging.KeyQueue, queueName)
+}
+
+// isPaused reports whether the queue is paused.
+func (c *Consumer) isPaused(q *queueState) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return q.paused
+}
+
+// fail settles a delivery whose handler returned err: it is re-queued, or moved to the
+// dead letter queue when err is permanent or the message has run out of attempts.
+func (c *Consumer) fail(ctx context.Context, d amqp.Delivery, queueName string, err error) {
+	permanent := errors.Is(err, ErrDeadLetter)
+	if c.deadLetters == nil {
+		if permanent {
+			slog.ErrorContext(ctx, "Error processing message; rejecting", "error", err)
+			metrics.MessagesDeadLettered.WithLabelValues(queueName, d.RoutingKey).Inc()
+		} else {
+			slog.ErrorContext(ctx, "Error processing message; re-queueing", "error", err)
+		}
+		d.Nack(false, !permanent)
+		metrics.MessagesNacked.WithLabelValues(queueName, d.RoutingKey, strconv.FormatBool(!permanent)).Inc()
+		return
+	}
+
+	attempts, exhausted := c.deadLetters.recordFailure(d)
+	if !permanent && !exhausted {
+		slog.ErrorContext(ctx, "Error processing message; re-queueing", "error", err, "attempts", attempts)
+		d.Nack(false, true)
+		metrics.MessagesNacked.WithLabelValues(queueName, d.RoutingKey, "true").Inc()
+		return
+	}
+
+	publishCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if pubErr := c.deadLetters.publish(publishCtx, d, queueName, err, attempts); pubErr != nil {
+		slog.ErrorContext(ctx, "Failed to dead-letter message; re-queueing",
+			"error", err, "publish_error", pubErr, "attempts", attempts)
+		d.Nack(false, true)
+		metrics.MessagesNacked.WithLabelValues(queueName, d.RoutingKey, "true").Inc()
+		return
+	}
+	slog.ErrorContext(ctx, "Error processing message; dead-lettered",
+		"error", err, "attempts", attempts, "dead_letter_queue", c.deadLetters.Name())
+	c.deadLetters.forget(d)
+	d.Ack(false)
+	metrics.MessagesDeadLettered.WithLabelValues(queueName, d.RoutingKey).Inc()
+}
+
+// consumerTag returns a unique consumer tag naming the service and queue.
+func consumerTag(queue string) string {
+	return constants.ServiceName + "." + queue + "." + NewMessageID()[:8]
+}
+
+// startSpan starts the processing span for a delivery as a child of the publisher's span.
+func startSpan(d Delivery) (context.Context, trace.Span) {
+	ctx := tracing.Extract(context.Background(), d.Headers)
+	return tracing.Tracer().Start(ctx, d.Queue+" process",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			semconv.MessagingSystemRabbitmq,
+			semconv.MessagingOperationTypeDeliver,
+			semconv.MessagingDestinationName(d.Queue),
+			semconv.MessagingRabbitmqDestinationRoutingKey(d.RoutingKey),
+			semconv.MessagingMessageID(d.MessageID),
+			semconv.MessagingRabbitmqMessageDeliveryTag(int(d.DeliveryTag)),
+			attribute.Bool("messaging.rabbitmq.redelivered", d.Redelivered),
+		))
 }
@@ -0,0 +1,149 @@
+// rabbitmq/deadletter.go
+package rabbitmq
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// Headers added to dead-lettered messages, describing where they came from and why they failed.
+const (
+	HeaderOriginalExchange   = "x-original-exchange"
+	HeaderOriginalRoutingKey = "x-original-routing-key"
+	HeaderOriginalQueue      = "x-original-queue"
+	HeaderFailureReason      = "x-failure-reason"
+	HeaderFailedAt           = "x-failed-at" // RFC 3339
+	HeaderDeliveryAttempts   = "x-delivery-attempts"
+)
+
+// DeadLetter marks err as permanent, like wrapping ErrDeadLetter, and adds headers to
+// the copy of the message moved to the dead letter queue.
+func DeadLetter(err error, headers map[string]interface{}) error {
+	return &deadLetterError{err: err, headers: headers}
+}
+
+// deadLetterError is a permanent handler error carrying dead-letter headers.
+type deadLetterError struct {
+	err     error
+	headers map[string]interface{}
+}
+
+func (e *deadLetterError) Error() string {
+	return ErrDeadLetter.Error() + ": " + e.err.Error()
+}
+
+func (e *deadLetterError) Unwrap() []error {
+	return []error{ErrDeadLetter, e.err}
+}
+
+// maxTrackedFailures bounds the in-memory failure counts. When it is reached the counts
+// are reset, which at worst gives some messages a few extra attempts.
+const maxTrackedFailures = 10000
+
+// DeadLetterQueue receives messages a Consumer gives up on: those whose handler error wraps
+// ErrDeadLetter, and those that failed maxAttempts times. Failed attempts are counted in
+// memory per message, because classic queues do not count redeliveries.
+type DeadLetterQueue struct {
+	queue       string
+	maxAttempts int
+	publisher   *Publisher
+
+	mu       sync.Mutex
+	failures map[string]int
+}
+
+// NewDeadLetterQueue declares the durable dead letter queue and opens a publisher for it.
+func NewDeadLetterQueue(conn *Connection, queue string, maxAttempts int) (*DeadLetterQueue, error) {
+	if _, err := conn.DeclareQueue(queue); err != nil {
+		return nil, err
+	}
+	// The default exchange routes by queue name, so no binding is needed.
+	publisher, err := NewPublisher(conn, "")
+	if err != nil {
+		return nil, err
+	}
+	return &DeadLetterQueue{
+		queue:       queue,
+		maxAttempts: maxAttempts,
+		publisher:   publisher,
+		failures:    make(map[string]int),
+	}, nil
+}
+
+// Name returns the dead letter queue's name.
+func (q *DeadLetterQueue) Name() string {
+	return q.queue
+}
+
+// Close closes the queue's publisher.
+func (q *DeadLetterQueue) Close() error {
+	return q.publisher.Close()
+}
+
+// recordFailure counts a failed attempt and returns the attempts so far and whether
+// the message has run out of them.
+func (q *DeadLetterQueue) recordFailure(d amqp.Delivery) (int, bool) {
+	key := failureKey(d)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.failures) >= maxTrackedFailures {
+		q.failures = make(map[string]int)
+	}
+	q.failures[key]++
+	attempts := q.failures[key]
+	return attempts, q.maxAttempts > 0 && attempts >= q.maxAttempts
+}
+
+// forget drops the failure count of a message that was processed or dead-lettered.
+func (q *DeadLetterQueue) forget(d amqp.Delivery) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	delete(q.failures, failureKey(d))
+}
+
+// publish copies the delivery to the dead letter queue with the failure headers,
+// plus any headers the handler error added with DeadLetter.
+func (q *DeadLetterQueue) publish(ctx context.Context, d amqp.Delivery, queue string, cause error, attempts int) error {
+	headers := make(map[string]interface{}, len(d.Headers)+6)
+	for key, value := range d.Headers {
+		headers[key] = value
+	}
+	var dlErr *deadLetterError
+	if errors.As(cause, &dlErr) {
+		for key, value := range dlErr.headers {
+			headers[key] = value
+		}
+	}
+	headers[HeaderOriginalExchange] = d.Exchange
+	headers[HeaderOriginalRoutingKey] = d.RoutingKey
+	headers[HeaderOriginalQueue] = queue
+	headers[HeaderFailureReason] = cause.Error()
+	headers[HeaderFailedAt] = time.Now().UTC().Format(time.RFC3339)
+	headers[HeaderDeliveryAttempts] = strconv.Itoa(attempts)
+
+	return q.publisher.Publish(ctx, Message{
+		RoutingKey:    q.queue,
+		Body:          d.Body,
+		ContentType:   d.ContentType,
+		MessageID:     d.MessageId,
+		CorrelationID: d.CorrelationId,
+		Headers:       headers,
+		Mandatory:     true,
+	})
+}
+
+// failureKey identifies a message across redeliveries: its message ID, or a hash of its body.
+func failureKey(d amqp.Delivery) string {
+	if d.MessageId != "" {
+		return d.MessageId
+	}
+	sum := sha256.Sum256(d.Body)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,295 @@
+// rabbitmq/publisher.go
+package rabbitmq
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"notification-service/cloudevents"
+	"notification-service/constants"
+	"notification-service/envelope"
+	"notification-service/models"
+	"notification-service/tracing"
+)
+
+// confirmTimeout bounds how long EventPublisher waits for the broker to confirm a message.
+const confirmTimeout = 5 * time.Second
+
+var (
+	// ErrNacked is returned when the broker refuses to take responsibility for a message.
+	ErrNacked = errors.New("broker nacked published message")
+	// ErrUnroutable is returned when a mandatory message matched no queue.
+	ErrUnroutable = errors.New("published message was not routed to any queue")
+	// ErrPublisherClosed is returned after the publisher's channel has closed.
+	ErrPublisherClosed = errors.New("publisher channel is closed")
+)
+
+// Message is a single message to publish.
+type Message struct {
+	RoutingKey    string
+	Body          []byte
+	ContentType   string // Defaults to application/json
+	MessageID     string // Generated if empty
+	CorrelationID string
+	Headers       map[string]interface{}
+	// Mandatory asks the broker to return the message if no queue is bound for
+	// its routing key; Publish then fails with ErrUnroutable.
+	Mandatory bool
+}
+
+// Publisher publishes persistent messages on its own confirm-mode channel. Publish
+// only returns nil once the broker has confirmed the message (and, for mandatory
+// messages, routed it to at least one queue).
+type Publisher struct {
+	conn     *Connection
+	exchange string
+	mu       sync.Mutex // One message in flight at a time, so returns and confirms match it
+	channel  *amqp.Channel
+	returns  chan amqp.Return
+	closed   chan *amqp.Error
+}
+
+// NewPublisher opens a confirm-mode channel for publishing to the given exchange
+func NewPublisher(conn *Connection, exchange string) (*Publisher, error) {
+	p := &Publisher{conn: conn, exchange: exchange}
+	if err := p.open(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// open opens the publisher's confirm-mode channel. The caller holds p.mu, or owns p.
+func (p *Publisher) open() error {
+	ch, err := p.conn.openChannel()
+	if err != nil {
+		return err
+	}
+	if err := ch.Confirm(false); err != nil {
+		ch.Close()
+		return fmt.Errorf("enabling publisher confirms: %w", err)
+	}
+
+	p.channel = ch
+	// The broker sends basic.return before the matching basic.ack, so with
+	// a buffer the return is always waiting by the time the confirm arrives.
+	p.returns = ch.NotifyReturn(make(chan amqp.Return, 1))
+	p.closed = ch.NotifyClose(make(chan *amqp.Error, 1))
+	return nil
+}
+
+// Publish publishes the message and waits for the broker to confirm it
+func (p *Publisher) Publish(ctx context.Context, m Message) error {
+	if m.ContentType == "" {
+		m.ContentType = "application/json"
+	}
+	if m.MessageID == "" {
+		m.MessageID = NewMessageID()
+	}
+
+	ctx, span := tracing.Tracer().Start(ctx, p.exchange+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystemRabbitmq,
+			semconv.MessagingOperationTypePublish,
+			semconv.MessagingDestinationName(p.exchange),
+			semconv.MessagingRabbitmqDestinationRoutingKey(m.RoutingKey),
+			semconv.MessagingMessageID(m.MessageID),
+		))
+	defer span.End()
+	err := p.publish(ctx, m)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
+
+// publish sends the message with the span context of ctx in its headers.
+func (p *Publisher) publish(ctx context.Context, m Message) error {
+	headers := make(map[string]interface{}, len(m.Headers)+2)
+	for key, value := range m.Headers {
+		headers[key] = value
+	}
+	tracing.Inject(ctx, headers)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	select {
+	case <-p.closed:
+		// The channel or connection closed; reopen it, e.g. after a reconnection.
+		if err := p.open(); err != nil {
+			return fmt.Errorf("%w: %v", ErrPublisherClosed, err)
+		}
+	default:
+	}
+	p.drainReturns()
+
+	confirmation, err := p.channel.PublishWithDeferredConfirmWithContext(ctx,
+		p.exchange,   // exchange
+		m.RoutingKey, // routing key
+		m.Mandatory,  // mandatory
+		false,        // immediate
+		amqp.Publishing{
+			Headers:       amqp.Table(headers),
+			ContentType:   m.ContentType,
+			DeliveryMode:  amqp.Persistent,
+			MessageId:     m.MessageID,
+			CorrelationId: m.CorrelationID,
+			Timestamp:     time.Now(),
+			Body:          m.Body,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		return fmt.Errorf("waiting for publisher confirm: %w", err)
+	}
+	if !acked {
+		return ErrNacked
+	}
+
+	select {
+	case ret := <-p.returns:
+		if ret.MessageId == m.MessageID {
+			return fmt.Errorf("%w (Routing key: %s, Reply: %d %s)", ErrUnroutable, m.RoutingKey, ret.ReplyCode, ret.ReplyText)
+		}
+		log.Printf("Ignoring stale returned message %s (Routing key: %s)", ret.MessageId, ret.RoutingKey)
+	default:
+	}
+
+	log.Printf("Published message %s to exchange '%s' with routing key '%s'", m.MessageID, p.exchange, m.RoutingKey)
+	return nil
+}
+
+// PublishJSON marshals v to JSON and publishes it with the given routing key
+func (p *Publisher) PublishJSON(ctx context.Context, routingKey string, v interface{}, mandatory bool) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return p.Publish(ctx, Message{
+		RoutingKey: routingKey,
+		Body:       body,
+		Mandatory:  mandatory,
+	})
+}
+
+// PublishNotification publishes a notification, in the latest envelope schema version,
+// with the routing key NestJS uses for its type, as a mandatory message so one that
+// would reach no queue fails loudly. The message's EventID is used as the AMQP message
+// ID, and one is assigned if it is empty. It returns the event ID.
+func (p *Publisher) PublishNotification(ctx context.Context, msg models.NotificationMessage) (string, error) {
+	routingKey, ok := constants.NotificationRoutingKeys[msg.NotificationType]
+	if !ok {
+		return "", fmt.Errorf("no routing key for notification type %q", msg.NotificationType)
+	}
+	if msg.EventID == "" {
+		msg.EventID = NewMessageID()
+	}
+
+	body, err := envelope.Encode(msg)
+	if err != nil {
+		return "", err
+	}
+	err = p.Publish(ctx, Message{
+		RoutingKey: routingKey,
+		Body:       body,
+		MessageID:  msg.EventID,
+		Mandatory:  true,
+	})
+	if err != nil {
+		return "", err
+	}
+	return msg.EventID, nil
+}
+
+// Close closes the publisher's channel
+func (p *Publisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.channel.Close()
+}
+
+// drainReturns discards returns left over from publishes whose context expired.
+func (p *Publisher) drainReturns() {
+	for {
+		select {
+		case <-p.returns:
+		default:
+			return
+		}
+	}
+}
+
+// NewMessageID returns a random message ID.
+func NewMessageID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// EventPublisher publishes events from this service back to an exchange as CloudEvents.
+// The routing key is the event type and the AMQP message ID is the event ID.
+type EventPublisher struct {
+	publisher *Publisher
+	mode      string // cloudevents.ModeBinary or cloudevents.ModeStructured
+}
+
+// NewEventPublisher creates an event publisher backed by its own confirm-mode Publisher
+// that writes events in the given CloudEvents mode
+func NewEventPublisher(conn *Connection, exchange, mode string) (*EventPublisher, error) {
+	if !cloudevents.ValidMode(mode) {
+		return nil, fmt.Errorf("unknown CloudEvents mode %q", mode)
+	}
+	publisher, err := NewPublisher(conn, exchange)
+	if err != nil {
+		return nil, err
+	}
+	return &EventPublisher{publisher: publisher, mode: mode}, nil
+}
+
+// Publish wraps the event in a CloudEvent, publishes it with the given routing key
+// and waits for the broker to confirm it
+func (p *EventPublisher) Publish(routingKey string, event interface{}) error {
+	e, err := cloudevents.New(NewMessageID(), constants.CloudEventSource, routingKey, event)
+	if err != nil {
+		return err
+	}
+	// Events are fire-and-forget for NestJS; an event nobody has bound a queue for yet is not an error.
+	m := Message{RoutingKey: routingKey, MessageID: e.ID}
+	if p.mode == cloudevents.ModeStructured {
+		m.ContentType = cloudevents.ContentType
+		if m.Body, err = json.Marshal(e); err != nil {
+			return err
+		}
+	} else {
+		m.ContentType = e.DataContentType
+		m.Headers = e.Headers()
+		m.Body = e.Data
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), confirmTimeout)
+	defer cancel()
+	return p.publisher.Publish(ctx, m)
+}
+
+// Close closes the publisher's channel
+func (p *EventPublisher) Close() error {
+	return p.publisher.Close()
+}
@@ -0,0 +1,52 @@
+// rabbitmq/tail.go
+package rabbitmq
+
+import (
+	"context"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// Tail calls fn with a copy of every message published to the exchange with one of
+// the routing keys, until ctx is done or the connection closes. The copies go to a
+// temporary exclusive queue, so the queues the service consumes are left untouched.
+func (c *Connection) Tail(ctx context.Context, exchange string, routingKeys []string, fn func(d Delivery)) error {
+	ch, err := c.openChannel()
+	if err != nil {
+		return err
+	}
+	defer ch.Close()
+
+	queue, err := ch.QueueDeclare(
+		"",    // name: generated by the broker
+		false, // durable
+		true,  // delete when unused
+		true,  // exclusive
+		false, // no-wait
+		amqp.Table{"x-queue-type": "classic"},
+	)
+	if err != nil {
+		return err
+	}
+	for _, routingKey := range routingKeys {
+		if err := ch.QueueBind(queue.Name, routingKey, exchange, false, nil); err != nil {
+			return err
+		}
+	}
+
+	msgs, err := ch.Consume(queue.Name, "", true, true, false, false, nil)
+	if err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case d, ok := <-msgs:
+			if !ok {
+				return ErrNotConnected
+			}
+			fn(deliveryFrom(d, queue.Name))
+		}
+	}
+}
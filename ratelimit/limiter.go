@@ -0,0 +1,162 @@
+// ratelimit/limiter.go
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"notification-service/models"
+)
+
+// pruneAfter is how long idle bucket state is kept. It must exceed every Limit.Per
+// and CollapseWindow, or caps would reset early.
+const pruneAfter = 24 * time.Hour
+
+// Limiter enforces per-recipient, per-type frequency caps.
+type Limiter struct {
+	policies map[string]Policy
+	store    BucketStore
+	log      SuppressionLog
+}
+
+// NewLimiter creates a limiter for the given per-type policies
+func NewLimiter(policies map[string]Policy, store BucketStore, suppressionLog SuppressionLog) *Limiter {
+	return &Limiter{
+		policies: policies,
+		store:    store,
+		log:      suppressionLog,
+	}
+}
+
+// Reservation is the capacity Reserve took for one send. Commit it once the
+// notification went out, or Cancel it so a retry is not capped by the failed attempt.
+type Reservation struct {
+	limiter     *Limiter
+	sentKey     string
+	collapseKey string
+	bucketKey   string
+	limit       Limit
+	at          time.Time
+}
+
+// Reserve reports whether the message may be sent to its recipient on channel and,
+// if not, why. An allowed message holds a token and the collapse marker until the
+// returned Reservation is committed or cancelled. Suppressed notifications are
+// recorded in the suppression log.
+func (l *Limiter) Reserve(msg models.NotificationMessage, channel string) (*Reservation, bool, string) {
+	r := &Reservation{limiter: l}
+	policy, ok := l.policies[msg.NotificationType]
+	if !ok {
+		return r, true, ""
+	}
+	now := time.Now()
+	userID := msg.Recipient.UserID
+
+	// A redelivered event that already went out on this channel (e.g. to some of the
+	// recipient's devices) was charged then; the retry must not be capped by itself.
+	if msg.EventID != "" {
+		r.sentKey = fmt.Sprintf("sent|%s|%s", msg.EventID, channel)
+		sent, err := l.store.Marked(r.sentKey, pruneAfter, now)
+		if err != nil {
+			log.Printf("Rate limit store error (allowing notification): %v", err)
+			return r, true, ""
+		}
+		if sent {
+			r.sentKey = ""
+			return r, true, ""
+		}
+	}
+
+	if policy.CollapseWindow > 0 && msg.Payload.OpportunityID != 0 {
+		key := fmt.Sprintf("collapse|%s|%s|%s|%d", userID, msg.NotificationType, channel, msg.Payload.OpportunityID)
+		collapsed, err := l.store.Collapse(key, policy.CollapseWindow, now)
+		if err != nil {
+			log.Printf("Rate limit store error (allowing notification): %v", err)
+			return r, true, ""
+		}
+		if collapsed {
+			l.suppress(msg, channel, ReasonCollapsed, now)
+			return nil, false, ReasonCollapsed
+		}
+		r.collapseKey = key
+		r.at = now
+	}
+
+	if limit, ok := policy.Limits[channel]; ok {
+		key := fmt.Sprintf("bucket|%s|%s|%s", userID, msg.NotificationType, channel)
+		allowed, err := l.store.Take(key, limit, now)
+		if err != nil {
+			log.Printf("Rate limit store error (allowing notification): %v", err)
+			return r, true, ""
+		}
+		if !allowed {
+			r.Cancel()
+			l.suppress(msg, channel, ReasonRateLimited, now)
+			return nil, false, ReasonRateLimited
+		}
+		r.bucketKey = key
+		r.limit = limit
+	}
+
+	return r, true, ""
+}
+
+// Commit keeps the reserved capacity because the notification was sent
+func (r *Reservation) Commit() {
+	if r.sentKey == "" {
+		return
+	}
+	if err := r.limiter.store.Mark(r.sentKey, time.Now()); err != nil {
+		log.Printf("Rate limit store error (recording sent notification): %v", err)
+	}
+}
+
+// Cancel returns the reserved token and collapse marker because nothing was sent
+func (r *Reservation) Cancel() {
+	if r.bucketKey != "" {
+		if err := r.limiter.store.Refund(r.bucketKey, r.limit); err != nil {
+			log.Printf("Rate limit store error (refunding token): %v", err)
+		}
+	}
+	if r.collapseKey != "" {
+		if err := r.limiter.store.Unmark(r.collapseKey, r.at); err != nil {
+			log.Printf("Rate limit store error (releasing collapse marker): %v", err)
+		}
+	}
+}
+
+// Run prunes idle bucket state until ctx is cancelled
+func (l *Limiter) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			l.store.Prune(now.Add(-pruneAfter))
+		}
+	}
+}
+
+func (l *Limiter) suppress(msg models.NotificationMessage, channel, reason string, now time.Time) {
+	log.Printf("Suppressed %s %s for %s (Reason: %s, OpportunityID: %d)",
+		channel, msg.NotificationType, msg.Recipient.UserID, reason, msg.Payload.OpportunityID)
+
+	err := l.log.Record(Suppression{
+		UserID:           msg.Recipient.UserID,
+		NotificationType: msg.NotificationType,
+		Channel:          channel,
+		Reason:           reason,
+		OpportunityID:    msg.Payload.OpportunityID,
+		ApplicationID:    msg.Payload.ApplicationID,
+		Title:            msg.Payload.Title,
+		SuppressedAt:     now.UTC(),
+	})
+	if err != nil {
+		log.Printf("Failed to record suppressed notification for %s: %v", msg.Recipient.UserID, err)
+	}
+}
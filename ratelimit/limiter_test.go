@@ -0,0 +1,90 @@
+// ratelimit/limiter_test.go
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"notification-service/models"
+)
+
+func newTestLimiter() *Limiter {
+	policies := map[string]Policy{
+		"OPPORTUNITY_UPDATED": {
+			Limits:         map[string]Limit{"push": {Count: 1, Per: time.Hour}},
+			CollapseWindow: 10 * time.Minute,
+		},
+	}
+	return NewLimiter(policies, NewMemoryBucketStore(), NewMemorySuppressionLog(10))
+}
+
+func testMessage(eventID string, opportunityID int) models.NotificationMessage {
+	msg := models.NotificationMessage{NotificationType: "OPPORTUNITY_UPDATED", EventID: eventID}
+	msg.Recipient.UserID = "user-1"
+	msg.Payload.OpportunityID = opportunityID
+	return msg
+}
+
+func TestReserve(t *testing.T) {
+	tests := []struct {
+		name       string
+		first      func(r *Reservation)
+		second     models.NotificationMessage
+		wantAllow  bool
+		wantReason string
+	}{
+		{
+			name:      "retry after failed send is neither collapsed nor capped",
+			first:     (*Reservation).Cancel,
+			second:    testMessage("evt-1", 7),
+			wantAllow: true,
+		},
+		{
+			name:      "redelivery of a sent event is not charged again",
+			first:     (*Reservation).Commit,
+			second:    testMessage("evt-1", 7),
+			wantAllow: true,
+		},
+		{
+			name:       "repeat for the same opportunity is collapsed",
+			first:      (*Reservation).Commit,
+			second:     testMessage("evt-2", 7),
+			wantReason: ReasonCollapsed,
+		},
+		{
+			name:       "other opportunity is capped once the bucket is empty",
+			first:      (*Reservation).Commit,
+			second:     testMessage("evt-2", 8),
+			wantReason: ReasonRateLimited,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newTestLimiter()
+			r, allowed, _ := l.Reserve(testMessage("evt-1", 7), "push")
+			if !allowed {
+				t.Fatal("first notification was not allowed")
+			}
+			tt.first(r)
+
+			_, allowed, reason := l.Reserve(tt.second, "push")
+			if allowed != tt.wantAllow || reason != tt.wantReason {
+				t.Errorf("Reserve = %v, %q; want %v, %q", allowed, reason, tt.wantAllow, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestReserveUncappedType(t *testing.T) {
+	l := newTestLimiter()
+	msg := testMessage("evt-1", 7)
+	msg.NotificationType = "NGO_NEW_APPLICATION"
+	for i := 0; i < 3; i++ {
+		r, allowed, _ := l.Reserve(msg, "push")
+		if !allowed {
+			t.Fatalf("uncapped notification %d was suppressed", i)
+		}
+		r.Commit()
+	}
+}
@@ -0,0 +1,109 @@
+// ratelimit/policy.go
+package ratelimit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"notification-service/preferences"
+)
+
+// Limit caps how many notifications a recipient gets on a channel, e.g. 3 per hour.
+type Limit struct {
+	Count int
+	Per   time.Duration
+}
+
+// Policy is the frequency capping configuration for one notification type.
+type Policy struct {
+	// Limits maps a channel ("email", "push") to its cap. Channels without one are uncapped.
+	Limits map[string]Limit
+	// CollapseWindow suppresses repeats for the same opportunity within the window.
+	// Zero disables collapsing.
+	CollapseWindow time.Duration
+}
+
+// ParsePolicies builds per-type policies from two config strings:
+//
+//	limits:   "OPPORTUNITY_UPDATED.push=3/1h,OPPORTUNITY_UPDATED.email=3/1h"
+//	collapse: "OPPORTUNITY_UPDATED=10m"
+//
+// Channels must be email or push, and windows shorter than pruneAfter (24h), as
+// idle bucket state is pruned after that.
+func ParsePolicies(limits, collapse string) (map[string]Policy, error) {
+	policies := make(map[string]Policy)
+	policy := func(notificationType string) Policy {
+		p, ok := policies[notificationType]
+		if !ok {
+			p = Policy{Limits: make(map[string]Limit)}
+		}
+		return p
+	}
+
+	for _, entry := range splitList(limits) {
+		key, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid rate limit %q (expected TYPE.channel=count/duration)", entry)
+		}
+		notificationType, channel, ok := strings.Cut(strings.TrimSpace(key), ".")
+		if !ok {
+			return nil, fmt.Errorf("invalid rate limit key %q (expected TYPE.channel)", key)
+		}
+		if !preferences.ValidChannel(channel) {
+			return nil, fmt.Errorf("invalid rate limit channel %q (expected %s or %s)",
+				channel, preferences.ChannelEmail, preferences.ChannelPush)
+		}
+		countStr, perStr, ok := strings.Cut(strings.TrimSpace(value), "/")
+		if !ok {
+			return nil, fmt.Errorf("invalid rate limit value %q (expected count/duration)", value)
+		}
+		count, err := strconv.Atoi(countStr)
+		if err != nil || count <= 0 {
+			return nil, fmt.Errorf("invalid rate limit count %q", countStr)
+		}
+		per, err := time.ParseDuration(perStr)
+		if err != nil || per <= 0 {
+			return nil, fmt.Errorf("invalid rate limit duration %q", perStr)
+		}
+		if per >= pruneAfter {
+			return nil, fmt.Errorf("rate limit duration %q must be shorter than %s", perStr, pruneAfter)
+		}
+
+		p := policy(notificationType)
+		p.Limits[channel] = Limit{Count: count, Per: per}
+		policies[notificationType] = p
+	}
+
+	for _, entry := range splitList(collapse) {
+		notificationType, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid collapse window %q (expected TYPE=duration)", entry)
+		}
+		window, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil || window < 0 {
+			return nil, fmt.Errorf("invalid collapse window duration %q", value)
+		}
+		if window >= pruneAfter {
+			return nil, fmt.Errorf("collapse window %q must be shorter than %s", value, pruneAfter)
+		}
+
+		notificationType = strings.TrimSpace(notificationType)
+		p := policy(notificationType)
+		p.CollapseWindow = window
+		policies[notificationType] = p
+	}
+
+	return policies, nil
+}
+
+func splitList(raw string) []string {
+	var out []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
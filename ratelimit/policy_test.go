@@ -0,0 +1,42 @@
+// ratelimit/policy_test.go
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParsePolicies(t *testing.T) {
+	tests := []struct {
+		name     string
+		limits   string
+		collapse string
+		wantErr  bool
+	}{
+		{"valid", "OPPORTUNITY_UPDATED.push=3/1h, OPPORTUNITY_UPDATED.email=3/1h", "OPPORTUNITY_UPDATED=10m", false},
+		{"empty", "", "", false},
+		{"unknown channel", "OPPORTUNITY_UPDATED.sms=3/1h", "", true},
+		{"missing channel", "OPPORTUNITY_UPDATED=3/1h", "", true},
+		{"zero count", "OPPORTUNITY_UPDATED.push=0/1h", "", true},
+		{"limit window of a day", "OPPORTUNITY_UPDATED.push=3/24h", "", true},
+		{"collapse window of a day", "", "OPPORTUNITY_UPDATED=24h", true},
+		{"negative collapse window", "", "OPPORTUNITY_UPDATED=-1m", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParsePolicies(tt.limits, tt.collapse)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePolicies(%q, %q) error = %v, wantErr %v", tt.limits, tt.collapse, err, tt.wantErr)
+			}
+		})
+	}
+
+	policies, err := ParsePolicies("OPPORTUNITY_UPDATED.push=3/1h", "OPPORTUNITY_UPDATED=10m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := policies["OPPORTUNITY_UPDATED"]
+	if p.Limits["push"] != (Limit{Count: 3, Per: time.Hour}) || p.CollapseWindow != 10*time.Minute {
+		t.Fatalf("policy = %+v, want 3/1h push and a 10m collapse window", p)
+	}
+}
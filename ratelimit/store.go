@@ -0,0 +1,144 @@
+// ratelimit/store.go
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// BucketStore holds token buckets and collapse markers keyed by recipient/type/channel.
+type BucketStore interface {
+	// Take removes one token from the bucket for key, refilling it at limit.Count per
+	// limit.Per. It reports whether a token was available.
+	Take(key string, limit Limit, now time.Time) (bool, error)
+	// Collapse reports whether key was marked within window. If not, it marks key at now.
+	Collapse(key string, window time.Duration, now time.Time) (bool, error)
+	// Refund returns a token taken from the bucket for key, up to limit.Count.
+	Refund(key string, limit Limit) error
+	// Marked reports whether key was marked within window, without marking it.
+	Marked(key string, window time.Duration, now time.Time) (bool, error)
+	// Mark marks key at now.
+	Mark(key string, now time.Time) error
+	// Unmark removes the mark on key if it is still the one made at markedAt.
+	Unmark(key string, markedAt time.Time) error
+	// Prune drops state that has not been touched since before cutoff.
+	Prune(cutoff time.Time)
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// MemoryBucketStore is an in-process BucketStore.
+type MemoryBucketStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	collapsed map[string]time.Time
+}
+
+// NewMemoryBucketStore creates an empty in-memory bucket store
+func NewMemoryBucketStore() *MemoryBucketStore {
+	return &MemoryBucketStore{
+		buckets:   make(map[string]*bucket),
+		collapsed: make(map[string]time.Time),
+	}
+}
+
+// Take removes one token from the bucket for key
+func (s *MemoryBucketStore) Take(key string, limit Limit, now time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	capacity := float64(limit.Count)
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, last: now}
+		s.buckets[key] = b
+	}
+
+	// Refill continuously: Count tokens every Per.
+	elapsed := now.Sub(b.last)
+	if elapsed > 0 {
+		b.tokens += elapsed.Seconds() * capacity / limit.Per.Seconds()
+		if b.tokens > capacity {
+			b.tokens = capacity
+		}
+		b.last = now
+	}
+
+	if b.tokens < 1 {
+		return false, nil
+	}
+	b.tokens--
+	return true, nil
+}
+
+// Collapse reports whether key was marked within window, marking it otherwise
+func (s *MemoryBucketStore) Collapse(key string, window time.Duration, now time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if last, ok := s.collapsed[key]; ok && now.Sub(last) < window {
+		return true, nil
+	}
+	s.collapsed[key] = now
+	return false, nil
+}
+
+// Refund returns one token to the bucket for key
+func (s *MemoryBucketStore) Refund(key string, limit Limit) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if b, ok := s.buckets[key]; ok {
+		b.tokens = min(b.tokens+1, float64(limit.Count))
+	}
+	return nil
+}
+
+// Marked reports whether key was marked within window
+func (s *MemoryBucketStore) Marked(key string, window time.Duration, now time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	last, ok := s.collapsed[key]
+	return ok && now.Sub(last) < window, nil
+}
+
+// Mark marks key at now
+func (s *MemoryBucketStore) Mark(key string, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.collapsed[key] = now
+	return nil
+}
+
+// Unmark removes the mark on key made at markedAt
+func (s *MemoryBucketStore) Unmark(key string, markedAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if last, ok := s.collapsed[key]; ok && last.Equal(markedAt) {
+		delete(s.collapsed, key)
+	}
+	return nil
+}
+
+// Prune drops buckets and markers not touched since cutoff
+func (s *MemoryBucketStore) Prune(cutoff time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, b := range s.buckets {
+		if b.last.Before(cutoff) {
+			delete(s.buckets, key)
+		}
+	}
+	for key, last := range s.collapsed {
+		if last.Before(cutoff) {
+			delete(s.collapsed, key)
+		}
+	}
+}
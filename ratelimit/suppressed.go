@@ -0,0 +1,80 @@
+// ratelimit/suppressed.go
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Reasons a notification was suppressed.
+const (
+	ReasonRateLimited = "rate_limited"
+	ReasonCollapsed   = "collapsed"
+)
+
+// Suppression records a notification that was not sent because of frequency capping.
+type Suppression struct {
+	UserID           string    `json:"user_id"`
+	NotificationType string    `json:"notification_type"`
+	Channel          string    `json:"channel"`
+	Reason           string    `json:"reason"`
+	OpportunityID    int       `json:"opportunity_id,omitempty"`
+	ApplicationID    int       `json:"application_id,omitempty"`
+	Title            string    `json:"title,omitempty"`
+	SuppressedAt     time.Time `json:"suppressed_at"`
+}
+
+// SuppressionLog records suppressed notifications so they are visible rather than silently dropped.
+type SuppressionLog interface {
+	Record(s Suppression) error
+	// ListByUser returns a user's suppressed notifications, newest first.
+	ListByUser(userID string, limit int) ([]Suppression, error)
+}
+
+// MemorySuppressionLog keeps the most recent suppressions in a fixed-size ring.
+type MemorySuppressionLog struct {
+	mu      sync.Mutex
+	entries []Suppression
+	next    int
+	full    bool
+}
+
+// NewMemorySuppressionLog creates a log holding up to size entries
+func NewMemorySuppressionLog(size int) *MemorySuppressionLog {
+	return &MemorySuppressionLog{
+		entries: make([]Suppression, size),
+	}
+}
+
+// Record adds a suppression, overwriting the oldest when full
+func (l *MemorySuppressionLog) Record(s Suppression) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.entries[l.next] = s
+	l.next = (l.next + 1) % len(l.entries)
+	if l.next == 0 {
+		l.full = true
+	}
+	return nil
+}
+
+// ListByUser returns a user's suppressed notifications, newest first
+func (l *MemorySuppressionLog) ListByUser(userID string, limit int) ([]Suppression, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	count := l.next
+	if l.full {
+		count = len(l.entries)
+	}
+
+	var result []Suppression
+	for i := 0; i < count && (limit <= 0 || len(result) < limit); i++ {
+		idx := (l.next - 1 - i + len(l.entries)) % len(l.entries)
+		if l.entries[idx].UserID == userID {
+			result = append(result, l.entries[idx])
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,25 @@
+// scheduler/policy.go
+package scheduler
+
+import "strings"
+
+// Policy says which notification types are urgent enough to bypass quiet hours.
+type Policy struct {
+	urgent map[string]bool
+}
+
+// NewPolicy creates a policy where the listed notification types bypass quiet hours
+func NewPolicy(urgentTypes ...string) *Policy {
+	p := &Policy{urgent: make(map[string]bool)}
+	for _, t := range urgentTypes {
+		if t = strings.TrimSpace(t); t != "" {
+			p.urgent[t] = true
+		}
+	}
+	return p
+}
+
+// BypassesQuietHours reports whether a notification type is delivered even during quiet hours.
+func (p *Policy) BypassesQuietHours(notificationType string) bool {
+	return p.urgent[notificationType]
+}
@@ -0,0 +1,77 @@
+// scheduler/quiet_hours.go
+package scheduler
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"notification-service/models"
+)
+
+// ParseClock parses an "HH:MM" time of day into minutes after midnight.
+func ParseClock(clock string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(clock))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q (expected HH:MM)", clock)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// ValidateQuietHours checks that a quiet hours window and timezone can be evaluated.
+func ValidateQuietHours(qh models.QuietHours, timezone string) error {
+	if _, err := ParseClock(qh.Start); err != nil {
+		return err
+	}
+	if _, err := ParseClock(qh.End); err != nil {
+		return err
+	}
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return fmt.Errorf("invalid timezone %q", timezone)
+	}
+	return nil
+}
+
+// QuietUntil reports whether now falls inside the quiet hours window in the given
+// timezone and, if so, when the window ends. An empty timezone means UTC.
+func QuietUntil(now time.Time, qh models.QuietHours, timezone string) (time.Time, bool, error) {
+	start, err := ParseClock(qh.Start)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	end, err := ParseClock(qh.End)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid timezone %q", timezone)
+	}
+	if start == end {
+		return time.Time{}, false, nil
+	}
+
+	local := now.In(loc)
+	minute := local.Hour()*60 + local.Minute()
+	endOfWindow := func(daysAhead int) time.Time {
+		// Built from the wall clock so the end stays at End local time on DST-change days.
+		return time.Date(local.Year(), local.Month(), local.Day()+daysAhead, end/60, end%60, 0, 0, loc)
+	}
+
+	if start < end {
+		// Same-day window, e.g. 13:00-15:00.
+		if minute >= start && minute < end {
+			return endOfWindow(0), true, nil
+		}
+		return time.Time{}, false, nil
+	}
+
+	// Window wraps past midnight, e.g. 22:00-07:00.
+	if minute >= start {
+		return endOfWindow(1), true, nil
+	}
+	if minute < end {
+		return endOfWindow(0), true, nil
+	}
+	return time.Time{}, false, nil
+}
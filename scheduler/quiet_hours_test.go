@@ -0,0 +1,46 @@
+// scheduler/quiet_hours_test.go
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"notification-service/models"
+)
+
+func TestQuietUntil(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("no tzdata:", err)
+	}
+	night := models.QuietHours{Start: "22:00", End: "07:00"}
+	afternoon := models.QuietHours{Start: "13:00", End: "15:00"}
+
+	tests := []struct {
+		name      string
+		now       time.Time
+		qh        models.QuietHours
+		wantQuiet bool
+		want      time.Time
+	}{
+		{"before wrapping window", time.Date(2025, 6, 1, 21, 59, 0, 0, newYork), night, false, time.Time{}},
+		{"evening in wrapping window", time.Date(2025, 6, 1, 23, 0, 0, 0, newYork), night, true, time.Date(2025, 6, 2, 7, 0, 0, 0, newYork)},
+		{"morning in wrapping window", time.Date(2025, 6, 2, 6, 30, 0, 0, newYork), night, true, time.Date(2025, 6, 2, 7, 0, 0, 0, newYork)},
+		{"window end is exclusive", time.Date(2025, 6, 2, 7, 0, 0, 0, newYork), night, false, time.Time{}},
+		{"same-day window", time.Date(2025, 6, 2, 14, 0, 0, 0, newYork), afternoon, true, time.Date(2025, 6, 2, 15, 0, 0, 0, newYork)},
+		// Clocks go forward at 02:00 on 2025-03-09 and back at 02:00 on 2025-11-02.
+		{"spring forward", time.Date(2025, 3, 8, 23, 0, 0, 0, newYork), night, true, time.Date(2025, 3, 9, 7, 0, 0, 0, newYork)},
+		{"fall back", time.Date(2025, 11, 1, 23, 0, 0, 0, newYork), night, true, time.Date(2025, 11, 2, 7, 0, 0, 0, newYork)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, quiet, err := QuietUntil(tt.now, tt.qh, "America/New_York")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if quiet != tt.wantQuiet || !got.Equal(tt.want) {
+				t.Errorf("QuietUntil() = %s, %t; want %s, %t", got, quiet, tt.want, tt.wantQuiet)
+			}
+		})
+	}
+}
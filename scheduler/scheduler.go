@@ -0,0 +1,125 @@
+// scheduler/scheduler.go
+package scheduler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"time"
+
+	"notification-service/models"
+)
+
+// Retry bounds for jobs whose dispatch fails: the delay doubles from retryDelay up to
+// maxRetryDelay, and a job is dropped after maxAttempts failed dispatches.
+const (
+	retryDelay    = time.Minute
+	maxRetryDelay = time.Hour
+	maxAttempts   = 10
+)
+
+// Scheduler holds deferred notifications and dispatches them when they are due.
+type Scheduler struct {
+	store    Store
+	dispatch func(msg models.NotificationMessage, queue string) error
+	interval time.Duration
+}
+
+// NewScheduler creates a scheduler that polls the store every interval and hands
+// due messages, with the queue they were consumed from, to dispatch.
+func NewScheduler(store Store, interval time.Duration, dispatch func(msg models.NotificationMessage, queue string) error) *Scheduler {
+	return &Scheduler{
+		store:    store,
+		dispatch: dispatch,
+		interval: interval,
+	}
+}
+
+// Schedule defers a message consumed from queue until deliverAt
+func (s *Scheduler) Schedule(msg models.NotificationMessage, queue string, deliverAt time.Time) (string, error) {
+	id := newJobID()
+	err := s.store.Add(Job{
+		ID:        id,
+		DeliverAt: deliverAt,
+		Message:   msg,
+		Queue:     queue,
+	})
+	if err != nil {
+		return "", err
+	}
+	log.Printf("Scheduled %s for %s at %s (JobID: %s, Pending: %d)",
+		msg.NotificationType, msg.Recipient.UserID, deliverAt.UTC().Format(time.RFC3339), id, s.store.Len())
+	return id, nil
+}
+
+// Run dispatches due jobs until ctx is cancelled
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.dispatchDue(now)
+		}
+	}
+}
+
+// dispatchDue hands every due job to dispatch, rescheduling failures with backoff
+// until they run out of attempts.
+func (s *Scheduler) dispatchDue(now time.Time) {
+	jobs, err := s.store.Due(now)
+	if err != nil {
+		log.Printf("Failed to load due scheduled jobs: %v", err)
+		return
+	}
+
+	for _, job := range jobs {
+		log.Printf("Dispatching scheduled %s for %s (JobID: %s)",
+			job.Message.NotificationType, job.Message.Recipient.UserID, job.ID)
+
+		err := s.dispatch(job.Message, job.Queue)
+		if err == nil {
+			s.remove(job)
+			continue
+		}
+
+		job.Attempts++
+		if job.Attempts >= maxAttempts {
+			log.Printf("Giving up on scheduled job %s for %s after %d attempts: %v",
+				job.ID, job.Message.Recipient.UserID, job.Attempts, err)
+			s.remove(job)
+			continue
+		}
+		job.DeliverAt = now.Add(backoff(job.Attempts))
+		log.Printf("Error dispatching scheduled job %s (Attempt %d): %v. Retrying at %s.",
+			job.ID, job.Attempts, err, job.DeliverAt.UTC().Format(time.RFC3339))
+		if err := s.store.Add(job); err != nil {
+			log.Printf("Failed to reschedule job %s: %v", job.ID, err)
+		}
+	}
+}
+
+func (s *Scheduler) remove(job Job) {
+	if err := s.store.Remove(job.ID); err != nil {
+		log.Printf("Failed to remove scheduled job %s: %v", job.ID, err)
+	}
+}
+
+// backoff returns the delay before the next attempt after the given number of failures.
+func backoff(attempts int) time.Duration {
+	delay := retryDelay
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}
+
+func newJobID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,128 @@
+// scheduler/scheduler_test.go
+package scheduler
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"notification-service/models"
+	"notification-service/sqlstore"
+)
+
+func TestSchedulerGivesUpAfterMaxAttempts(t *testing.T) {
+	store := NewMemoryStore()
+	calls := 0
+	s := NewScheduler(store, time.Second, func(models.NotificationMessage, string) error {
+		calls++
+		return errors.New("provider down")
+	})
+	if _, err := s.Schedule(models.NotificationMessage{NotificationType: "T"}, "", time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	for i := 0; i < maxAttempts+5; i++ {
+		s.dispatchDue(now)
+		now = now.Add(maxRetryDelay)
+	}
+	if calls != maxAttempts {
+		t.Errorf("dispatched %d times, want %d", calls, maxAttempts)
+	}
+	if store.Len() != 0 {
+		t.Errorf("store holds %d jobs after giving up, want 0", store.Len())
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	for attempts, want := range map[int]time.Duration{1: time.Minute, 2: 2 * time.Minute, 4: 8 * time.Minute, 9: time.Hour} {
+		if got := backoff(attempts); got != want {
+			t.Errorf("backoff(%d) = %s, want %s", attempts, got, want)
+		}
+	}
+}
+
+func TestSQLStoreKeepsJobsUntilRemoved(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.db")
+	db, err := sqlstore.Open(sqlstore.DriverSQLite, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	store, err := NewSQLStore(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	msg := models.NotificationMessage{NotificationType: "VOLUNTEER_NEW_MATCHING_OPPORTUNITY", EventID: "e1"}
+	for _, job := range []Job{
+		{ID: "later", DeliverAt: now.Add(time.Hour), Message: msg},
+		{ID: "due", DeliverAt: now.Add(-time.Minute), Message: msg, Queue: "ngo_email_queue"},
+	} {
+		if err := store.Add(job); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	due, err := store.Due(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(due) != 1 || due[0].ID != "due" || due[0].Message.EventID != "e1" || due[0].Queue != "ngo_email_queue" {
+		t.Fatalf("Due() = %+v, want only the due job with its message and queue", due)
+	}
+	if store.Len() != 2 {
+		t.Fatalf("Len() = %d after Due, want 2 until the job is removed", store.Len())
+	}
+
+	due[0].Attempts = 3
+	due[0].DeliverAt = now.Add(2 * time.Hour)
+	if err := store.Add(due[0]); err != nil {
+		t.Fatal(err)
+	}
+	if due, _ := store.Due(now); len(due) != 0 {
+		t.Fatalf("Due() = %+v after rescheduling, want none", due)
+	}
+	if err := store.Remove("due"); err != nil {
+		t.Fatal(err)
+	}
+	if store.Len() != 1 {
+		t.Fatalf("Len() = %d after Remove, want 1", store.Len())
+	}
+}
+
+func TestSQLStoreDropsUndecodableJobs(t *testing.T) {
+	db, err := sqlstore.Open(sqlstore.DriverSQLite, filepath.Join(t.TempDir(), "jobs.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	store, err := NewSQLStore(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	msg := models.NotificationMessage{NotificationType: "VOLUNTEER_NEW_MATCHING_OPPORTUNITY", EventID: "e1"}
+	if err := store.Add(Job{ID: "good", DeliverAt: now.Add(-time.Minute), Message: msg}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Exec(`INSERT INTO scheduled_jobs (id, deliver_at, attempts, message) VALUES (?, ?, 0, ?)`,
+		"bad", now.Add(-2*time.Minute).UnixMilli(), `{"event_id": 42`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	due, err := store.Due(now)
+	if err != nil {
+		t.Fatalf("Due() error = %v, want the undecodable job skipped", err)
+	}
+	if len(due) != 1 || due[0].ID != "good" {
+		t.Fatalf("Due() = %+v, want only the decodable job", due)
+	}
+	if store.Len() != 1 {
+		t.Fatalf("Len() = %d, want the undecodable job removed", store.Len())
+	}
+}
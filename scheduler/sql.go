@@ -0,0 +1,127 @@
+// scheduler/sql.go
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"notification-service/sqlstore"
+)
+
+// Jobs are stored with their message as JSON; deliver_at is Unix milliseconds.
+var schema = map[string][]string{
+	sqlstore.DriverSQLite: {
+		`CREATE TABLE IF NOT EXISTS scheduled_jobs (
+			id TEXT PRIMARY KEY,
+			deliver_at INTEGER NOT NULL,
+			attempts INTEGER NOT NULL DEFAULT 0,
+			message TEXT NOT NULL,
+			queue TEXT NOT NULL DEFAULT ''
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_scheduled_jobs_deliver_at ON scheduled_jobs (deliver_at)`,
+	},
+	sqlstore.DriverPostgres: {
+		`CREATE TABLE IF NOT EXISTS scheduled_jobs (
+			id TEXT PRIMARY KEY,
+			deliver_at BIGINT NOT NULL,
+			attempts INTEGER NOT NULL DEFAULT 0,
+			message TEXT NOT NULL,
+			queue TEXT NOT NULL DEFAULT ''
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_scheduled_jobs_deliver_at ON scheduled_jobs (deliver_at)`,
+	},
+}
+
+// SQLStore is a Store backed by SQLite or Postgres, so deferred notifications,
+// which were already acknowledged on the queue, survive restarts.
+type SQLStore struct {
+	db *sqlstore.DB
+}
+
+// NewSQLStore creates the job table in db if needed
+func NewSQLStore(db *sqlstore.DB) (*SQLStore, error) {
+	if err := db.Migrate(schema[db.Driver]); err != nil {
+		return nil, fmt.Errorf("creating scheduled job schema: %w", err)
+	}
+	return &SQLStore{db: db}, nil
+}
+
+// Add stores a job
+func (s *SQLStore) Add(job Job) error {
+	message, err := json.Marshal(job.Message)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(context.Background(), s.db.Rebind(`INSERT INTO scheduled_jobs (id, deliver_at, attempts, message, queue)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET deliver_at = excluded.deliver_at, attempts = excluded.attempts,
+			message = excluded.message, queue = excluded.queue`),
+		job.ID, job.DeliverAt.UnixMilli(), job.Attempts, string(message), job.Queue)
+	return err
+}
+
+// Due returns every due job, earliest first. A job whose message cannot be decoded
+// would never dispatch, so it is logged with its raw message and dropped instead
+// of failing the rest of the batch.
+func (s *SQLStore) Due(now time.Time) ([]Job, error) {
+	due, undecodable, err := s.due(now)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range undecodable {
+		if err := s.Remove(id); err != nil {
+			log.Printf("Failed to remove undecodable scheduled job %s: %v", id, err)
+		}
+	}
+	return due, nil
+}
+
+// due reads the due jobs, returning the IDs of rows whose message does not decode separately.
+// The rows must be closed before those are removed, as SQLite uses a single connection.
+func (s *SQLStore) due(now time.Time) ([]Job, []string, error) {
+	rows, err := s.db.QueryContext(context.Background(), s.db.Rebind(
+		`SELECT id, deliver_at, attempts, message, queue FROM scheduled_jobs WHERE deliver_at <= ? ORDER BY deliver_at, id`),
+		now.UnixMilli())
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var due []Job
+	var undecodable []string
+	for rows.Next() {
+		var job Job
+		var deliverAt int64
+		var message string
+		if err := rows.Scan(&job.ID, &deliverAt, &job.Attempts, &message, &job.Queue); err != nil {
+			return nil, nil, err
+		}
+		if err := json.Unmarshal([]byte(message), &job.Message); err != nil {
+			log.Printf("Dropping scheduled job %s: decoding message: %v. Message: %s", job.ID, err, message)
+			undecodable = append(undecodable, job.ID)
+			continue
+		}
+		job.DeliverAt = time.UnixMilli(deliverAt).UTC()
+		due = append(due, job)
+	}
+	return due, undecodable, rows.Err()
+}
+
+// Remove deletes a job
+func (s *SQLStore) Remove(id string) error {
+	_, err := s.db.ExecContext(context.Background(), s.db.Rebind(`DELETE FROM scheduled_jobs WHERE id = ?`), id)
+	return err
+}
+
+// Len returns the number of pending jobs, or 0 if they cannot be counted
+func (s *SQLStore) Len() int {
+	var n int
+	if err := s.db.QueryRowContext(context.Background(), `SELECT COUNT(*) FROM scheduled_jobs`).Scan(&n); err != nil {
+		log.Printf("Failed to count scheduled jobs: %v", err)
+		return 0
+	}
+	return n
+}
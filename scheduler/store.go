@@ -0,0 +1,87 @@
+// scheduler/store.go
+package scheduler
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"notification-service/models"
+)
+
+// Job is a notification held back until DeliverAt.
+type Job struct {
+	ID        string                     `json:"id"`
+	DeliverAt time.Time                  `json:"deliver_at"`
+	Attempts  int                        `json:"attempts"`
+	Message   models.NotificationMessage `json:"message"`
+	// Queue is the queue the message was consumed from, which decides the channels it is sent on.
+	Queue string `json:"queue,omitempty"`
+}
+
+// Store holds deferred jobs until they are due. A job stays in the store while it is
+// being dispatched and is only removed afterwards, so a crash cannot lose it.
+type Store interface {
+	// Add stores a job, replacing any job with the same ID.
+	Add(job Job) error
+	// Due returns every job due at or before now, earliest first, without removing them.
+	Due(now time.Time) ([]Job, error)
+	// Remove deletes a job once it has been dispatched or given up on.
+	Remove(id string) error
+	// Len returns the number of pending jobs.
+	Len() int
+}
+
+// MemoryStore is an in-process Store. Pending jobs are lost on restart.
+type MemoryStore struct {
+	mu   sync.Mutex
+	jobs map[string]Job // keyed by job ID
+}
+
+// NewMemoryStore creates an empty in-memory job store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{jobs: make(map[string]Job)}
+}
+
+// Add stores a job
+func (s *MemoryStore) Add(job Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.jobs[job.ID] = job
+	return nil
+}
+
+// Due returns every due job, earliest first
+func (s *MemoryStore) Due(now time.Time) ([]Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var due []Job
+	for _, job := range s.jobs {
+		if !job.DeliverAt.After(now) {
+			due = append(due, job)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].DeliverAt.Before(due[j].DeliverAt)
+	})
+	return due, nil
+}
+
+// Remove deletes a job
+func (s *MemoryStore) Remove(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.jobs, id)
+	return nil
+}
+
+// Len returns the number of pending jobs
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.jobs)
+}
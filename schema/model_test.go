@@ -0,0 +1,83 @@
+// schema/model_test.go
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"notification-service/constants"
+	"notification-service/models"
+)
+
+// TestSchemasMatchModel keeps the schemas in step with the models: every JSON field of
+// models.NotificationMessage must be a property of the common schema and vice versa, at
+// every level, and every notification type with a routing key must have a schema.
+func TestSchemasMatchModel(t *testing.T) {
+	data, err := files.ReadFile("schemas/notification.schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var root schemaNode
+	if err := json.Unmarshal(data, &root); err != nil {
+		t.Fatal(err)
+	}
+
+	compareFields(t, reflect.TypeOf(models.NotificationMessage{}), root, "")
+	for notificationType := range constants.NotificationRoutingKeys {
+		if _, ok := schemas[notificationType]; !ok {
+			t.Errorf("notification type %s has no schema", notificationType)
+		}
+		if !slices.Contains(root.Properties["notification_type"].Enum, notificationType) {
+			t.Errorf("notification type %s is missing from the common schema's enum", notificationType)
+		}
+	}
+	for notificationType := range schemas {
+		if _, ok := constants.NotificationRoutingKeys[notificationType]; !ok && notificationType != "" {
+			t.Errorf("schema for %s has no routing key", notificationType)
+		}
+	}
+}
+
+// schemaNode is the part of a JSON Schema object TestSchemasMatchModel reads.
+type schemaNode struct {
+	Properties map[string]schemaNode `json:"properties"`
+	Enum       []string              `json:"enum"`
+}
+
+// compareFields reports the differences between a struct's JSON fields and a schema's properties.
+func compareFields(t *testing.T, typ reflect.Type, node schemaNode, prefix string) {
+	t.Helper()
+	seen := make(map[string]bool)
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		seen[name] = true
+
+		property, ok := node.Properties[name]
+		if !ok {
+			t.Errorf("%s%s is missing from the schema", prefix, name)
+			continue
+		}
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if fieldType.Kind() == reflect.Struct {
+			compareFields(t, fieldType, property, prefix+name+".")
+		}
+	}
+	for name := range node.Properties {
+		if !seen[name] {
+			t.Errorf("%s%s is not a field of the model", prefix, name)
+		}
+	}
+}
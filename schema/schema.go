@@ -0,0 +1,236 @@
+// schema/schema.go
+package schema
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+
+	"notification-service/models"
+)
+
+// Validation modes, chosen with VALIDATION_MODE.
+const (
+	ModeOff    = "off"    // Messages are not validated
+	ModeWarn   = "warn"   // Invalid messages are logged and still processed
+	ModeStrict = "strict" // Invalid messages are moved to the dead letter queue
+)
+
+// Headers describing why a message was dead-lettered for failing validation.
+const (
+	HeaderSchema = "x-validation-schema" // $id of the schema the message failed
+	HeaderErrors = "x-validation-errors" // JSON array of FieldError
+)
+
+// baseURL prefixes every schema's $id.
+const baseURL = "https://notification-service/schemas/"
+
+//go:embed schemas/*.json
+var files embed.FS
+
+// schemas holds the compiled schema for each notification type, plus "" for the
+// common schema, which unknown types are validated against.
+var schemas = mustCompile()
+
+// FieldError is a validation failure of one field.
+type FieldError struct {
+	Field   string `json:"field"` // Dotted path, e.g. recipient.user_id; empty for the whole message
+	Message string `json:"message"`
+}
+
+// ValidationError lists every field of a message that does not match its schema.
+type ValidationError struct {
+	Schema string       `json:"schema"`
+	Fields []FieldError `json:"fields"`
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		if f.Field == "" {
+			parts[i] = f.Message
+		} else {
+			parts[i] = f.Field + ": " + f.Message
+		}
+	}
+	return "invalid message: " + strings.Join(parts, "; ")
+}
+
+// Headers returns the validation report as AMQP headers for the dead-lettered message.
+func (e *ValidationError) Headers() map[string]interface{} {
+	report, _ := json.Marshal(e.Fields)
+	return map[string]interface{}{
+		HeaderSchema: e.Schema,
+		HeaderErrors: string(report),
+	}
+}
+
+// ValidMode reports whether mode is one of the validation modes.
+func ValidMode(mode string) bool {
+	return mode == ModeOff || mode == ModeWarn || mode == ModeStrict
+}
+
+// Validate checks a decoded message against the JSON Schema for its notification type.
+// It returns a *ValidationError listing every invalid field. Messages that arrive as
+// JSON are checked with ValidateBody instead, before they are decoded.
+func Validate(msg models.NotificationMessage) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	instance, err := jsonschema.UnmarshalJSON(bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	return validate(msg.NotificationType, instance)
+}
+
+// ValidateBody checks a message as it was sent, before it is decoded, so fields of the
+// wrong type and unknown fields are reported rather than failing to decode or being
+// dropped. A version 1 body is checked as it is; of a version 2 envelope, the metadata
+// and the recipient and payload in data are checked in the version 1 layout. A body
+// that is not a JSON object of a supported version is left to the decoder to reject.
+// defaultType is the notification type of a body that names none, such as the type of
+// the CloudEvent carrying it.
+func ValidateBody(body []byte, defaultType string) error {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(body))
+	if err != nil {
+		return nil
+	}
+	obj, ok := doc.(map[string]any)
+	if !ok {
+		return validate("", doc)
+	}
+
+	instance := make(map[string]any, len(obj))
+	switch version, _ := obj["schema_version"].(json.Number); version {
+	case "", "1":
+		for name, value := range obj {
+			if name != "schema_version" {
+				instance[name] = value
+			}
+		}
+	case "2":
+		// occurred_at is checked by the decoder; the rest of the envelope maps onto version 1.
+		for _, name := range []string{"notification_type", "event_id", "sender_service"} {
+			if value, ok := obj[name]; ok {
+				instance[name] = value
+			}
+		}
+		if data, ok := obj["data"].(map[string]any); ok {
+			for _, name := range []string{"recipient", "payload"} {
+				if value, ok := data[name]; ok {
+					instance[name] = value
+				}
+			}
+		}
+	default:
+		return nil
+	}
+	if _, ok := instance["notification_type"]; !ok && defaultType != "" {
+		instance["notification_type"] = defaultType
+	}
+	notificationType, _ := instance["notification_type"].(string)
+	return validate(notificationType, instance)
+}
+
+// validate checks an instance against the schema for a notification type, or the
+// common schema if the type has none.
+func validate(notificationType string, instance any) error {
+	sch, ok := schemas[notificationType]
+	if !ok {
+		sch = schemas[""]
+	}
+	err := sch.Validate(instance)
+	if err == nil {
+		return nil
+	}
+	verr, ok := err.(*jsonschema.ValidationError)
+	if !ok {
+		return err
+	}
+	result := &ValidationError{Schema: strings.TrimSuffix(sch.Location, "#")}
+	collect(verr, &result.Fields)
+	sort.SliceStable(result.Fields, func(i, j int) bool { return result.Fields[i].Field < result.Fields[j].Field })
+	return result
+}
+
+// Source returns the JSON Schema for a notification type, or false if there is none.
+func Source(notificationType string) ([]byte, bool) {
+	data, err := files.ReadFile("schemas/" + notificationType + ".schema.json")
+	return data, err == nil
+}
+
+// printer renders error kinds in English.
+var printer = message.NewPrinter(language.English)
+
+// collect appends a FieldError for each leaf of the validation error tree.
+func collect(verr *jsonschema.ValidationError, fields *[]FieldError) {
+	if len(verr.Causes) > 0 {
+		for _, cause := range verr.Causes {
+			collect(cause, fields)
+		}
+		return
+	}
+
+	field := strings.Join(verr.InstanceLocation, ".")
+	if required, ok := verr.ErrorKind.(*kind.Required); ok {
+		for _, missing := range required.Missing {
+			*fields = append(*fields, FieldError{Field: joinField(field, missing), Message: "is required"})
+		}
+		return
+	}
+	*fields = append(*fields, FieldError{Field: field, Message: verr.ErrorKind.LocalizedString(printer)})
+}
+
+func joinField(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
+
+// mustCompile compiles the embedded schemas. They are part of the build, so an
+// invalid one is a programming error.
+func mustCompile() map[string]*jsonschema.Schema {
+	compiler := jsonschema.NewCompiler()
+	compiler.AssertFormat()
+
+	entries, err := files.ReadDir("schemas")
+	if err != nil {
+		panic(err)
+	}
+	for _, entry := range entries {
+		data, err := files.ReadFile(path.Join("schemas", entry.Name()))
+		if err != nil {
+			panic(err)
+		}
+		doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+		if err != nil {
+			panic(fmt.Sprintf("schema %s: %v", entry.Name(), err))
+		}
+		if err := compiler.AddResource(baseURL+entry.Name(), doc); err != nil {
+			panic(fmt.Sprintf("schema %s: %v", entry.Name(), err))
+		}
+	}
+
+	compiled := make(map[string]*jsonschema.Schema, len(entries))
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".schema.json")
+		key := name
+		if name == "notification" {
+			key = ""
+		}
+		compiled[key] = compiler.MustCompile(baseURL + entry.Name())
+	}
+	return compiled
+}
@@ -0,0 +1,102 @@
+// schema/schema_test.go
+package schema
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestValidateBody(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		defaultType string
+		wantSchema  string
+		want        []FieldError // nil when the body is valid
+	}{
+		{
+			name: "valid v1",
+			body: `{"notification_type":"APPLICATION_ACCEPTED","timestamp":1735830245,
+				"recipient":{"user_id":"u1"},"payload":{"title":"Accepted","body":"You're in","application_id":12}}`,
+		},
+		{
+			name: "v1 field of the wrong type",
+			body: `{"notification_type":"APPLICATION_ACCEPTED",
+				"recipient":{"user_id":"u1"},"payload":{"title":"Accepted","body":"You're in","application_id":"12"}}`,
+			wantSchema: baseURL + "APPLICATION_ACCEPTED.schema.json",
+			want:       []FieldError{{Field: "payload.application_id", Message: "got string, want integer"}},
+		},
+		{
+			name: "v1 unknown field",
+			body: `{"schema_version":1,"notification_type":"NGO_NEW_APPLICATION","legacy_flag":true,
+				"recipient":{"user_id":"u2"},"payload":{"title":"New","body":"An application","application_id":3}}`,
+			wantSchema: baseURL + "NGO_NEW_APPLICATION.schema.json",
+			want:       []FieldError{{Message: "additional properties 'legacy_flag' not allowed"}},
+		},
+		{
+			name: "valid v2",
+			body: `{"schema_version":2,"event_id":"e3","notification_type":"OPPORTUNITY_UPDATED",
+				"occurred_at":"2025-01-02T15:04:05Z","sender_service":"opportunities",
+				"data":{"recipient":{"user_id":"u3"},"payload":{"title":"Updated","body":"Changed","opportunity_id":7}}}`,
+		},
+		{
+			name: "v2 data checked in the v1 layout",
+			body: `{"schema_version":2,"notification_type":"OPPORTUNITY_UPDATED",
+				"data":{"recipient":{"user_id":"u3","pager":"x"},"payload":{"title":"Updated","body":"Changed","opportunity_id":0}}}`,
+			wantSchema: baseURL + "OPPORTUNITY_UPDATED.schema.json",
+			want: []FieldError{
+				{Field: "payload.opportunity_id", Message: "minimum: got 0, want 1"},
+				{Field: "recipient", Message: "additional properties 'pager' not allowed"},
+			},
+		},
+		{
+			name:        "type of the carrying CloudEvent",
+			body:        `{"recipient":{"user_id":"u4"},"payload":{"title":"Status","body":"Changed","application_id":5}}`,
+			defaultType: "VOLUNTEER_APPLICATION_STATUS_UPDATE",
+			wantSchema:  baseURL + "VOLUNTEER_APPLICATION_STATUS_UPDATE.schema.json",
+			want:        []FieldError{{Field: "payload.new_status", Message: "is required"}},
+		},
+		{
+			name: "valid APPLICATION_STATUS_CHANGED",
+			body: `{"notification_type":"APPLICATION_STATUS_CHANGED","recipient":{"user_id":"u6"},
+				"payload":{"title":"Status","body":"Changed","application_id":5,"new_status":"ON_HOLD"}}`,
+		},
+		{
+			name:       "unknown type against the common schema",
+			body:       `{"notification_type":"NOPE","recipient":{"user_id":"u5"},"payload":{"title":"t","body":"b"}}`,
+			wantSchema: baseURL + "notification.schema.json",
+			want:       []FieldError{{Field: "notification_type", Message: "value must be one of 'NGO_NEW_APPLICATION', 'APPLICATION_ACCEPTED', 'APPLICATION_REJECTED', 'APPLICATION_WITHDRAWN', 'APPLICATION_COMPLETED', 'APPLICATION_STATUS_CHANGED', 'VOLUNTEER_APPLICATION_STATUS_UPDATE', 'VOLUNTEER_NEW_MATCHING_OPPORTUNITY', 'OPPORTUNITY_UPDATED', 'OPPORTUNITY_DELETED'"}},
+		},
+		{
+			name: "unsupported version is left to the decoder",
+			body: `{"schema_version":9,"whatever":true}`,
+		},
+		{
+			name: "not JSON is left to the decoder",
+			body: `{"notification_type":`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateBody([]byte(tt.body), tt.defaultType)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("ValidateBody() error = %v, want nil", err)
+				}
+				return
+			}
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("ValidateBody() error = %v, want *ValidationError", err)
+			}
+			if verr.Schema != tt.wantSchema {
+				t.Errorf("Schema = %q, want %q", verr.Schema, tt.wantSchema)
+			}
+			if !reflect.DeepEqual(verr.Fields, tt.want) {
+				t.Errorf("Fields = %+v, want %+v", verr.Fields, tt.want)
+			}
+		})
+	}
+}
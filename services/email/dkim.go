@@ -0,0 +1,422 @@
+// services/email/dkim.go
+package email
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DKIM signing algorithms (RFC 6376, RFC 8463).
+const (
+	DKIMAlgorithmRSA     = "rsa-sha256"
+	DKIMAlgorithmEd25519 = "ed25519-sha256"
+)
+
+// dkimSignedHeaders are signed when present. From is always signed.
+var dkimSignedHeaders = []string{
+	"From", "To", "Subject", "Date", "Message-ID", "MIME-Version", "Content-Type",
+	"List-Unsubscribe", "List-Unsubscribe-Post",
+}
+
+// DKIMKey signs mail for one sending domain with one selector. A domain may have
+// several keys (e.g. RSA and Ed25519); the message is then signed with each.
+type DKIMKey struct {
+	Domain   string
+	Selector string
+	Signer   crypto.Signer // *rsa.PrivateKey or ed25519.PrivateKey
+}
+
+// LoadDKIMKey reads a PEM private key (PKCS#8, or PKCS#1 for RSA) for the domain and selector
+func LoadDKIMKey(domain, selector, path string) (DKIMKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return DKIMKey{}, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return DKIMKey{}, fmt.Errorf("no PEM data in %s", path)
+	}
+
+	var key interface{}
+	key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return DKIMKey{}, fmt.Errorf("parsing DKIM key %s: %w", path, err)
+	}
+
+	dk := DKIMKey{Domain: strings.ToLower(domain), Selector: selector}
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		dk.Signer = k
+	case ed25519.PrivateKey:
+		dk.Signer = k
+	default:
+		return DKIMKey{}, fmt.Errorf("DKIM key %s must be RSA or Ed25519", path)
+	}
+	return dk, nil
+}
+
+// Algorithm returns the DKIM a= value for the key
+func (k DKIMKey) Algorithm() string {
+	if _, ok := k.Signer.(ed25519.PrivateKey); ok {
+		return DKIMAlgorithmEd25519
+	}
+	return DKIMAlgorithmRSA
+}
+
+// DNSRecord returns the TXT record to publish at <selector>._domainkey.<domain>
+func (k DKIMKey) DNSRecord() (string, error) {
+	switch pub := k.Signer.Public().(type) {
+	case ed25519.PublicKey:
+		return "v=DKIM1; k=ed25519; p=" + base64.StdEncoding.EncodeToString(pub), nil
+	case *rsa.PublicKey:
+		der, err := x509.MarshalPKIXPublicKey(pub)
+		if err != nil {
+			return "", err
+		}
+		return "v=DKIM1; k=rsa; p=" + base64.StdEncoding.EncodeToString(der), nil
+	}
+	return "", errors.New("unsupported DKIM key type")
+}
+
+// SignDKIM returns the message with a DKIM-Signature header prepended, using
+// relaxed/relaxed canonicalisation.
+func SignDKIM(raw []byte, key DKIMKey) ([]byte, error) {
+	headers, body := splitMessage(raw)
+
+	var signed []string
+	for _, name := range dkimSignedHeaders {
+		if _, ok := findHeader(headers, name); ok || name == "From" {
+			signed = append(signed, strings.ToLower(name))
+		}
+	}
+
+	bodyHash := sha256.Sum256(relaxedBody(body))
+	tags := []string{
+		"v=1",
+		"a=" + key.Algorithm(),
+		"c=relaxed/relaxed",
+		"d=" + key.Domain,
+		"s=" + key.Selector,
+		"t=" + strconv.FormatInt(time.Now().Unix(), 10),
+		"h=" + strings.Join(signed, ":"),
+		"bh=" + base64.StdEncoding.EncodeToString(bodyHash[:]),
+		"b=",
+	}
+	// Tags are folded one per line; relaxed canonicalisation removes the folding again.
+	unsigned := "DKIM-Signature: " + strings.Join(tags, ";\r\n\t")
+
+	digest := dkimHeaderHash(headers, signed, unsigned)
+	signature, err := dkimSign(key.Signer, digest)
+	if err != nil {
+		return nil, fmt.Errorf("DKIM signing for %s: %w", key.Domain, err)
+	}
+
+	var out bytes.Buffer
+	out.WriteString(unsigned)
+	out.WriteString(foldBase64(base64.StdEncoding.EncodeToString(signature)))
+	out.WriteString("\r\n")
+	out.Write(raw)
+	return out.Bytes(), nil
+}
+
+// DKIMResult is the outcome of verifying one DKIM-Signature header.
+type DKIMResult struct {
+	Domain    string
+	Selector  string
+	Algorithm string
+	Err       error // Nil when the signature is valid
+}
+
+// TXTLookup resolves DNS TXT records, e.g. net.LookupTXT.
+type TXTLookup func(name string) ([]string, error)
+
+// VerifyDKIM checks every DKIM signature on a message, fetching public keys with
+// lookup (net.LookupTXT when nil). It only supports what SignDKIM produces:
+// rsa-sha256 and ed25519-sha256 with relaxed or simple canonicalisation and no l= tag.
+func VerifyDKIM(raw []byte, lookup TXTLookup) ([]DKIMResult, error) {
+	if lookup == nil {
+		lookup = net.LookupTXT
+	}
+	headers, body := splitMessage(raw)
+
+	var results []DKIMResult
+	for _, h := range headers {
+		if !strings.EqualFold(headerName(h), "DKIM-Signature") {
+			continue
+		}
+		tags := parseTags(h[strings.Index(h, ":")+1:])
+		result := DKIMResult{Domain: tags["d"], Selector: tags["s"], Algorithm: tags["a"]}
+		result.Err = verifySignature(h, tags, headers, body, lookup)
+		results = append(results, result)
+	}
+	if len(results) == 0 {
+		return nil, errors.New("message has no DKIM-Signature")
+	}
+	return results, nil
+}
+
+func verifySignature(sigHeader string, tags map[string]string, headers []string, body []byte, lookup TXTLookup) error {
+	if tags["v"] != "1" || tags["d"] == "" || tags["s"] == "" || tags["h"] == "" {
+		return errors.New("malformed DKIM-Signature")
+	}
+	if tags["l"] != "" {
+		return errors.New("l= tag is not supported")
+	}
+	headerCanon, bodyCanon, _ := strings.Cut(tags["c"], "/")
+	if headerCanon == "" {
+		headerCanon = "simple"
+	}
+	if bodyCanon == "" {
+		bodyCanon = "simple"
+	}
+	if headerCanon != "relaxed" || bodyCanon != "relaxed" {
+		return fmt.Errorf("canonicalisation %s is not supported", tags["c"])
+	}
+
+	bodyHash := sha256.Sum256(relaxedBody(body))
+	if base64.StdEncoding.EncodeToString(bodyHash[:]) != stripWhitespace(tags["bh"]) {
+		return errors.New("body hash does not match")
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(stripWhitespace(tags["b"]))
+	if err != nil {
+		return fmt.Errorf("invalid b= tag: %w", err)
+	}
+
+	records, err := lookup(tags["s"] + "._domainkey." + tags["d"])
+	if err != nil {
+		return fmt.Errorf("looking up DKIM key: %w", err)
+	}
+	publicKey, err := parseDKIMRecord(strings.Join(records, ""))
+	if err != nil {
+		return err
+	}
+
+	var signed []string
+	for _, name := range strings.Split(tags["h"], ":") {
+		signed = append(signed, strings.ToLower(strings.TrimSpace(name)))
+	}
+	digest := dkimHeaderHash(headers, signed, removeSignatureValue(sigHeader))
+
+	switch tags["a"] {
+	case DKIMAlgorithmRSA:
+		pub, ok := publicKey.(*rsa.PublicKey)
+		if !ok {
+			return errors.New("key type does not match rsa-sha256")
+		}
+		return rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest, signature)
+	case DKIMAlgorithmEd25519:
+		pub, ok := publicKey.(ed25519.PublicKey)
+		if !ok {
+			return errors.New("key type does not match ed25519-sha256")
+		}
+		if !ed25519.Verify(pub, digest, signature) {
+			return errors.New("ed25519 signature does not verify")
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported algorithm %q", tags["a"])
+}
+
+// dkimSign signs the SHA-256 digest: PKCS#1 v1.5 for RSA, and PureEdDSA over the
+// digest for Ed25519 as RFC 8463 specifies.
+func dkimSign(signer crypto.Signer, digest []byte) ([]byte, error) {
+	if key, ok := signer.(ed25519.PrivateKey); ok {
+		return ed25519.Sign(key, digest), nil
+	}
+	return signer.Sign(nil, digest, crypto.SHA256)
+}
+
+// dkimHeaderHash hashes the signed headers (bottom-most instance first for repeated
+// names) followed by the DKIM-Signature header with an empty b= value.
+func dkimHeaderHash(headers []string, signed []string, sigHeader string) []byte {
+	h := sha256.New()
+	used := make(map[int]bool)
+	for _, name := range signed {
+		for i := len(headers) - 1; i >= 0; i-- {
+			if used[i] || !strings.EqualFold(headerName(headers[i]), name) {
+				continue
+			}
+			used[i] = true
+			h.Write([]byte(relaxedHeader(headers[i]) + "\r\n"))
+			break
+		}
+	}
+	h.Write([]byte(relaxedHeader(sigHeader)))
+	return h.Sum(nil)
+}
+
+func parseDKIMRecord(record string) (crypto.PublicKey, error) {
+	tags := parseTags(record)
+	if v := tags["v"]; v != "" && v != "DKIM1" {
+		return nil, fmt.Errorf("unsupported DKIM record version %q", v)
+	}
+	data, err := base64.StdEncoding.DecodeString(stripWhitespace(tags["p"]))
+	if err != nil || len(data) == 0 {
+		return nil, errors.New("DKIM record has no usable public key")
+	}
+	switch tags["k"] {
+	case "ed25519":
+		if len(data) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid ed25519 public key")
+		}
+		return ed25519.PublicKey(data), nil
+	case "", "rsa":
+		key, err := x509.ParsePKIXPublicKey(data)
+		if err != nil {
+			if rsaKey, err := x509.ParsePKCS1PublicKey(data); err == nil {
+				return rsaKey, nil
+			}
+			return nil, fmt.Errorf("invalid RSA public key: %w", err)
+		}
+		return key, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %q", tags["k"])
+}
+
+// splitMessage splits a raw message into header fields (with folding intact) and body.
+func splitMessage(raw []byte) ([]string, []byte) {
+	head, body, found := bytes.Cut(raw, []byte("\r\n\r\n"))
+	if !found {
+		return splitHeaders(string(raw)), nil
+	}
+	return splitHeaders(string(head) + "\r\n"), body
+}
+
+func splitHeaders(head string) []string {
+	var headers []string
+	for _, line := range strings.SplitAfter(head, "\r\n") {
+		if line == "" {
+			continue
+		}
+		if (line[0] == ' ' || line[0] == '\t') && len(headers) > 0 {
+			headers[len(headers)-1] += line
+			continue
+		}
+		headers = append(headers, line)
+	}
+	for i := range headers {
+		headers[i] = strings.TrimSuffix(headers[i], "\r\n")
+	}
+	return headers
+}
+
+func findHeader(headers []string, name string) (string, bool) {
+	for _, h := range headers {
+		if strings.EqualFold(headerName(h), name) {
+			return h, true
+		}
+	}
+	return "", false
+}
+
+func headerName(h string) string {
+	name, _, _ := strings.Cut(h, ":")
+	return strings.TrimSpace(name)
+}
+
+// relaxedHeader applies RFC 6376 relaxed header canonicalisation (without the trailing CRLF).
+func relaxedHeader(h string) string {
+	name, value, _ := strings.Cut(h, ":")
+	value = strings.NewReplacer("\r\n", "").Replace(value)
+	return strings.ToLower(strings.TrimSpace(name)) + ":" + strings.TrimSpace(collapseWhitespace(value))
+}
+
+// relaxedBody applies RFC 6376 relaxed body canonicalisation.
+func relaxedBody(body []byte) []byte {
+	lines := strings.Split(string(body), "\r\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(collapseWhitespace(line), " ")
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	return []byte(strings.Join(lines, "\r\n") + "\r\n")
+}
+
+func collapseWhitespace(s string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range s {
+		if r == ' ' || r == '\t' {
+			space = true
+			continue
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteRune(r)
+	}
+	if space {
+		b.WriteByte(' ')
+	}
+	return b.String()
+}
+
+func stripWhitespace(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '\t' || r == '\r' || r == '\n' {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// parseTags parses a tag=value list such as a DKIM-Signature or DKIM DNS record.
+func parseTags(s string) map[string]string {
+	tags := make(map[string]string)
+	for _, part := range strings.Split(s, ";") {
+		name, value, ok := strings.Cut(part, "=")
+		if !ok {
+			continue
+		}
+		tags[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+	return tags
+}
+
+// removeSignatureValue empties the b= tag of a DKIM-Signature header, keeping everything else verbatim.
+func removeSignatureValue(h string) string {
+	colon := strings.Index(h, ":")
+	parts := strings.Split(h[colon+1:], ";")
+	for i, part := range parts {
+		name, _, ok := strings.Cut(part, "=")
+		if ok && strings.TrimSpace(name) == "b" {
+			parts[i] = part[:strings.Index(part, "=")+1]
+		}
+	}
+	return h[:colon+1] + strings.Join(parts, ";")
+}
+
+// foldBase64 breaks a long base64 value over continuation lines.
+func foldBase64(s string) string {
+	const width = 72
+	var b strings.Builder
+	for len(s) > width {
+		b.WriteString(s[:width])
+		b.WriteString("\r\n\t")
+		s = s[width:]
+	}
+	b.WriteString(s)
+	return b.String()
+}
@@ -0,0 +1,117 @@
+// services/email/dkim_test.go
+package email
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"net/mail"
+	"testing"
+)
+
+// testMessage builds an HTML email from example.org signed with an RSA and an
+// Ed25519 key, and a TXTLookup that serves both keys' DNS records.
+func testMessage(t *testing.T) ([]byte, TXTLookup) {
+	t.Helper()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := []DKIMKey{
+		{Domain: "example.org", Selector: "rsa1", Signer: rsaKey},
+		{Domain: "example.org", Selector: "ed1", Signer: edKey},
+	}
+
+	records := make(map[string]string)
+	for _, key := range keys {
+		record, err := key.DNSRecord()
+		if err != nil {
+			t.Fatal(err)
+		}
+		records[key.Selector+"._domainkey."+key.Domain] = record
+	}
+	lookup := func(name string) ([]string, error) {
+		record, ok := records[name]
+		if !ok {
+			return nil, fmt.Errorf("no TXT record for %s", name)
+		}
+		return []string{record}, nil
+	}
+
+	s, err := NewService(Config{Host: "localhost", From: "VolHub <notifications@example.org>", DKIM: keys})
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := s.build(Message{
+		Subject:  "Your application was accepted",
+		TextBody: "Hello,\nSee you on Saturday.",
+		HTMLBody: "<p>Hello,</p>\n<p>See you on Saturday.</p>",
+		Headers:  map[string]string{"List-Unsubscribe": "<https://example.org/u/abc>"},
+	}, &mail.Address{Address: "volunteer@example.com"}, s.newMessageID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw, err = s.sign(raw); err != nil {
+		t.Fatal(err)
+	}
+	return raw, lookup
+}
+
+func TestDKIMSignVerify(t *testing.T) {
+	raw, lookup := testMessage(t)
+
+	tests := []struct {
+		name    string
+		message func([]byte) []byte
+		wantOK  bool
+	}{
+		{"unmodified", func(b []byte) []byte { return b }, true},
+		{"whitespace changed by a relay", func(b []byte) []byte {
+			return bytes.Replace(b, []byte("Subject: "), []byte("Subject:   "), 1)
+		}, true},
+		{"tampered subject", func(b []byte) []byte {
+			return bytes.Replace(b, []byte("accepted"), []byte("rejected"), 1)
+		}, false},
+		{"tampered body", func(b []byte) []byte {
+			return bytes.Replace(b, []byte("Saturday"), []byte("Sunday"), 1)
+		}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := VerifyDKIM(tt.message(bytes.Clone(raw)), lookup)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(results) != 2 {
+				t.Fatalf("got %d signatures, want 2", len(results))
+			}
+			for _, r := range results {
+				if ok := r.Err == nil; ok != tt.wantOK {
+					t.Errorf("%s (%s) valid = %v, want %v (err: %v)", r.Selector, r.Algorithm, ok, tt.wantOK, r.Err)
+				}
+			}
+		})
+	}
+}
+
+func TestVerifyDKIMUnknownKey(t *testing.T) {
+	raw, _ := testMessage(t)
+	_, otherLookup := testMessage(t)
+
+	results, err := VerifyDKIM(raw, otherLookup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range results {
+		if r.Err == nil {
+			t.Errorf("%s (%s) verified against another key", r.Selector, r.Algorithm)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+// services/email/email.go
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ProviderName identifies this service in delivery records.
+const ProviderName = "smtp"
+
+// Config holds the SMTP relay settings.
+type Config struct {
+	Host     string
+	Port     string
+	Username string // Optional; PLAIN auth is used when set
+	Password string
+	From     string // e.g. "VolHub <notifications@volhub.org>"
+	// DKIM keys by sending domain; messages from a domain are signed with each of its keys.
+	DKIM []DKIMKey
+}
+
+// Message is a single email to a single recipient.
+type Message struct {
+	To       string
+	Subject  string
+	TextBody string
+	HTMLBody string            // Optional; sent as multipart/alternative with TextBody
+	Headers  map[string]string // Extra headers, e.g. List-Unsubscribe
+}
+
+// Service sends email through an SMTP relay.
+type Service struct {
+	config Config
+	from   *mail.Address
+}
+
+// NewService creates an email service for the given SMTP relay
+func NewService(config Config) (*Service, error) {
+	if config.Host == "" {
+		return nil, errors.New("SMTP host is required")
+	}
+	if config.Port == "" {
+		config.Port = "587"
+	}
+	from, err := mail.ParseAddress(config.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid from address %q: %w", config.From, err)
+	}
+	return &Service{config: config, from: from}, nil
+}
+
+// Send delivers the message and returns the Message-ID it was sent with
+func (s *Service) Send(ctx context.Context, m Message) (string, error) {
+	to, err := mail.ParseAddress(m.To)
+	if err != nil {
+		return "", fmt.Errorf("invalid recipient address %q: %w", m.To, err)
+	}
+
+	messageID := s.newMessageID()
+	raw, err := s.build(m, to, messageID)
+	if err != nil {
+		return "", err
+	}
+	if raw, err = s.sign(raw); err != nil {
+		return "", err
+	}
+
+	var auth smtp.Auth
+	if s.config.Username != "" {
+		auth = smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.Host)
+	}
+
+	// net/smtp has no context support, so run it in the background and honour cancellation.
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(net.JoinHostPort(s.config.Host, s.config.Port), auth, s.from.Address, []string{to.Address}, raw)
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			return "", fmt.Errorf("smtp: %w", err)
+		}
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+
+	log.Printf("Email sent (MessageID: %s, Subject: '%s')", messageID, m.Subject)
+	return messageID, nil
+}
+
+// Ping checks that the SMTP relay accepts connections.
+func (s *Service) Ping(ctx context.Context) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", net.JoinHostPort(s.config.Host, s.config.Port))
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// build renders the RFC 5322 message.
+func (s *Service) build(m Message, to *mail.Address, messageID string) ([]byte, error) {
+	var buf bytes.Buffer
+
+	headers := map[string]string{
+		"From":         s.from.String(),
+		"To":           to.String(),
+		"Subject":      mime.QEncoding.Encode("utf-8", m.Subject),
+		"Date":         time.Now().Format(time.RFC1123Z),
+		"Message-ID":   messageID,
+		"MIME-Version": "1.0",
+	}
+	for k, v := range m.Headers {
+		headers[textproto.CanonicalMIMEHeaderKey(k)] = v
+	}
+
+	if m.HTMLBody == "" {
+		headers["Content-Type"] = "text/plain; charset=utf-8"
+		headers["Content-Transfer-Encoding"] = "quoted-printable"
+		writeHeaders(&buf, headers)
+		if err := writeQuotedPrintable(&buf, m.TextBody); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	headers["Content-Type"] = "multipart/alternative; boundary=" + mw.Boundary()
+	writeHeaders(&buf, headers)
+
+	for _, part := range []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=utf-8", m.TextBody},
+		{"text/html; charset=utf-8", m.HTMLBody},
+	} {
+		w, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeQuotedPrintable(w, part.content); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	buf.Write(body.Bytes())
+	return buf.Bytes(), nil
+}
+
+// sign adds a DKIM-Signature for every key configured for the sender's domain.
+func (s *Service) sign(raw []byte) ([]byte, error) {
+	domain := strings.ToLower(s.from.Address[strings.LastIndex(s.from.Address, "@")+1:])
+	for _, key := range s.config.DKIM {
+		if key.Domain != domain {
+			continue
+		}
+		signed, err := SignDKIM(raw, key)
+		if err != nil {
+			return nil, err
+		}
+		raw = signed
+	}
+	return raw, nil
+}
+
+// newMessageID returns a unique Message-ID in the sender's domain.
+func (s *Service) newMessageID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	domain := "localhost"
+	if at := strings.LastIndex(s.from.Address, "@"); at >= 0 {
+		domain = s.from.Address[at+1:]
+	}
+	return fmt.Sprintf("<%s@%s>", hex.EncodeToString(b), domain)
+}
+
+// writeHeaders writes headers in a stable order followed by the blank separator line.
+func writeHeaders(buf *bytes.Buffer, headers map[string]string) {
+	keys := make([]string, 0, len(headers))
+	for k := range headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(buf, "%s: %s\r\n", k, headers[k])
+	}
+	buf.WriteString("\r\n")
+}
+
+func writeQuotedPrintable(w io.Writer, content string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(content)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
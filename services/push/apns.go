@@ -0,0 +1,165 @@
+// services/push/apns.go
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	apnsProductionURL  = "https://api.push.apple.com"
+	apnsDevelopmentURL = "https://api.sandbox.push.apple.com"
+
+	// Apple rejects provider tokens older than an hour and throttles ones refreshed
+	// more often than every 20 minutes.
+	apnsTokenLifetime = 40 * time.Minute
+)
+
+// APNsConfig holds the token-based authentication settings for APNs.
+type APNsConfig struct {
+	KeyFile    string // .p8 signing key downloaded from the Apple developer portal
+	KeyID      string
+	TeamID     string
+	Topic      string // App bundle ID
+	Production bool
+}
+
+// APNsProvider sends pushes through the APNs HTTP/2 API.
+type APNsProvider struct {
+	client *http.Client
+	config APNsConfig
+	key    crypto.Signer
+	host   string
+
+	mu       sync.Mutex
+	jwt      string
+	issuedAt time.Time
+}
+
+// NewAPNsProvider creates an APNs provider using token-based authentication
+func NewAPNsProvider(config APNsConfig) (*APNsProvider, error) {
+	raw, err := os.ReadFile(config.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	key, err := parsePrivateKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid APNs signing key: %w", err)
+	}
+
+	host := apnsDevelopmentURL
+	if config.Production {
+		host = apnsProductionURL
+	}
+
+	return &APNsProvider{
+		// net/http negotiates HTTP/2 over TLS automatically, which APNs requires.
+		client: &http.Client{Timeout: 10 * time.Second},
+		config: config,
+		key:    key,
+		host:   host,
+	}, nil
+}
+
+// Name returns the provider identifier
+func (p *APNsProvider) Name() string {
+	return "apns"
+}
+
+// Ping checks that the APNs endpoint accepts connections.
+func (p *APNsProvider) Ping(ctx context.Context) error {
+	return dialHost(ctx, p.host)
+}
+
+// Send delivers a notification to a single APNs device token
+func (p *APNsProvider) Send(ctx context.Context, n Notification) (string, error) {
+	token, err := p.providerToken()
+	if err != nil {
+		return "", fmt.Errorf("apns: signing provider token: %w", err)
+	}
+
+	payload := map[string]interface{}{
+		"aps": map[string]interface{}{
+			"alert": map[string]string{
+				"title": n.Title,
+				"body":  n.Body,
+			},
+			"sound": "default",
+		},
+	}
+	for k, v := range n.Data {
+		payload[k] = v
+	}
+	if n.DeepLink != "" {
+		payload["deep_link"] = n.DeepLink
+	}
+
+	reqBody, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		p.host+"/3/device/"+n.Token, bytes.NewReader(reqBody))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("authorization", "bearer "+token)
+	req.Header.Set("apns-topic", p.config.Topic)
+	req.Header.Set("apns-push-type", "alert")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("apns: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return resp.Header.Get("apns-id"), nil
+	}
+
+	var apnsErr struct {
+		Reason string `json:"reason"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&apnsErr)
+
+	// 410 means the token is no longer active for the topic; BadDeviceToken and
+	// DeviceTokenNotForTopic mean it never will be.
+	if resp.StatusCode == http.StatusGone ||
+		apnsErr.Reason == "BadDeviceToken" || apnsErr.Reason == "DeviceTokenNotForTopic" {
+		return "", &InvalidTokenError{Provider: p.Name(), Reason: apnsErr.Reason}
+	}
+
+	return "", fmt.Errorf("apns: send failed with status %d: %s", resp.StatusCode, apnsErr.Reason)
+}
+
+// providerToken returns a cached ES256 provider token, re-signing it when it gets old.
+func (p *APNsProvider) providerToken() (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.jwt != "" && time.Since(p.issuedAt) < apnsTokenLifetime {
+		return p.jwt, nil
+	}
+
+	now := time.Now()
+	token, err := signJWT(
+		map[string]interface{}{"alg": "ES256", "kid": p.config.KeyID},
+		map[string]interface{}{"iss": p.config.TeamID, "iat": now.Unix()},
+		p.key,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	p.jwt = token
+	p.issuedAt = now
+	return p.jwt, nil
+}
@@ -0,0 +1,219 @@
+// services/push/fcm.go
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	fcmScope        = "https://www.googleapis.com/auth/firebase.messaging"
+	fcmSendURL      = "https://fcm.googleapis.com/v1/projects/%s/messages:send"
+	defaultTokenURI = "https://oauth2.googleapis.com/token"
+)
+
+// serviceAccount is the subset of a Google service account JSON key we need.
+type serviceAccount struct {
+	ProjectID   string `json:"project_id"`
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// FCMProvider sends pushes through the FCM HTTP v1 API.
+type FCMProvider struct {
+	client  *http.Client
+	account serviceAccount
+	key     crypto.Signer
+
+	mu          sync.Mutex
+	accessToken string
+	expiry      time.Time
+}
+
+// NewFCMProvider creates an FCM provider from a service account credentials file
+func NewFCMProvider(credentialsFile string) (*FCMProvider, error) {
+	raw, err := os.ReadFile(credentialsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var account serviceAccount
+	if err := json.Unmarshal(raw, &account); err != nil {
+		return nil, fmt.Errorf("invalid FCM credentials file: %w", err)
+	}
+	if account.ProjectID == "" || account.ClientEmail == "" || account.PrivateKey == "" {
+		return nil, errors.New("FCM credentials file is missing project_id, client_email or private_key")
+	}
+	if account.TokenURI == "" {
+		account.TokenURI = defaultTokenURI
+	}
+
+	key, err := parsePrivateKey([]byte(account.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("invalid FCM private key: %w", err)
+	}
+
+	return &FCMProvider{
+		client:  &http.Client{Timeout: 10 * time.Second},
+		account: account,
+		key:     key,
+	}, nil
+}
+
+// Name returns the provider identifier
+func (p *FCMProvider) Name() string {
+	return "fcm"
+}
+
+// Ping checks that the FCM endpoint accepts connections.
+func (p *FCMProvider) Ping(ctx context.Context) error {
+	return dialHost(ctx, "https://fcm.googleapis.com")
+}
+
+// Send delivers a notification to a single FCM registration token
+func (p *FCMProvider) Send(ctx context.Context, n Notification) (string, error) {
+	token, err := p.token(ctx)
+	if err != nil {
+		return "", fmt.Errorf("fcm: fetching access token: %w", err)
+	}
+
+	data := make(map[string]string, len(n.Data)+1)
+	for k, v := range n.Data {
+		data[k] = v
+	}
+	if n.DeepLink != "" {
+		data["deep_link"] = n.DeepLink
+	}
+
+	reqBody, err := json.Marshal(map[string]interface{}{
+		"message": map[string]interface{}{
+			"token": n.Token,
+			"notification": map[string]string{
+				"title": n.Title,
+				"body":  n.Body,
+			},
+			"data": data,
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		fmt.Sprintf(fcmSendURL, p.account.ProjectID), bytes.NewReader(reqBody))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("fcm: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode == http.StatusOK {
+		var result struct {
+			Name string `json:"name"` // projects/{project}/messages/{message_id}
+		}
+		if err := json.Unmarshal(respBody, &result); err != nil {
+			return "", fmt.Errorf("fcm: decoding response: %w", err)
+		}
+		return result.Name, nil
+	}
+
+	var fcmErr struct {
+		Error struct {
+			Status  string `json:"status"`
+			Message string `json:"message"`
+			Details []struct {
+				Type      string `json:"@type"`
+				ErrorCode string `json:"errorCode"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	_ = json.Unmarshal(respBody, &fcmErr)
+	for _, d := range fcmErr.Error.Details {
+		// UNREGISTERED: app uninstalled or token expired.
+		// SENDER_ID_MISMATCH: token belongs to a different Firebase project.
+		if d.ErrorCode == "UNREGISTERED" || d.ErrorCode == "SENDER_ID_MISMATCH" {
+			return "", &InvalidTokenError{Provider: p.Name(), Reason: d.ErrorCode}
+		}
+	}
+
+	return "", fmt.Errorf("fcm: send failed with status %d (%s): %s",
+		resp.StatusCode, fcmErr.Error.Status, fcmErr.Error.Message)
+}
+
+// token returns a cached OAuth2 access token, exchanging a signed JWT for a new one when needed.
+func (p *FCMProvider) token(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.accessToken != "" && time.Now().Before(p.expiry.Add(-time.Minute)) {
+		return p.accessToken, nil
+	}
+
+	now := time.Now()
+	assertion, err := signJWT(
+		map[string]interface{}{"alg": "RS256", "typ": "JWT"},
+		map[string]interface{}{
+			"iss":   p.account.ClientEmail,
+			"scope": fcmScope,
+			"aud":   p.account.TokenURI,
+			"iat":   now.Unix(),
+			"exp":   now.Add(time.Hour).Unix(),
+		},
+		p.key,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.account.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("token endpoint returned %d: %s", resp.StatusCode, body)
+	}
+
+	var result struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", err
+	}
+
+	p.accessToken = result.AccessToken
+	p.expiry = now.Add(time.Duration(result.ExpiresIn) * time.Second)
+	return p.accessToken, nil
+}
@@ -0,0 +1,133 @@
+// services/push/push.go
+package push
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+)
+
+// ErrInvalidToken is matched (via errors.Is) by errors returned when a provider reports
+// that a device token is permanently dead and should no longer be used.
+var ErrInvalidToken = errors.New("device token is no longer valid")
+
+// ErrProviderNotConfigured is returned for a push through a provider this service has no
+// credentials for. Retrying cannot succeed until the provider is configured.
+var ErrProviderNotConfigured = errors.New("push provider is not configured")
+
+// InvalidTokenError carries the provider's reason for rejecting a token.
+type InvalidTokenError struct {
+	Provider string
+	Reason   string // e.g. "UNREGISTERED" (FCM) or "Unregistered" (APNs)
+}
+
+func (e *InvalidTokenError) Error() string {
+	return fmt.Sprintf("%s: %s (%s)", e.Provider, ErrInvalidToken, e.Reason)
+}
+
+// Is makes errors.Is(err, ErrInvalidToken) succeed for InvalidTokenError.
+func (e *InvalidTokenError) Is(target error) bool {
+	return target == ErrInvalidToken
+}
+
+// Notification is a single push to a single device token.
+type Notification struct {
+	Token    string
+	Title    string
+	Body     string
+	DeepLink string
+	Data     map[string]string
+}
+
+// Provider sends pushes through one vendor (FCM, APNs).
+type Provider interface {
+	// Name returns the provider identifier stored alongside tokens, e.g. "fcm".
+	Name() string
+	// Send delivers the notification and returns the provider's message ID.
+	Send(ctx context.Context, n Notification) (string, error)
+}
+
+// Pinger is implemented by providers that can check they are reachable without sending.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Service routes pushes to the provider a token belongs to.
+type Service struct {
+	providers       map[string]Provider
+	defaultProvider string
+}
+
+// NewService creates a push service. defaultProvider is used for tokens whose
+// provider is unknown, e.g. tokens supplied directly in a NestJS message.
+func NewService(defaultProvider string, providers ...Provider) *Service {
+	s := &Service{
+		providers:       make(map[string]Provider),
+		defaultProvider: defaultProvider,
+	}
+	for _, p := range providers {
+		s.providers[p.Name()] = p
+	}
+	return s
+}
+
+// DefaultProvider returns the provider used when a token has none recorded.
+func (s *Service) DefaultProvider() string {
+	return s.defaultProvider
+}
+
+// Send delivers a notification through the named provider.
+func (s *Service) Send(ctx context.Context, provider string, n Notification) (string, error) {
+	if provider == "" {
+		provider = s.defaultProvider
+	}
+	p, ok := s.providers[provider]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrProviderNotConfigured, provider)
+	}
+	return p.Send(ctx, n)
+}
+
+// Providers returns the names of the configured providers.
+func (s *Service) Providers() []string {
+	names := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Ping checks that the named provider is reachable. Providers that cannot be
+// checked are assumed reachable.
+func (s *Service) Ping(ctx context.Context, provider string) error {
+	p, ok := s.providers[provider]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrProviderNotConfigured, provider)
+	}
+	if pinger, ok := p.(Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+// dialHost opens and closes a TCP connection to the host of an HTTPS endpoint.
+func dialHost(ctx context.Context, endpoint string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return err
+	}
+	port := u.Port()
+	if port == "" {
+		port = "443"
+	}
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", net.JoinHostPort(u.Hostname(), port))
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
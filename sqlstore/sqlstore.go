@@ -0,0 +1,81 @@
+// sqlstore/sqlstore.go
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib" // Registers the "pgx" driver
+	_ "modernc.org/sqlite"             // Registers the "sqlite" driver
+)
+
+// Supported SQL backends.
+const (
+	DriverSQLite   = "sqlite"
+	DriverPostgres = "postgres"
+)
+
+// DB is a database handle that knows which backend it talks to, so stores can keep
+// one set of queries written with ? placeholders and pick per-backend DDL.
+type DB struct {
+	*sql.DB
+	Driver string
+}
+
+// Open connects to a database. driver is "sqlite" (dsn is a file path or file: URI)
+// or "postgres" (dsn is a connection URL).
+func Open(driver, dsn string) (*DB, error) {
+	sqlDriver := "sqlite"
+	switch driver {
+	case DriverSQLite:
+	case DriverPostgres:
+		sqlDriver = "pgx"
+	default:
+		return nil, fmt.Errorf("unknown database driver %q (expected %q or %q)", driver, DriverSQLite, DriverPostgres)
+	}
+
+	db, err := sql.Open(sqlDriver, dsn)
+	if err != nil {
+		return nil, err
+	}
+	if driver == DriverSQLite {
+		// SQLite allows a single writer; serialising avoids SQLITE_BUSY under load.
+		db.SetMaxOpenConns(1)
+	}
+	return &DB{DB: db, Driver: driver}, nil
+}
+
+// Migrate runs the statements, which must be idempotent (CREATE ... IF NOT EXISTS).
+func (db *DB) Migrate(statements []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	for _, stmt := range statements {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Rebind converts ? placeholders to $1, $2, ... for Postgres.
+func (db *DB) Rebind(query string) string {
+	if db.Driver != DriverPostgres {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,146 @@
+// tokens/sql.go
+package tokens
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"notification-service/sqlstore"
+)
+
+// Timestamps are stored as Unix milliseconds so the same queries work on both backends.
+var schema = map[string][]string{
+	sqlstore.DriverSQLite: {
+		`CREATE TABLE IF NOT EXISTS device_tokens (
+			token TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL,
+			provider TEXT NOT NULL,
+			platform_type TEXT NOT NULL DEFAULT '',
+			disabled INTEGER NOT NULL DEFAULT 0,
+			disabled_reason TEXT NOT NULL DEFAULT '',
+			created_at INTEGER NOT NULL,
+			updated_at INTEGER NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_device_tokens_user ON device_tokens (user_id, created_at)`,
+	},
+	sqlstore.DriverPostgres: {
+		`CREATE TABLE IF NOT EXISTS device_tokens (
+			token TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL,
+			provider TEXT NOT NULL,
+			platform_type TEXT NOT NULL DEFAULT '',
+			disabled INTEGER NOT NULL DEFAULT 0,
+			disabled_reason TEXT NOT NULL DEFAULT '',
+			created_at BIGINT NOT NULL,
+			updated_at BIGINT NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_device_tokens_user ON device_tokens (user_id, created_at)`,
+	},
+}
+
+const tokenColumns = `token, user_id, provider, platform_type, disabled, disabled_reason, created_at, updated_at`
+
+// SQLStore is a Store backed by SQLite or Postgres, so registered and disabled
+// tokens survive restarts.
+type SQLStore struct {
+	db *sqlstore.DB
+}
+
+// NewSQLStore creates the token table in db if needed
+func NewSQLStore(db *sqlstore.DB) (*SQLStore, error) {
+	if err := db.Migrate(schema[db.Driver]); err != nil {
+		return nil, fmt.Errorf("creating device token schema: %w", err)
+	}
+	return &SQLStore{db: db}, nil
+}
+
+// Register adds or re-enables a token for a user
+func (s *SQLStore) Register(t Token) (Token, error) {
+	if t.Provider == "" {
+		t.Provider = ProviderFCM
+	}
+	now := time.Now().UTC().UnixMilli()
+	row := s.db.QueryRowContext(context.Background(), s.db.Rebind(`INSERT INTO device_tokens (`+tokenColumns+`)
+		VALUES (?, ?, ?, ?, 0, '', ?, ?)
+		ON CONFLICT (token) DO UPDATE SET user_id = excluded.user_id, provider = excluded.provider,
+			platform_type = excluded.platform_type, disabled = 0, disabled_reason = '', updated_at = excluded.updated_at
+		RETURNING `+tokenColumns),
+		t.Token, t.UserID, t.Provider, t.PlatformType, now, now)
+	return scanToken(row)
+}
+
+// Unregister removes a user's token
+func (s *SQLStore) Unregister(userID, token string) error {
+	result, err := s.db.ExecContext(context.Background(), s.db.Rebind(
+		`DELETE FROM device_tokens WHERE token = ? AND user_id = ?`), token, userID)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Get looks up a single token
+func (s *SQLStore) Get(token string) (Token, error) {
+	row := s.db.QueryRowContext(context.Background(), s.db.Rebind(
+		`SELECT `+tokenColumns+` FROM device_tokens WHERE token = ?`), token)
+	t, err := scanToken(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Token{}, ErrNotFound
+	}
+	return t, err
+}
+
+// ListByUser returns all tokens for a user, oldest first
+func (s *SQLStore) ListByUser(userID string) ([]Token, error) {
+	rows, err := s.db.QueryContext(context.Background(), s.db.Rebind(
+		`SELECT `+tokenColumns+` FROM device_tokens WHERE user_id = ? ORDER BY created_at, token`), userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []Token
+	for rows.Next() {
+		t, err := scanToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, t)
+	}
+	return result, rows.Err()
+}
+
+// Disable marks a token as dead
+func (s *SQLStore) Disable(userID, token, provider, reason string) (Token, error) {
+	now := time.Now().UTC().UnixMilli()
+	row := s.db.QueryRowContext(context.Background(), s.db.Rebind(`INSERT INTO device_tokens (`+tokenColumns+`)
+		VALUES (?, ?, ?, '', 1, ?, ?, ?)
+		ON CONFLICT (token) DO UPDATE SET disabled = 1, disabled_reason = excluded.disabled_reason,
+			updated_at = excluded.updated_at
+		RETURNING `+tokenColumns),
+		token, userID, provider, reason, now, now)
+	return scanToken(row)
+}
+
+// scanToken reads a row selected with tokenColumns.
+func scanToken(row interface{ Scan(...any) error }) (Token, error) {
+	var t Token
+	var disabled int
+	var createdAt, updatedAt int64
+	err := row.Scan(&t.Token, &t.UserID, &t.Provider, &t.PlatformType, &disabled, &t.DisabledReason, &createdAt, &updatedAt)
+	if err != nil {
+		return Token{}, err
+	}
+	t.Disabled = disabled != 0
+	t.CreatedAt = time.UnixMilli(createdAt).UTC()
+	t.UpdatedAt = time.UnixMilli(updatedAt).UTC()
+	return t, nil
+}
@@ -0,0 +1,81 @@
+// tokens/sql_test.go
+package tokens
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"notification-service/sqlstore"
+)
+
+func openTestStore(t *testing.T) *SQLStore {
+	t.Helper()
+	db, err := sqlstore.Open(sqlstore.DriverSQLite, filepath.Join(t.TempDir(), "tokens.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	store, err := NewSQLStore(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func TestSQLStoreLifecycle(t *testing.T) {
+	store := openTestStore(t)
+
+	registered, err := store.Register(Token{UserID: "u1", Token: "tok", PlatformType: "ios"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if registered.Provider != ProviderFCM || registered.CreatedAt.IsZero() {
+		t.Fatalf("Register() = %+v, want default provider and a creation time", registered)
+	}
+
+	disabled, err := store.Disable("u1", "tok", ProviderFCM, "UNREGISTERED")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !disabled.Disabled || disabled.DisabledReason != "UNREGISTERED" || disabled.PlatformType != "ios" {
+		t.Fatalf("Disable() = %+v, want the registered token disabled", disabled)
+	}
+
+	// Registering again moves the token to the new owner and re-enables it.
+	moved, err := store.Register(Token{UserID: "u2", Token: "tok"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if moved.UserID != "u2" || moved.Disabled || !moved.CreatedAt.Equal(registered.CreatedAt) {
+		t.Fatalf("Register() again = %+v, want an enabled token owned by u2 with the original creation time", moved)
+	}
+
+	if list, err := store.ListByUser("u1"); err != nil || len(list) != 0 {
+		t.Fatalf("ListByUser(u1) = %v, %v; want no tokens", list, err)
+	}
+	if err := store.Unregister("u1", "tok"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Unregister by a non-owner = %v, want ErrNotFound", err)
+	}
+	if err := store.Unregister("u2", "tok"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get("tok"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get after Unregister = %v, want ErrNotFound", err)
+	}
+}
+
+func TestSQLStoreDisableUnknownToken(t *testing.T) {
+	store := openTestStore(t)
+
+	if _, err := store.Disable("u1", "dead", ProviderAPNs, "410"); err != nil {
+		t.Fatal(err)
+	}
+	got, err := store.Get("dead")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Disabled || got.UserID != "u1" || got.Provider != ProviderAPNs {
+		t.Fatalf("Get() = %+v, want a disabled APNs token for u1", got)
+	}
+}
@@ -0,0 +1,50 @@
+// tokens/store.go
+package tokens
+
+import (
+	"errors"
+	"time"
+)
+
+// Push providers a token can belong to.
+const (
+	ProviderFCM  = "fcm"
+	ProviderAPNs = "apns"
+)
+
+// ValidProvider reports whether provider is one a token can belong to. Empty is
+// valid and means ProviderFCM.
+func ValidProvider(provider string) bool {
+	return provider == "" || provider == ProviderFCM || provider == ProviderAPNs
+}
+
+// ErrNotFound is returned when a token is not in the store.
+var ErrNotFound = errors.New("device token not found")
+
+// Token is a push token registered for a user.
+type Token struct {
+	UserID         string    `json:"user_id"`
+	Token          string    `json:"device_token"`
+	Provider       string    `json:"provider"`
+	PlatformType   string    `json:"platform_type,omitempty"`
+	Disabled       bool      `json:"disabled"`
+	DisabledReason string    `json:"disabled_reason,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// Store keeps track of device tokens per user.
+// A token belongs to at most one user; registering it again moves it to the new owner.
+type Store interface {
+	// Register adds or re-enables a token for a user.
+	Register(t Token) (Token, error)
+	// Unregister removes a user's token. It returns ErrNotFound if the user does not own it.
+	Unregister(userID, token string) error
+	// Get looks up a single token.
+	Get(token string) (Token, error)
+	// ListByUser returns all tokens for a user, including disabled ones.
+	ListByUser(userID string) ([]Token, error)
+	// Disable marks a token as dead so it is no longer used for delivery.
+	// Unknown tokens are recorded as disabled so repeated messages carrying them are skipped.
+	Disable(userID, token, provider, reason string) (Token, error)
+}
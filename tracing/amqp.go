@@ -0,0 +1,51 @@
+// tracing/amqp.go
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+)
+
+// HeaderCarrier adapts AMQP message headers to a propagation.TextMapCarrier,
+// so traceparent and tracestate travel with the message.
+type HeaderCarrier map[string]interface{}
+
+// Get returns the header as a string, or "" if it is missing or not a string.
+func (c HeaderCarrier) Get(key string) string {
+	switch v := c[key].(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	}
+	return ""
+}
+
+// Set stores the header value.
+func (c HeaderCarrier) Set(key, value string) {
+	c[key] = value
+}
+
+// Keys lists the header names.
+func (c HeaderCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Extract returns ctx carrying the remote span context found in the AMQP headers.
+func Extract(ctx context.Context, headers map[string]interface{}) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, HeaderCarrier(headers))
+}
+
+// Inject writes the span context of ctx into the AMQP headers, allocating them if needed.
+func Inject(ctx context.Context, headers map[string]interface{}) map[string]interface{} {
+	if headers == nil {
+		headers = make(map[string]interface{})
+	}
+	otel.GetTextMapPropagator().Inject(ctx, HeaderCarrier(headers))
+	return headers
+}
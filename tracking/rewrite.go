@@ -0,0 +1,60 @@
+// tracking/rewrite.go
+package tracking
+
+import (
+	"html"
+	"regexp"
+	"strings"
+
+	"notification-service/models"
+)
+
+var (
+	hrefPattern = regexp.MustCompile(`(?i)(<a\b[^>]*?\bhref\s*=\s*)(["'])(.*?)(["'])`)
+	// Trailing punctuation is left out so "see https://example.org." keeps its full stop.
+	urlPattern = regexp.MustCompile(`https?://[^\s<>"']*[^\s<>"'.,;:!?)]`)
+)
+
+// RewriteHTML routes every http(s) link in an HTML body, and links to the payload's
+// deep link whatever its scheme, through the click redirect and adds the open-tracking pixel before </body>.
+func (t *Tracker) RewriteHTML(body string, msg models.NotificationMessage) string {
+	body = hrefPattern.ReplaceAllStringFunc(body, func(match string) string {
+		parts := hrefPattern.FindStringSubmatch(match)
+		target := html.UnescapeString(parts[3])
+		if !trackable(target) && target != msg.Payload.DeepLink {
+			return match
+		}
+		return parts[1] + parts[2] + html.EscapeString(t.ClickURL(msg, target)) + parts[4]
+	})
+
+	pixel := `<img src="` + html.EscapeString(t.PixelURL(msg)) + `" width="1" height="1" alt="" style="display: none;">`
+	if i := strings.LastIndex(strings.ToLower(body), "</body>"); i >= 0 {
+		return body[:i] + pixel + body[i:]
+	}
+	return body + pixel
+}
+
+// RewriteText routes every http(s) URL in a plain text body, and the payload's deep
+// link whatever its scheme, through the click redirect.
+func (t *Tracker) RewriteText(body string, msg models.NotificationMessage) string {
+	body = urlPattern.ReplaceAllStringFunc(body, func(target string) string {
+		if !trackable(target) {
+			return target
+		}
+		return t.ClickURL(msg, target)
+	})
+	if deepLink := msg.Payload.DeepLink; deepLink != "" && !trackable(deepLink) {
+		body = strings.ReplaceAll(body, deepLink, t.ClickURL(msg, deepLink))
+	}
+	return body
+}
+
+// trackable reports whether a link should be rewritten: only web links, and never
+// links back into this service (unsubscribe, tracking).
+func trackable(target string) bool {
+	lower := strings.ToLower(target)
+	if !strings.HasPrefix(lower, "http://") && !strings.HasPrefix(lower, "https://") {
+		return false
+	}
+	return !strings.Contains(lower, "/v1/unsubscribe") && !strings.Contains(lower, "/v1/track/")
+}
@@ -0,0 +1,99 @@
+// tracking/rewrite_test.go
+package tracking
+
+import (
+	"html"
+	"strings"
+	"testing"
+)
+
+func TestRewriteHTML(t *testing.T) {
+	tracker := newTestTracker(t)
+	msg := testMessage
+	msg.Payload.DeepLink = "volhub://opportunities/7"
+
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "web link",
+			body: `<a class="btn" href="https://volhub.example.org/o/7?a=1&amp;b=2">View</a>`,
+			want: `<a class="btn" href="` + html.EscapeString(tracker.ClickURL(msg, "https://volhub.example.org/o/7?a=1&b=2")) + `">View</a>`,
+		},
+		{
+			name: "deep link",
+			body: `<a href='volhub://opportunities/7'>Open the app</a>`,
+			want: `<a href='` + html.EscapeString(tracker.ClickURL(msg, "volhub://opportunities/7")) + `'>Open the app</a>`,
+		},
+		{
+			name: "unsubscribe link",
+			body: `<a href="https://notify.example.org/v1/unsubscribe?token=abc">Unsubscribe</a>`,
+			want: `<a href="https://notify.example.org/v1/unsubscribe?token=abc">Unsubscribe</a>`,
+		},
+		{
+			name: "tracking link",
+			body: `<a href="https://notify.example.org/v1/track/click/abc.def">Already tracked</a>`,
+			want: `<a href="https://notify.example.org/v1/track/click/abc.def">Already tracked</a>`,
+		},
+		{
+			name: "mailto link",
+			body: `<a href="mailto:help@example.org">Help</a>`,
+			want: `<a href="mailto:help@example.org">Help</a>`,
+		},
+	}
+	pixel := `<img src="` + html.EscapeString(tracker.PixelURL(msg)) + `" width="1" height="1" alt="" style="display: none;">`
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tracker.RewriteHTML(tt.body, msg); got != tt.want+pixel {
+				t.Errorf("RewriteHTML() =\n%s\nwant\n%s", got, tt.want+pixel)
+			}
+		})
+	}
+
+	got := tracker.RewriteHTML("<html><BODY><p>Hi</p></BODY></html>", msg)
+	if want := "<html><BODY><p>Hi</p>" + pixel + "</BODY></html>"; got != want {
+		t.Errorf("RewriteHTML() = %s, want the pixel before </body>", got)
+	}
+}
+
+func TestRewriteText(t *testing.T) {
+	tracker := newTestTracker(t)
+	msg := testMessage
+	msg.Payload.DeepLink = "volhub://opportunities/7"
+	click := func(target string) string { return tracker.ClickURL(msg, target) }
+
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "trailing punctuation is kept",
+			body: "See https://volhub.example.org/o/7. Or (https://volhub.example.org/faq)!",
+			want: "See " + click("https://volhub.example.org/o/7") + ". Or (" + click("https://volhub.example.org/faq") + ")!",
+		},
+		{
+			name: "deep link",
+			body: "Open volhub://opportunities/7 in the app",
+			want: "Open " + click("volhub://opportunities/7") + " in the app",
+		},
+		{
+			name: "unsubscribe and tracking links",
+			body: "Unsubscribe: https://notify.example.org/v1/unsubscribe?token=abc\nhttps://notify.example.org/v1/track/click/abc.def",
+			want: "Unsubscribe: https://notify.example.org/v1/unsubscribe?token=abc\nhttps://notify.example.org/v1/track/click/abc.def",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tracker.RewriteText(tt.body, msg); got != tt.want {
+				t.Errorf("RewriteText() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+
+	if got := tracker.RewriteText("No links here.", msg); strings.Contains(got, ClickPath) {
+		t.Errorf("RewriteText() = %s, want the body unchanged", got)
+	}
+}
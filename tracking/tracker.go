@@ -0,0 +1,106 @@
+// tracking/tracker.go
+package tracking
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"notification-service/models"
+)
+
+// Paths the HTTP API serves tracking requests on; the signed token follows the path.
+const (
+	OpenPath  = "/v1/track/open/"
+	ClickPath = "/v1/track/click/"
+)
+
+// ErrInvalidToken is returned for tracking tokens that are malformed or carry a bad signature.
+var ErrInvalidToken = errors.New("invalid tracking token")
+
+// Claims identify the email an open or click belongs to. URL is set for clicks only.
+type Claims struct {
+	EventID          string `json:"e,omitempty"`
+	NotificationType string `json:"t"`
+	UserID           string `json:"u"`
+	ApplicationID    int    `json:"a,omitempty"`
+	OpportunityID    int    `json:"o,omitempty"`
+	URL              string `json:"l,omitempty"`
+}
+
+// Tracker builds signed pixel and redirect URLs and verifies them when they are hit.
+// Links are signed so the redirect endpoint can never be used as an open redirect.
+type Tracker struct {
+	key     []byte
+	baseURL string
+}
+
+// NewTracker creates a tracker for the API's public base URL (e.g. https://notify.example.org)
+func NewTracker(secret []byte, baseURL string) (*Tracker, error) {
+	if len(secret) < 16 {
+		return nil, errors.New("tracking secret must be at least 16 bytes")
+	}
+	return &Tracker{
+		key:     secret,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}, nil
+}
+
+// PixelURL returns the URL of the open-tracking pixel for a message
+func (t *Tracker) PixelURL(msg models.NotificationMessage) string {
+	return t.baseURL + OpenPath + t.sign(claimsFor(msg, "")) + ".gif"
+}
+
+// ClickURL returns a redirect URL that records a click and forwards to target
+func (t *Tracker) ClickURL(msg models.NotificationMessage, target string) string {
+	return t.baseURL + ClickPath + t.sign(claimsFor(msg, target))
+}
+
+// Verify checks a token's signature and returns its claims. A ".gif" suffix is ignored.
+func (t *Tracker) Verify(token string) (Claims, error) {
+	token = strings.TrimSuffix(token, ".gif")
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return Claims{}, ErrInvalidToken
+	}
+	got, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(got, t.mac(encoded)) {
+		return Claims{}, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return Claims{}, ErrInvalidToken
+	}
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.UserID == "" {
+		return Claims{}, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+func (t *Tracker) sign(claims Claims) string {
+	payload, _ := json.Marshal(claims)
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(t.mac(encoded))
+}
+
+func (t *Tracker) mac(encoded string) []byte {
+	m := hmac.New(sha256.New, t.key)
+	m.Write([]byte(encoded))
+	return m.Sum(nil)
+}
+
+func claimsFor(msg models.NotificationMessage, url string) Claims {
+	return Claims{
+		EventID:          msg.EventID,
+		NotificationType: msg.NotificationType,
+		UserID:           msg.Recipient.UserID,
+		ApplicationID:    msg.Payload.ApplicationID,
+		OpportunityID:    msg.Payload.OpportunityID,
+		URL:              url,
+	}
+}
@@ -0,0 +1,72 @@
+// tracking/tracker_test.go
+package tracking
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"notification-service/models"
+)
+
+func newTestTracker(t *testing.T) *Tracker {
+	t.Helper()
+	tracker, err := NewTracker([]byte("0123456789abcdef"), "https://notify.example.org/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tracker
+}
+
+var testMessage = models.NotificationMessage{
+	NotificationType: "VOLUNTEER_NEW_MATCHING_OPPORTUNITY",
+	EventID:          "e1",
+	Recipient:        models.Recipient{UserID: "u1"},
+	Payload:          models.Payload{OpportunityID: 7},
+}
+
+func TestTrackerVerify(t *testing.T) {
+	tracker := newTestTracker(t)
+	click := strings.TrimPrefix(tracker.ClickURL(testMessage, "https://volhub.example.org/o/7"), "https://notify.example.org"+ClickPath)
+	pixel := strings.TrimPrefix(tracker.PixelURL(testMessage), "https://notify.example.org"+OpenPath)
+	encoded, signature, _ := strings.Cut(click, ".")
+
+	// An attacker swapping in their own URL must not get a valid redirect.
+	forged, _ := json.Marshal(Claims{NotificationType: "T", UserID: "u1", URL: "https://evil.example.com"})
+	other, _ := NewTracker([]byte("fedcba9876543210"), "https://notify.example.org")
+	otherClick := strings.TrimPrefix(other.ClickURL(testMessage, "https://evil.example.com"), "https://notify.example.org"+ClickPath)
+
+	tests := []struct {
+		name    string
+		token   string
+		wantURL string
+		wantErr error
+	}{
+		{name: "click", token: click, wantURL: "https://volhub.example.org/o/7"},
+		{name: "pixel with .gif suffix", token: pixel},
+		{name: "click with .gif suffix", token: click + ".gif", wantURL: "https://volhub.example.org/o/7"},
+		{name: "tampered payload", token: base64.RawURLEncoding.EncodeToString(forged) + "." + signature, wantErr: ErrInvalidToken},
+		{name: "tampered signature", token: encoded + "." + strings.Repeat("A", len(signature)), wantErr: ErrInvalidToken},
+		{name: "signed with another secret", token: otherClick, wantErr: ErrInvalidToken},
+		{name: "unsigned", token: base64.RawURLEncoding.EncodeToString(forged), wantErr: ErrInvalidToken},
+		{name: "unsigned with empty signature", token: base64.RawURLEncoding.EncodeToString(forged) + ".", wantErr: ErrInvalidToken},
+		{name: "plain URL", token: "https://evil.example.com", wantErr: ErrInvalidToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := tracker.Verify(tt.token)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			want := Claims{EventID: "e1", NotificationType: "VOLUNTEER_NEW_MATCHING_OPPORTUNITY", UserID: "u1", OpportunityID: 7, URL: tt.wantURL}
+			if claims != want {
+				t.Errorf("Verify() = %+v, want %+v", claims, want)
+			}
+		})
+	}
+}
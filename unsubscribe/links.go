@@ -0,0 +1,37 @@
+// unsubscribe/links.go
+package unsubscribe
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Path is where the HTTP API serves unsubscribe requests.
+const Path = "/v1/unsubscribe"
+
+// Links builds unsubscribe URLs and RFC 8058 headers for outgoing email.
+type Links struct {
+	signer  *Signer
+	baseURL string
+}
+
+// NewLinks creates a link builder for the API's public base URL (e.g. https://notify.example.org)
+func NewLinks(signer *Signer, baseURL string) *Links {
+	return &Links{
+		signer:  signer,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+// URL returns a signed one-click unsubscribe URL
+func (l *Links) URL(userID, notificationType, channel string) string {
+	return l.baseURL + Path + "?token=" + url.QueryEscape(l.signer.Sign(userID, notificationType, channel))
+}
+
+// Headers returns the List-Unsubscribe and List-Unsubscribe-Post headers for an unsubscribe URL
+func Headers(unsubscribeURL string) map[string]string {
+	return map[string]string{
+		"List-Unsubscribe":      "<" + unsubscribeURL + ">",
+		"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+	}
+}
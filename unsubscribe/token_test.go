@@ -0,0 +1,70 @@
+// unsubscribe/token_test.go
+package unsubscribe
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testSecret = []byte("0123456789abcdef")
+
+func TestNewSignerRejectsShortSecret(t *testing.T) {
+	if _, err := NewSigner([]byte("0123456789abcde"), time.Hour); err == nil {
+		t.Error("NewSigner() with a 15 byte secret succeeded, want an error")
+	}
+	if _, err := NewSigner(testSecret, time.Hour); err != nil {
+		t.Errorf("NewSigner() with a 16 byte secret error = %v", err)
+	}
+}
+
+func TestSignerVerify(t *testing.T) {
+	signer, _ := NewSigner(testSecret, time.Hour)
+	expired, _ := NewSigner(testSecret, -time.Minute)
+	other, _ := NewSigner([]byte("fedcba9876543210"), time.Hour)
+	token := signer.Sign("u1", "VOLUNTEER_NEW_MATCHING_OPPORTUNITY", "email")
+	encoded, signature, _ := strings.Cut(token, ".")
+
+	forged, _ := json.Marshal(Claims{UserID: "u2", NotificationType: "VOLUNTEER_NEW_MATCHING_OPPORTUNITY", Channel: "email", ExpiresAt: time.Now().Add(time.Hour).Unix()})
+	flipped := []byte(signature)
+	if flipped[0] == 'A' {
+		flipped[0] = 'B'
+	} else {
+		flipped[0] = 'A'
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{name: "valid", token: token},
+		{name: "tampered payload", token: base64.RawURLEncoding.EncodeToString(forged) + "." + signature, wantErr: ErrInvalidToken},
+		{name: "tampered signature", token: encoded + "." + string(flipped), wantErr: ErrInvalidToken},
+		{name: "signed with another secret", token: other.Sign("u1", "", "email"), wantErr: ErrInvalidToken},
+		{name: "expired", token: expired.Sign("u1", "", "email"), wantErr: ErrExpiredToken},
+		{name: "missing separator", token: encoded + signature, wantErr: ErrInvalidToken},
+		{name: "empty", token: "", wantErr: ErrInvalidToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := signer.Verify(tt.token)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			want := Claims{UserID: "u1", NotificationType: "VOLUNTEER_NEW_MATCHING_OPPORTUNITY", Channel: "email", ExpiresAt: claims.ExpiresAt}
+			if claims != want {
+				t.Errorf("Verify() = %+v, want %+v", claims, want)
+			}
+			if until := time.Until(time.Unix(claims.ExpiresAt, 0)); until <= 0 || until > time.Hour {
+				t.Errorf("token expires in %s, want within the signer's ttl", until)
+			}
+		})
+	}
+}
@@ -1,7 +1,13 @@
 // utility.go
 package main
 
-import "log"
+import (
+	"log"
+	"os"
+	"strings"
+
+	"notification-service/constants"
+)
 
 // handleErrorMessage is a helper function to log fatal errors.
 func handleErrorMessage(err error, msg string) {
@@ -9,3 +15,40 @@ func handleErrorMessage(err error, msg string) {
 		log.Fatalf("%s: %s", msg, err)
 	}
 }
+
+// getEnv returns the value of an environment variable, or fallback if it is unset.
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// parseKeyValues parses "key=value,key=value" into a map, ignoring malformed pairs.
+func parseKeyValues(raw string) map[string]string {
+	result := make(map[string]string)
+	for _, pair := range strings.Split(raw, ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		result[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return result
+}
+
+// splitList parses a comma separated list, dropping empty entries.
+func splitList(raw string) []string {
+	var result []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// publicBaseURL is the externally reachable URL of the HTTP API, used in links placed in emails.
+func publicBaseURL() string {
+	return getEnv("PUBLIC_BASE_URL", "http://localhost"+getEnv("HTTP_ADDR", constants.DefaultHTTPAddr))
+}